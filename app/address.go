@@ -0,0 +1,41 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// AddressInfo 地址校验结果
+type AddressInfo struct {
+	Address     string `json:"address"`
+	Valid       bool   `json:"valid"`
+	Checksummed string `json:"checksummed,omitempty"`
+}
+
+// verifyAddress 校验地址格式，大小写混合的地址需要满足 EIP-55 校验和，合法时返回校验和格式
+func (g *Server) verifyAddress(c *gin.Context) {
+	address := c.Query("address")
+	info := &AddressInfo{Address: address}
+	if IsValidEthereumAddress(address) {
+		checksummed := common.HexToAddress(address).Hex()
+		if isChecksumValid(address, checksummed) {
+			info.Valid = true
+			info.Checksummed = checksummed
+		}
+	}
+
+	global.Result(global.SuccessDetail(info), c)
+}
+
+// isChecksumValid 全小写或全大写的地址不携带校验和，直接通过
+func isChecksumValid(address string, checksummed string) bool {
+	hex := address[2:]
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return true
+	}
+	return address == checksummed
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestVerifyAddress(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	const checksummed = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	// 校验和错误：将一个大写字母改为小写
+	badChecksum := strings.Replace(checksummed, "F3E", "f3E", 1)
+
+	for _, tc := range []struct {
+		address string
+		valid   bool
+	}{
+		{checksummed, true},
+		{strings.ToLower(checksummed), true},
+		{"0x" + strings.ToUpper(checksummed[2:]), true},
+		{badChecksum, false},
+		{"0x1234", false},
+		{"", false},
+	} {
+		res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/verifyAddress?address="+tc.address, nil, nil))
+		if res.Code != global.SUCCESS {
+			t.Fatalf("%q: verify failed: %d %s", tc.address, res.Code, res.Msg)
+		}
+		info := &AddressInfo{}
+		decodeDetail(t, res, info)
+		if info.Valid != tc.valid {
+			t.Fatalf("%q: expect valid %v, got %v", tc.address, tc.valid, info.Valid)
+		}
+		if tc.valid && info.Checksummed != checksummed {
+			t.Fatalf("%q: expect checksummed %s, got %s", tc.address, checksummed, info.Checksummed)
+		}
+		if !tc.valid && info.Checksummed != "" {
+			t.Fatalf("%q: invalid address should not be checksummed, got %s", tc.address, info.Checksummed)
+		}
+	}
+}
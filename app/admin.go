@@ -0,0 +1,204 @@
+package app
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/pkg/audit"
+)
+
+// 管理接口单次查询领取记录的最大条数
+const maxAdminClaimsLimit = 500
+
+// adminClaimRecord 管理接口返回的领取记录，附带地址备注
+type adminClaimRecord struct {
+	*formattedClaimRecord
+	Note *internal.AddressNote `json:"note,omitempty"`
+}
+
+type overviewCache struct {
+	lock     sync.Mutex
+	overview *internal.Overview
+	expireAt time.Time
+}
+
+// AdminAuth 校验管理接口的 Authorization: Bearer token
+func (g *Server) AdminAuth() func(c *gin.Context) {
+	token := []byte(g.config.Admin.Token)
+	return func(c *gin.Context) {
+		auth := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), token) != 1 {
+			g.requestLogger(c).Warnf("unauthorized admin request from %s", c.ClientIP())
+			global.Result(global.Fail(global.UnauthorizedCode, global.UnauthorizedMsg), c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (g *Server) adminOverview(c *gin.Context) {
+	g.overview.lock.Lock()
+	defer g.overview.lock.Unlock()
+	if g.overview.overview == nil || time.Now().After(g.overview.expireAt) {
+		overview, err := g.client.Overview()
+		if err != nil {
+			g.requestLogger(c).Error(err)
+			global.Result(global.Fail(global.BlockChainCode, global.BlockChainMsg), c)
+			return
+		}
+		g.overview.overview = overview
+		g.overview.expireAt = time.Now().Add(g.config.Admin.OverviewCacheTTL.ToDuration())
+	}
+
+	global.Result(global.SuccessDetail(g.overview.overview), c)
+}
+
+func (g *Server) adminClaims(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > maxAdminClaimsLimit {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+
+	net := g.config.Axiom.TestNetName
+	records := g.client.RecentClaims(net, limit)
+	format := g.amountFormat(c)
+	claims := make([]*adminClaimRecord, 0, len(records))
+	for _, record := range records {
+		claims = append(claims, &adminClaimRecord{
+			formattedClaimRecord: &formattedClaimRecord{ClaimRecord: record, Amount: formatAmount(record.Amount, format)},
+			Note:                 g.client.GetAddressNote(net, record.Address),
+		})
+	}
+	global.Result(global.SuccessDetail(claims), c)
+}
+
+// adminClaim 管理员指定数量领取，数量不超过 admin.max_amount
+func (g *Server) adminClaim(c *gin.Context) {
+	var adminClaimReq global.AdminClaimReq
+	if !bindJSON(c, &adminClaimReq) {
+		return
+	}
+
+	if judge := IsValidEthereumAddress(adminClaimReq.Address); !judge {
+		global.Result(global.Fail(global.ErrAddrCode, global.ErrAddrMsg+adminClaimReq.Address), c)
+		return
+	}
+
+	net, ok := g.canonicalNet(adminClaimReq.Net)
+	if !ok {
+		global.Result(g.unsupportedNet(adminClaimReq.Net), c)
+		return
+	}
+	adminClaimReq.Net = net
+
+	if adminClaimReq.Amount <= 0 {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+	amount := adminClaimAmount(adminClaimReq.Amount, g.config.Admin.MaxAmount)
+
+	txHash, code, err := g.client.AdminClaim(g.requestContext(c), adminClaimReq.Net, adminClaimReq.Address, amount, adminClaimReq.Source)
+	if !errors.Is(err, internal.ErrAddressLocked) {
+		internal.DeleteTxData(g.client, strings.ToLower(adminClaimReq.Address), global.NativeToken, adminClaimReq.Net)
+	}
+	if err != nil {
+		global.Result(global.Fail(code, err.Error()), c)
+		return
+	}
+	g.requestLogger(c).Infof("admin claim %v for %s, tx: %s", amount, adminClaimReq.Address, txHash)
+	g.client.Audit(g.requestContext(c), &audit.Event{Type: audit.EventAdminAction, Net: adminClaimReq.Net, Address: strings.ToLower(adminClaimReq.Address), TxHash: txHash, Amount: amount, Action: "claim"})
+	global.Result(g.claimSuccess(txHash, adminClaimReq.Net, adminClaimReq.Address), c)
+}
+
+// rotateFundingKey 运行时替换资金账户私钥，请求体中的私钥不会写入日志和审计记录
+func (g *Server) rotateFundingKey(c *gin.Context) {
+	var rotateKeyReq global.RotateKeyReq
+	if !bindJSON(c, &rotateKeyReq) {
+		return
+	}
+
+	net, ok := g.canonicalNet(rotateKeyReq.Net)
+	if !ok {
+		global.Result(g.unsupportedNet(rotateKeyReq.Net), c)
+		return
+	}
+
+	address, code, err := g.client.RotateFundingKey(rotateKeyReq.Key)
+	if err != nil {
+		g.requestLogger(c).Warnf("rotate funding key failed: %v", err)
+		global.Result(global.Fail(code, err.Error()), c)
+		return
+	}
+	g.client.Audit(g.requestContext(c), &audit.Event{Type: audit.EventAdminAction, Net: net, Address: strings.ToLower(address.Hex()), Action: "rotate_key"})
+	global.Result(global.SuccessDetail(address.Hex()), c)
+}
+
+// adminClaimAmount 将管理员指定的数量限制在 maxAmount 以内，maxAmount 为 0 时不限制
+func adminClaimAmount(amount float64, maxAmount float64) float64 {
+	if maxAmount > 0 && amount > maxAmount {
+		return maxAmount
+	}
+	return amount
+}
+
+func (g *Server) getAddressNote(c *gin.Context) {
+	address, ok := g.adminAddress(c)
+	if !ok {
+		return
+	}
+
+	global.Result(global.SuccessDetail(g.client.GetAddressNote(g.config.Axiom.TestNetName, address)), c)
+}
+
+func (g *Server) setAddressNote(c *gin.Context) {
+	address, ok := g.adminAddress(c)
+	if !ok {
+		return
+	}
+	var noteReq global.AddressNoteReq
+	if !bindJSON(c, &noteReq) {
+		return
+	}
+
+	addressNote, err := g.client.SetAddressNote(g.config.Axiom.TestNetName, address, noteReq.Note, noteReq.Flag)
+	if err != nil {
+		g.requestLogger(c).Error(err)
+		global.Result(global.Fail(global.CommonErrCode, global.CommonErrMsg), c)
+		return
+	}
+	g.requestLogger(c).Infof("admin set note of %s, flag: %s", address, noteReq.Flag)
+	g.client.Audit(g.requestContext(c), &audit.Event{Type: audit.EventAdminAction, Net: g.config.Axiom.TestNetName, Address: address, Action: "set_note", Detail: noteReq.Flag})
+	global.Result(global.SuccessDetail(addressNote), c)
+}
+
+func (g *Server) deleteAddressNote(c *gin.Context) {
+	address, ok := g.adminAddress(c)
+	if !ok {
+		return
+	}
+
+	g.client.DeleteAddressNote(g.config.Axiom.TestNetName, address)
+	g.requestLogger(c).Infof("admin deleted note of %s", address)
+	g.client.Audit(g.requestContext(c), &audit.Event{Type: audit.EventAdminAction, Net: g.config.Axiom.TestNetName, Address: address, Action: "delete_note"})
+	global.Result(global.Success(""), c)
+}
+
+// adminAddress 校验路径中的地址并统一为小写
+func (g *Server) adminAddress(c *gin.Context) (string, bool) {
+	address := c.Param("address")
+	if judge := IsValidEthereumAddress(address); !judge {
+		global.Result(global.Fail(global.ErrAddrCode, global.ErrAddrMsg+address), c)
+		return "", false
+	}
+	return strings.ToLower(address), true
+}
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testAdminToken = "admin-token"
+
+func adminHeader() http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + testAdminToken}}
+}
+
+// decodeDetail 将响应的 detail 解析到 v
+func decodeDetail(t *testing.T, res *global.Response, v any) {
+	t.Helper()
+	raw, err := json.Marshal(res.Detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		t.Fatalf("decode detail %s: %v", raw, err)
+	}
+}
+
+func fetchOverview(t *testing.T, g *Server) *internal.Overview {
+	t.Helper()
+	res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/admin/overview", nil, adminHeader()))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("overview failed: %d %s", res.Code, res.Msg)
+	}
+	overview := &internal.Overview{}
+	decodeDetail(t, res, overview)
+	return overview
+}
+
+func TestAdminRequiresToken(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+	})
+
+	for _, header := range []http.Header{nil, {"Authorization": []string{"Bearer wrong"}}} {
+		res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/admin/overview", nil, header))
+		if res.Code != global.UnauthorizedCode {
+			t.Fatalf("expect %d without a valid token, got %d", global.UnauthorizedCode, res.Code)
+		}
+	}
+}
+
+func TestAdminOverviewIsCached(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+		cfg.Admin.OverviewCacheTTL = repo.Duration(time.Hour)
+	})
+
+	if overview := fetchOverview(t, g); overview.Total.Claims != 0 || overview.Status == nil {
+		t.Fatalf("expect an empty overview with status, got %+v", overview)
+	}
+	claimAll(t, g, 1)
+	if overview := fetchOverview(t, g); overview.Total.Claims != 0 {
+		t.Fatalf("overview should be served from cache within the ttl, got %d claims", overview.Total.Claims)
+	}
+}
+
+func TestAdminOverviewReportsClaimsAndLastError(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+		cfg.Admin.OverviewCacheTTL = 0
+	})
+	claimAll(t, g, 1)
+
+	node.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (any, error) {
+		return nil, &testutil.RPCError{Code: -32000, Message: "txpool is full"}
+	})
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testAddress(1), Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code == global.SUCCESS {
+		t.Fatal("claim should fail when the node rejects the tx")
+	}
+
+	overview := fetchOverview(t, g)
+	if overview.Total.Claims != 1 || overview.Today.Claims != 1 {
+		t.Fatalf("expect 1 claim, got total %d today %d", overview.Total.Claims, overview.Today.Claims)
+	}
+	if overview.LastError == "" || overview.LastErrorTime == 0 {
+		t.Fatalf("overview should report the last send error, got %+v", overview)
+	}
+}
+
+func TestAdminClaimsListsRecentClaimsWithNonce(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+	})
+	addresses := claimAll(t, g, 3)
+	sent := node.Sent()
+
+	res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/admin/claims?limit=2", nil, adminHeader()))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("list claims failed: %d %s", res.Code, res.Msg)
+	}
+	var records []map[string]any
+	decodeDetail(t, res, &records)
+	if len(records) != 2 {
+		t.Fatalf("expect 2 records, got %d", len(records))
+	}
+	// 按时间倒序返回，nonce 与实际发出的交易一致
+	for i, record := range records {
+		j := len(addresses) - 1 - i
+		if record["address"] != addresses[j] || record["nonce"] != float64(sent[j].Nonce()) {
+			t.Fatalf("record %d: expect %s with nonce %d, got %v", i, addresses[j], sent[j].Nonce(), record)
+		}
+	}
+}
+
+func TestAdminClaimsRejectsInvalidLimit(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+	})
+
+	for _, limit := range []string{"0", "-1", "501", "many"} {
+		res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/admin/claims?limit="+limit, nil, adminHeader()))
+		if res.Code != global.ParseErrCode {
+			t.Fatalf("limit %s should be rejected, got %d", limit, res.Code)
+		}
+	}
+}
+
+func TestAdminBlockedNoteRefusesClaims(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+		cfg.Admin.OverviewCacheTTL = 0
+	})
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	notePath := "/faucet/admin/address/" + checksummed + "/note"
+	claimReq := global.DirectClaimReq{Address: checksummed, Net: g.config.Axiom.TestNetName}
+
+	res := decodeResponse(t, serve(g, http.MethodPut, notePath, global.AddressNoteReq{Note: "sybil", Flag: internal.FlagBlocked}, adminHeader()))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("set note failed: %d %s", res.Code, res.Msg)
+	}
+	res = decodeResponse(t, serve(g, http.MethodGet, notePath, nil, adminHeader()))
+	note := &internal.AddressNote{}
+	decodeDetail(t, res, note)
+	if note.Note != "sybil" || note.Flag != internal.FlagBlocked {
+		t.Fatalf("expect the stored note, got %+v", note)
+	}
+	if overview := fetchOverview(t, g); overview.Flagged != 1 {
+		t.Fatalf("expect 1 flagged address, got %d", overview.Flagged)
+	}
+
+	// 备注按小写地址保存，大小写不同的请求同样被拒绝
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", claimReq, nil)); res.Code != global.AddrBlockedCode {
+		t.Fatalf("blocked address should be refused with %d, got %d", global.AddrBlockedCode, res.Code)
+	}
+
+	if res := decodeResponse(t, serve(g, http.MethodDelete, notePath, nil, adminHeader())); res.Code != global.SUCCESS {
+		t.Fatalf("delete note failed: %d %s", res.Code, res.Msg)
+	}
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", claimReq, nil)); res.Code != global.SUCCESS {
+		t.Fatalf("claim after removing the flag failed: %d %s", res.Code, res.Msg)
+	}
+}
+
+func TestAdminNoteRejectsInvalidAddress(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPut, "/faucet/admin/address/0x1234/note", global.AddressNoteReq{Flag: internal.FlagBlocked}, adminHeader()))
+	if res.Code != global.ErrAddrCode {
+		t.Fatalf("expect %d, got %d", global.ErrAddrCode, res.Code)
+	}
+}
+
+func TestAdminClaimClampsAmountAndSkipsInterval(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+		cfg.Admin.MaxAmount = 50
+		cfg.Axiom.ClaimTierMultipliers = []float64{2}
+	})
+	net := g.config.Axiom.TestNetName
+
+	claimAll(t, g, 1)
+	// 管理员领取不受领取间隔限制，数量按上限截断且不按领取次数分档
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/admin/claim", global.AdminClaimReq{Net: net, Address: testAddress(0), Amount: 500}, adminHeader()))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("admin claim failed: %d %s", res.Code, res.Msg)
+	}
+	data := g.client.LastClaim(net, testAddress(0))
+	if data == nil || data.TxHash != res.Data || data.Amount != 50 || !data.Override {
+		t.Fatalf("expect an override record of 50, got %+v", data)
+	}
+}
+
+func TestAdminClaimKeepsClaimGates(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+	})
+	net := g.config.Axiom.TestNetName
+	if _, err := g.client.SetAddressNote(net, testRecipient, "", internal.FlagBlocked); err != nil {
+		t.Fatal(err)
+	}
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/admin/claim", global.AdminClaimReq{Net: net, Address: testRecipient, Amount: 1}, adminHeader()))
+	if res.Code != global.AddrBlockedCode {
+		t.Fatalf("blocked address should be refused with %d, got %d", global.AddrBlockedCode, res.Code)
+	}
+	res = decodeResponse(t, serve(g, http.MethodPost, "/faucet/admin/claim", global.AdminClaimReq{Net: net, Address: testAddress(1), Amount: 0}, adminHeader()))
+	if res.Code != global.ParseErrCode {
+		t.Fatalf("non-positive amount should be rejected, got %d", res.Code)
+	}
+}
+
+func TestAdminRotateFundingKey(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+	})
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	req := global.RotateKeyReq{Net: g.config.Axiom.TestNetName, Key: hex.EncodeToString(crypto.FromECDSA(key))}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/admin/funding-key", req, adminHeader()))
+	if res.Code != global.SUCCESS || res.Detail != want {
+		t.Fatalf("expect funding address %s, got %d %v", want, res.Code, res.Detail)
+	}
+	if got := g.client.FundingAddress(); got != want {
+		t.Fatalf("expect funding address %s, got %s", want, got)
+	}
+
+	req.Key = "zz"
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/admin/funding-key", req, adminHeader())); res.Code != global.InvalidKeyCode {
+		t.Fatalf("expect %d for an invalid key, got %d", global.InvalidKeyCode, res.Code)
+	}
+}
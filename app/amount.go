@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// 请求头中指定数量格式，未指定时使用配置 network.amount_format
+const amountFormatHeader = "X-Amount-Format"
+
+// amountFormat 返回本次请求使用的数量格式，响应随请求头变化，需要声明 Vary 避免缓存混用
+func (g *Server) amountFormat(c *gin.Context) string {
+	addVary(c.Writer.Header(), amountFormatHeader)
+	switch format := c.GetHeader(amountFormatHeader); format {
+	case repo.AmountFormatEther, repo.AmountFormatDecimal, repo.AmountFormatHex:
+		return format
+	default:
+		return g.config.Network.AmountFormat
+	}
+}
+
+// formatAmount 将以 ether 为单位的数量格式化：ether 保持数字，decimal 为十进制 wei 字符串，hex 为 0x 开头的 wei
+func formatAmount(amount float64, format string) any {
+	switch format {
+	case repo.AmountFormatDecimal:
+		return internal.EtherToWei(amount).String()
+	case repo.AmountFormatHex:
+		return hexutil.EncodeBig(internal.EtherToWei(amount))
+	default:
+		return amount
+	}
+}
+
+// formattedCounter 覆盖 ClaimCounter 中的 disbursed 字段
+type formattedCounter struct {
+	internal.ClaimCounter
+	Disbursed any `json:"disbursed"`
+}
+
+type formattedStats struct {
+	Net   string           `json:"net"`
+	Total formattedCounter `json:"total"`
+	Today formattedCounter `json:"today"`
+}
+
+func newFormattedStats(stats *internal.Stats, format string) *formattedStats {
+	return &formattedStats{
+		Net:   stats.Net,
+		Total: formattedCounter{ClaimCounter: stats.Total, Disbursed: formatAmount(stats.Total.Disbursed, format)},
+		Today: formattedCounter{ClaimCounter: stats.Today, Disbursed: formatAmount(stats.Today.Disbursed, format)},
+	}
+}
+
+// formattedClaimRecord 覆盖 ClaimRecord 中的 amount 字段
+type formattedClaimRecord struct {
+	*internal.ClaimRecord
+	Amount any `json:"amount"`
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestFormatAmount(t *testing.T) {
+	for _, tc := range []struct {
+		amount float64
+		format string
+		want   any
+	}{
+		{100, repo.AmountFormatEther, 100.0},
+		{100, "", 100.0},
+		{100, repo.AmountFormatDecimal, "100000000000000000000"},
+		{100, repo.AmountFormatHex, "0x56bc75e2d63100000"},
+		{0.5, repo.AmountFormatDecimal, "500000000000000000"},
+		{0, repo.AmountFormatHex, "0x0"},
+	} {
+		if got := formatAmount(tc.amount, tc.format); got != tc.want {
+			t.Fatalf("format %v as %q: expect %v, got %v", tc.amount, tc.format, tc.want, got)
+		}
+	}
+}
+
+func TestPublicConfigAmountFormat(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.Amount = 100
+		cfg.Network.AmountFormat = repo.AmountFormatDecimal
+	})
+
+	for header, want := range map[string]any{
+		"":                     "100000000000000000000",
+		repo.AmountFormatHex:   "0x56bc75e2d63100000",
+		repo.AmountFormatEther: 100.0,
+		"unknown":              "100000000000000000000",
+	} {
+		w := serve(g, http.MethodGet, "/faucet/config", nil, http.Header{amountFormatHeader: []string{header}})
+		if vary := w.Header().Values("Vary"); !strings.Contains(strings.Join(vary, ","), amountFormatHeader) {
+			t.Fatalf("response should vary on %s, got %q", amountFormatHeader, vary)
+		}
+		res := decodeResponse(t, w)
+		if res.Code != global.SUCCESS {
+			t.Fatalf("config failed: %d %s", res.Code, res.Msg)
+		}
+		config := make(map[string]any)
+		decodeDetail(t, res, &config)
+		if config["amount"] != want {
+			t.Fatalf("header %q: expect amount %v, got %v", header, want, config["amount"])
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+type cacheWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *cacheWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// CacheControl 为只读接口设置 Cache-Control 和 ETag，If-None-Match 命中时返回 304。
+// 响应消息按 Accept-Language 本地化，声明 Vary 并将语言计入 ETag，避免共享缓存把一种语言的响应返回给所有用户
+func (g *Server) CacheControl(ttl time.Duration) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		addVary(c.Writer.Header(), "Accept-Language")
+		writer := &cacheWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.Status() != http.StatusOK {
+			_, _ = c.Writer.Write(writer.body.Bytes())
+			return
+		}
+		hash := sha256.New()
+		hash.Write([]byte(global.ParseLanguage(c.GetHeader("Accept-Language"))))
+		hash.Write([]byte{0})
+		hash.Write(writer.body.Bytes())
+		sum := hash.Sum(nil)
+		etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(ttl.Seconds())))
+		if c.GetHeader("If-None-Match") == etag {
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		_, _ = c.Writer.Write(writer.body.Bytes())
+	}
+}
+
+// addVary 在 Vary 响应头中追加 value，已声明时不重复添加，不覆盖其他中间件设置的值
+func addVary(header http.Header, value string) {
+	for _, vary := range header.Values("Vary") {
+		for _, field := range strings.Split(vary, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
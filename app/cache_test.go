@@ -0,0 +1,75 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestCacheControlSetsHeadersAndETag(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.ConfigCacheTTL = repo.Duration(30 * time.Second)
+	})
+
+	w := serve(g, http.MethodGet, "/faucet/config", nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expect 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=30" {
+		t.Fatalf("unexpected Cache-Control %q", got)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("expect an ETag and a body, got %q %q", etag, w.Body.String())
+	}
+
+	w = serve(g, http.MethodGet, "/faucet/config", nil, http.Header{"If-None-Match": {etag}})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("matching If-None-Match should return an empty 304, got %d %q", w.Code, w.Body.String())
+	}
+	w = serve(g, http.MethodGet, "/faucet/config", nil, http.Header{"If-None-Match": {`"stale"`}})
+	if w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Fatalf("stale If-None-Match should return the body, got %d", w.Code)
+	}
+}
+
+// 响应按 Accept-Language 本地化，Vary 同时声明语言与数量格式，不同语言的 ETag 不同
+func TestCacheControlVariesByLanguage(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.ConfigCacheTTL = repo.Duration(30 * time.Second)
+	})
+
+	en := serve(g, http.MethodGet, "/faucet/config", nil, http.Header{"Accept-Language": {"en"}})
+	vary := strings.Join(en.Header().Values("Vary"), ",")
+	for _, want := range []string{"Accept-Language", amountFormatHeader} {
+		if !strings.Contains(vary, want) {
+			t.Fatalf("response should vary on %s, got %q", want, vary)
+		}
+	}
+	if strings.Count(vary, "Accept-Language") != 1 {
+		t.Fatalf("Accept-Language should be declared once, got %q", vary)
+	}
+
+	zh := serve(g, http.MethodGet, "/faucet/config", nil, http.Header{"Accept-Language": {"zh-CN"}})
+	if zh.Header().Get("ETag") == en.Header().Get("ETag") {
+		t.Fatal("responses in different languages should have different ETags")
+	}
+	w := serve(g, http.MethodGet, "/faucet/config", nil, http.Header{"Accept-Language": {"zh-CN"}, "If-None-Match": {en.Header().Get("ETag")}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("ETag of another language should not match, got %d", w.Code)
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	header := http.Header{}
+	header.Set("Vary", "Accept-Encoding, X-Amount-Format")
+	addVary(header, "x-amount-format")
+	addVary(header, "Accept-Language")
+	if got := header.Values("Vary"); len(got) != 2 || got[1] != "Accept-Language" {
+		t.Fatalf("expect Accept-Language appended once, got %q", got)
+	}
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+)
+
+// claimEvents 以 Server-Sent Events 推送领取交易状态：轮询节点，状态变化时发送 status 事件，
+// 交易上链（mined/reverted）后结束；超过 max_duration 仍未上链时发送 timeout 事件后结束，客户端断开时停止轮询
+func (g *Server) claimEvents(c *gin.Context) {
+	txHash := c.Param("id")
+	if hash, err := hexutil.Decode(txHash); err != nil || len(hash) != 32 {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// 回执中已记录最终状态时直接返回，不再轮询节点
+	if receipt, ok := g.client.GetReceipt(internal.ReceiptID(txHash)); ok {
+		if status, ok := receipt.TxStatus(); ok {
+			c.SSEvent("status", status)
+			c.Writer.Flush()
+			return
+		}
+	}
+
+	cfg := g.config.ClaimEvents
+	ctx := c.Request.Context()
+	interval := cfg.PollInterval.ToDuration()
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.MaxDuration.ToDuration())
+	defer deadline.Stop()
+
+	var last string
+	for {
+		status, err := g.client.TxStatus(ctx, txHash)
+		if err != nil {
+			g.requestLogger(c).Warnf("query status of tx %s: %v", txHash, err)
+		} else if status.Status != last {
+			last = status.Status
+			c.SSEvent("status", status)
+			c.Writer.Flush()
+			if status.Terminal() {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			c.SSEvent("timeout", &internal.TxStatus{TxHash: txHash, Status: last})
+			c.Writer.Flush()
+			return
+		case <-ticker.C:
+		}
+	}
+}
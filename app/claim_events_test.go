@@ -0,0 +1,107 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newClaimEventsServer(t *testing.T, node *testutil.Node) *Server {
+	t.Helper()
+	return newTestServer(t, node, func(cfg *repo.Config) {
+		cfg.ClaimEvents.Enable = true
+		cfg.ClaimEvents.PollInterval = repo.Duration(10 * time.Millisecond)
+		cfg.ClaimEvents.MaxDuration = repo.Duration(100 * time.Millisecond)
+	})
+}
+
+func TestClaimEventsUntilMined(t *testing.T) {
+	g := newClaimEventsServer(t, testutil.NewNode(t))
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+
+	w := serve(g, http.MethodGet, "/faucet/claim/"+res.Data+"/events", nil, nil)
+	if w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expect event stream, got %q", w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "event:status") || !strings.Contains(body, `"status":"mined"`) || strings.Contains(body, "event:timeout") {
+		t.Fatalf("expect a mined status event, got %q", body)
+	}
+}
+
+// 超过 max_duration 仍未上链时发送 timeout 事件后结束
+func TestClaimEventsTimeout(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newClaimEventsServer(t, node)
+	node.SetAutoReceipt(false)
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+
+	body := serve(g, http.MethodGet, "/faucet/claim/"+res.Data+"/events", nil, nil).Body.String()
+	// 状态不变时只推送一次
+	if strings.Count(body, "event:status") != 1 || !strings.Contains(body, `"status":"pending"`) || !strings.Contains(body, "event:timeout") {
+		t.Fatalf("expect one pending event followed by timeout, got %q", body)
+	}
+}
+
+func TestClaimEventsRejectsInvalidHash(t *testing.T) {
+	g := newClaimEventsServer(t, testutil.NewNode(t))
+	res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/claim/0x1234/events", nil, nil))
+	if res.Code != global.ParseErrCode {
+		t.Fatalf("expect %d for an invalid tx hash, got %d", global.ParseErrCode, res.Code)
+	}
+}
+
+func TestClaimEventsDisabled(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	hash := "0x" + strings.Repeat("ab", 32)
+	if w := serve(g, http.MethodGet, "/faucet/claim/"+hash+"/events", nil, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("events should not be served when disabled, got %d", w.Code)
+	}
+}
+
+// 与排队凭证查询共用 claim/:id 路由前缀
+func TestClaimEventsWithQueue(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+		cfg.ClaimEvents.Enable = true
+		cfg.ClaimEvents.MaxDuration = repo.Duration(100 * time.Millisecond)
+	})
+	res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/claim/unknown", nil, nil))
+	if res.Code != global.TicketNotFoundCode {
+		t.Fatalf("expect %d for an unknown ticket, got %d", global.TicketNotFoundCode, res.Code)
+	}
+	hash := "0x" + strings.Repeat("ab", 32)
+	if body := serve(g, http.MethodGet, "/faucet/claim/"+hash+"/events", nil, nil).Body.String(); !strings.Contains(body, `"status":"pending"`) {
+		t.Fatalf("expect events of the tx, got %q", body)
+	}
+}
+
+// 回执已记录最终状态时直接推送，不再查询节点
+func TestClaimEventsUsesFinalReceipt(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newClaimEventsServer(t, node)
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+
+	calls := node.Calls("eth_getTransactionReceipt")
+	body := serve(g, http.MethodGet, "/faucet/claim/"+res.Data+"/events", nil, nil).Body.String()
+	if !strings.Contains(body, `"status":"mined"`) || !strings.Contains(body, `"blockNumber"`) {
+		t.Fatalf("expect the final status from the receipt, got %q", body)
+	}
+	if node.Calls("eth_getTransactionReceipt") != calls {
+		t.Fatal("final receipt should not query the node")
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipWriter 先缓冲响应，超过最小长度后切换为 gzip 输出，未达到最小长度的响应原样输出
+type gzipWriter struct {
+	gin.ResponseWriter
+	minSize int
+	buffer  bytes.Buffer
+	gz      *gzip.Writer
+	// passthrough 为 true 时不再压缩，直接写出
+	passthrough bool
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) startGzip() error {
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+// Flush 流式接口刷新时，尚未达到最小长度的响应不再压缩
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	} else if !w.passthrough {
+		w.finishPlain()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) finishPlain() {
+	w.passthrough = true
+	if w.buffer.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+}
+
+func (w *gzipWriter) close() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+		return
+	}
+	if !w.passthrough {
+		w.finishPlain()
+	}
+}
+
+// Compress 对支持 gzip 的 GET 请求压缩响应，响应小于 network.gzip_min_size 时不压缩
+func (g *Server) Compress() func(c *gin.Context) {
+	if !g.config.Network.EnableGzip {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	minSize := g.config.Network.GzipMinSize
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+			c.Next()
+			return
+		}
+		writer := &gzipWriter{ResponseWriter: c.Writer, minSize: minSize}
+		c.Writer = writer
+		defer func() {
+			writer.close()
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Next()
+	}
+}
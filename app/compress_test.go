@@ -0,0 +1,79 @@
+package app
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+var gzipHeader = http.Header{"Accept-Encoding": {"gzip, deflate"}}
+
+func newCompressServer(t *testing.T, minSize int) *Server {
+	t.Helper()
+	return newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableGzip = true
+		cfg.Network.GzipMinSize = minSize
+	})
+}
+
+func TestCompressLargeResponse(t *testing.T) {
+	g := newCompressServer(t, 1)
+
+	w := serve(g, http.MethodGet, "/faucet/config", nil, gzipHeader)
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("response should be compressed, got headers %v", w.Header())
+	}
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &global.Response{}
+	if err := json.Unmarshal(raw, res); err != nil || res.Code != global.SUCCESS {
+		t.Fatalf("unexpected decompressed body %s: %v", raw, err)
+	}
+}
+
+func TestCompressSkipsSmallResponse(t *testing.T) {
+	g := newCompressServer(t, 1<<20)
+
+	w := serve(g, http.MethodGet, "/faucet/config", nil, gzipHeader)
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Fatal("response below the min size should not be compressed")
+	}
+	if res := decodeResponse(t, w); res.Code != global.SUCCESS {
+		t.Fatalf("unexpected response %d %s", res.Code, res.Msg)
+	}
+}
+
+func TestCompressRequiresAcceptEncoding(t *testing.T) {
+	g := newCompressServer(t, 1)
+
+	w := serve(g, http.MethodGet, "/faucet/config", nil, nil)
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Fatal("client without gzip support should get a plain response")
+	}
+	if res := decodeResponse(t, w); res.Code != global.SUCCESS {
+		t.Fatalf("unexpected response %d %s", res.Code, res.Msg)
+	}
+}
+
+func TestCompressDisabled(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableGzip = false
+		cfg.Network.GzipMinSize = 1
+	})
+
+	if w := serve(g, http.MethodGet, "/faucet/config", nil, gzipHeader); w.Header().Get("Content-Encoding") != "" {
+		t.Fatal("response should not be compressed when gzip is disabled")
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// claimDedupe 合并窗口内相同 (route, address, net) 的领取请求：处理中的请求等待第一次的结果，
+// 刚完成的请求在窗口内直接返回缓存的结果，窗口过后按正常的领取限制处理
+type claimDedupe struct {
+	lock    sync.Mutex
+	window  time.Duration
+	entries map[string]*dedupeEntry
+}
+
+type dedupeEntry struct {
+	done       chan struct{}
+	res        *global.Response
+	finishedAt time.Time
+}
+
+func newClaimDedupe(window time.Duration) *claimDedupe {
+	return &claimDedupe{window: window, entries: make(map[string]*dedupeEntry)}
+}
+
+// do 执行领取并返回结果，window 为 0 时不合并；不同接口的领取（如直接领取与推文领取）分别合并
+func (d *claimDedupe) do(route string, net string, address string, claim func() *global.Response) *global.Response {
+	if d.window <= 0 {
+		return claim()
+	}
+	key := route + "-" + net + "-" + strings.ToLower(address)
+	now := time.Now()
+
+	d.lock.Lock()
+	if entry, ok := d.entries[key]; ok && (entry.finishedAt.IsZero() || now.Sub(entry.finishedAt) < d.window) {
+		d.lock.Unlock()
+		<-entry.done
+		if entry.res == nil {
+			return global.Fail(global.CommonErrCode, global.CommonErrMsg)
+		}
+		// Result 会本地化 msg，返回副本避免修改缓存的结果
+		res := *entry.res
+		return &res
+	}
+	for k, entry := range d.entries {
+		if !entry.finishedAt.IsZero() && now.Sub(entry.finishedAt) >= d.window {
+			delete(d.entries, k)
+		}
+	}
+	entry := &dedupeEntry{done: make(chan struct{})}
+	d.entries[key] = entry
+	d.lock.Unlock()
+
+	var res *global.Response
+	defer func() {
+		d.lock.Lock()
+		entry.res = res
+		entry.finishedAt = time.Now()
+		// claim panic 时不缓存结果，等待中的请求返回通用错误
+		if res == nil && d.entries[key] == entry {
+			delete(d.entries, key)
+		}
+		d.lock.Unlock()
+		close(entry.done)
+	}()
+	res = claim()
+	copied := *res
+	return &copied
+}
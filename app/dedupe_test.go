@@ -0,0 +1,147 @@
+package app
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+const dedupeRoute = "/faucet/directClaim"
+
+func TestClaimDedupeMergesConcurrentClaims(t *testing.T) {
+	d := newClaimDedupe(time.Minute)
+	var calls int32
+	release := make(chan struct{})
+	claim := func() *global.Response {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return global.Success("0xhash")
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*global.Response, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = d.do(dedupeRoute, "testnet", testRecipient, claim)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("identical claims should run once, got %d", calls)
+	}
+	for i, res := range results {
+		if res.Code != global.SUCCESS || res.Data != "0xhash" {
+			t.Fatalf("claim %d should share the first result, got %+v", i, res)
+		}
+	}
+	// 每个请求拿到各自的副本
+	if results[0] == results[1] {
+		t.Fatal("merged claims should not share the same response")
+	}
+}
+
+// 窗口内直接返回缓存结果，地址大小写不影响合并
+func TestClaimDedupeCachesWithinWindow(t *testing.T) {
+	d := newClaimDedupe(time.Minute)
+	var calls int
+	claim := func() *global.Response {
+		calls++
+		return global.Fail(global.ReqWithinDayCode, global.ReqWithinDayMsg)
+	}
+	d.do(dedupeRoute, "testnet", testRecipient, claim)
+	res := d.do(dedupeRoute, "testnet", "0x"+strings.ToUpper(testRecipient[2:]), claim)
+	if calls != 1 || res.Code != global.ReqWithinDayCode {
+		t.Fatalf("claim within window should be cached, got %d calls %+v", calls, res)
+	}
+}
+
+func TestClaimDedupeSeparatesKeys(t *testing.T) {
+	d := newClaimDedupe(time.Minute)
+	var calls int
+	claim := func() *global.Response {
+		calls++
+		return global.Success("")
+	}
+	d.do(dedupeRoute, "testnet", testRecipient, claim)
+	d.do("/faucet/tweetClaim", "testnet", testRecipient, claim)
+	d.do(dedupeRoute, "othernet", testRecipient, claim)
+	d.do(dedupeRoute, "testnet", "0x2222222222222222222222222222222222222222", claim)
+	if calls != 4 {
+		t.Fatalf("different route, net or address should not merge, got %d calls", calls)
+	}
+}
+
+func TestClaimDedupeExpires(t *testing.T) {
+	d := newClaimDedupe(20 * time.Millisecond)
+	var calls int
+	claim := func() *global.Response {
+		calls++
+		return global.Success("")
+	}
+	d.do(dedupeRoute, "testnet", testRecipient, claim)
+	time.Sleep(30 * time.Millisecond)
+	d.do(dedupeRoute, "testnet", testRecipient, claim)
+	if calls != 2 {
+		t.Fatalf("claim after window should run again, got %d calls", calls)
+	}
+	if len(d.entries) != 1 {
+		t.Fatalf("expired entries should be removed, got %d", len(d.entries))
+	}
+}
+
+func TestClaimDedupeDisabled(t *testing.T) {
+	d := newClaimDedupe(0)
+	var calls int
+	claim := func() *global.Response {
+		calls++
+		return global.Success("")
+	}
+	d.do(dedupeRoute, "testnet", testRecipient, claim)
+	d.do(dedupeRoute, "testnet", testRecipient, claim)
+	if calls != 2 {
+		t.Fatalf("zero window should not merge, got %d calls", calls)
+	}
+}
+
+// 第一次领取 panic 时等待中的请求返回通用错误，之后的请求重新领取
+func TestClaimDedupeRecoversFromPanic(t *testing.T) {
+	d := newClaimDedupe(time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		defer func() { recover() }()
+		d.do(dedupeRoute, "testnet", testRecipient, func() *global.Response {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	waited := make(chan *global.Response)
+	go func() {
+		waited <- d.do(dedupeRoute, "testnet", testRecipient, func() *global.Response {
+			t.Error("waiting claim should not run")
+			return nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if res := <-waited; res.Code != global.CommonErrCode {
+		t.Fatalf("expect %d after panic, got %+v", global.CommonErrCode, res)
+	}
+
+	res := d.do(dedupeRoute, "testnet", testRecipient, func() *global.Response { return global.Success("") })
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim after panic should run again, got %+v", res)
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestClaimOutsideEventRejected(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, func(cfg *repo.Config) {
+		cfg.Event = repo.Event{Timezone: "UTC", Start: "2020-01-01 00:00", End: "2020-01-02 00:00"}
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.EventInactiveCode {
+		t.Fatalf("expect %d, got %d %s", global.EventInactiveCode, res.Code, res.Msg)
+	}
+	window := &internal.EventWindow{}
+	decodeDetail(t, res, window)
+	if window.Active || window.Start != "2020-01-01T00:00:00Z" || window.End != "2020-01-02T00:00:00Z" {
+		t.Fatalf("detail should carry the event window, got %+v", window)
+	}
+	if len(node.Sent()) != 0 {
+		t.Fatal("no tx should be sent outside the event")
+	}
+
+	config := &PublicConfig{}
+	decodeDetail(t, decodeResponse(t, serve(g, http.MethodGet, "/faucet/config", nil, nil)), config)
+	if config.Event == nil || config.Event.Active {
+		t.Fatalf("config should report the inactive event, got %+v", config.Event)
+	}
+}
+
+func TestClaimWithinEventServed(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Event = repo.Event{Timezone: "UTC", Start: "2020-01-01 00:00"}
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim within the event should succeed, got %d %s", res.Code, res.Msg)
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestResponsesIncludeFundingAddress(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	funding := g.client.FundingAddress()
+	if funding == "" {
+		t.Fatal("funding address should be loaded")
+	}
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &ClaimDetail{}
+	decodeDetail(t, res, detail)
+	if detail.FundingAddress != funding {
+		t.Fatalf("claim should report funding address %s, got %s", funding, detail.FundingAddress)
+	}
+
+	config := &PublicConfig{}
+	decodeDetail(t, decodeResponse(t, serve(g, http.MethodGet, "/faucet/config", nil, nil)), config)
+	if config.FundingAddress != funding {
+		t.Fatalf("config should report funding address %s, got %s", funding, config.FundingAddress)
+	}
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testRecipient = "0x1111111111111111111111111111111111111111"
+
+// testFaucetAddress 测试使用的水龙头合约地址
+var testFaucetAddress = "0x" + strings.Repeat("fa", 20)
+
+// newTestServer 连接测试节点初始化客户端并注册路由，请求通过 serve 直接交给 router 处理
+func newTestServer(t *testing.T, node *testutil.Node, setup func(cfg *repo.Config)) *Server {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := repo.DefaultConfig()
+	cfg.Network.Port = "0"
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.AxiomKey = hex.EncodeToString(crypto.FromECDSA(key))
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.FaucetAddr = testFaucetAddress
+	if setup != nil {
+		setup(cfg)
+	}
+	node.SetBalance(cfg.Axiom.FaucetAddr, 1e6)
+	node.SetBalance(crypto.PubkeyToAddress(key.PublicKey).Hex(), 1000)
+
+	client := &internal.Client{}
+	if err := client.Initialize(cfg, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewServer(client, cfg)
+	if err != nil {
+		client.Close()
+		t.Fatal(err)
+	}
+	if err := g.Start(); err != nil {
+		client.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = g.Stop() })
+	return g
+}
+
+// serve 发送请求，body 不为 nil 时按 json 编码
+func serve(g *Server, method string, path string, body any, header http.Header) *httptest.ResponseRecorder {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		raw, _ := json.Marshal(b)
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	g.router.ServeHTTP(w, req)
+	return w
+}
+
+// decodeResponse 解析统一格式的响应
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) *global.Response {
+	t.Helper()
+	res := &global.Response{}
+	if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return res
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+)
+
+// 流式输出时每写入多少条记录刷新一次
+const streamFlushSize = 100
+
+// 分页查询每页的最大记录数
+const maxHistoryPageSize = 100
+
+// ClaimPage 分页查询的一页领取记录，nextCursor 为空表示没有更多记录
+type ClaimPage struct {
+	Records    []*formattedClaimRecord `json:"records"`
+	NextCursor string                  `json:"nextCursor,omitempty"`
+}
+
+// history 带 limit 参数时按游标分页返回，否则以 NDJSON 流式返回全部记录
+func (g *Server) history(c *gin.Context) {
+	address := c.Query("address")
+	if judge := IsValidEthereumAddress(address); !judge {
+		global.Result(global.Fail(global.ErrAddrCode, global.ErrAddrMsg+address), c)
+		return
+	}
+
+	if _, ok := c.GetQuery("limit"); ok {
+		g.claimPage(c, strings.ToLower(address))
+		return
+	}
+	g.streamClaims(c, strings.ToLower(address))
+}
+
+func (g *Server) claimPage(c *gin.Context, address string) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 || limit > maxHistoryPageSize {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+	records, next, err := g.client.ClaimPage(g.config.Axiom.TestNetName, address, c.Query("cursor"), limit)
+	if err != nil {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+	format := g.amountFormat(c)
+	page := &ClaimPage{Records: make([]*formattedClaimRecord, 0, len(records)), NextCursor: next}
+	for _, record := range records {
+		page.Records = append(page.Records, &formattedClaimRecord{ClaimRecord: record, Amount: formatAmount(record.Amount, format)})
+	}
+	global.Result(global.SuccessDetail(page), c)
+}
+
+func (g *Server) export(c *gin.Context) {
+	g.streamClaims(c, "")
+}
+
+// streamClaims 以 NDJSON 格式边遍历边输出领取记录，客户端读取慢时写入阻塞，遍历随之暂停，内存占用不随记录数增长
+func (g *Server) streamClaims(c *gin.Context, address string) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	format := g.amountFormat(c)
+	encoder := json.NewEncoder(c.Writer)
+	count := 0
+	g.client.IterateClaims(g.config.Axiom.TestNetName, address, func(record *internal.ClaimRecord) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		default:
+		}
+		if err := encoder.Encode(&formattedClaimRecord{ClaimRecord: record, Amount: formatAmount(record.Amount, format)}); err != nil {
+			g.requestLogger(c).Warn(fmt.Errorf("stream claim records: %w", err))
+			return false
+		}
+		count++
+		if count%streamFlushSize == 0 {
+			c.Writer.Flush()
+		}
+		return true
+	})
+	c.Writer.Flush()
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func testAddress(i int) string {
+	return fmt.Sprintf("0x%040x", i+1)
+}
+
+// claimAll 依次为 n 个地址领取
+func claimAll(t *testing.T, g *Server, n int) []string {
+	t.Helper()
+	addresses := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		address := testAddress(i)
+		res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: address, Net: g.config.Axiom.TestNetName}, nil))
+		if res.Code != global.SUCCESS {
+			t.Fatalf("claim for %s failed: %d %s", address, res.Code, res.Msg)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+func readNDJSON(t *testing.T, body string) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		record := make(map[string]any)
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestExportStreamsAllClaims(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableExport = true
+	})
+	claimAll(t, g, 3)
+
+	w := serve(g, http.MethodGet, "/faucet/export", nil, nil)
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("expect ndjson, got %q", got)
+	}
+	if records := readNDJSON(t, w.Body.String()); len(records) != 3 {
+		t.Fatalf("expect 3 records, got %d: %s", len(records), w.Body.String())
+	}
+}
+
+func TestHistoryStreamsClaimsOfAddress(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	addresses := claimAll(t, g, 2)
+
+	w := serve(g, http.MethodGet, "/faucet/history?address="+addresses[1], nil, nil)
+	records := readNDJSON(t, w.Body.String())
+	if len(records) != 1 || !strings.EqualFold(records[0]["address"].(string), addresses[1]) {
+		t.Fatalf("expect the single claim of %s, got %s", addresses[1], w.Body.String())
+	}
+}
+
+func TestExportDisabledByDefault(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	if w := serve(g, http.MethodGet, "/faucet/export", nil, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("export is off, expect 404, got %d", w.Code)
+	}
+}
+
+func TestHistoryPage(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	addresses := claimAll(t, g, 2)
+
+	page := &ClaimPage{}
+	decodeDetail(t, decodeResponse(t, serve(g, http.MethodGet, "/faucet/history?limit=10&address="+addresses[0], nil, nil)), page)
+	if len(page.Records) != 1 || !strings.EqualFold(page.Records[0].Address, addresses[0]) || page.NextCursor != "" {
+		t.Fatalf("expect the single claim of %s without cursor, got %+v", addresses[0], page)
+	}
+}
+
+func TestHistoryPageRejectsInvalidParams(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	for _, query := range []string{"limit=0", "limit=101", "limit=abc", "limit=10&cursor=invalid"} {
+		res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/history?address="+testRecipient+"&"+query, nil, nil))
+		if res.Code != global.ParseErrCode {
+			t.Errorf("%s: expect %d, got %d", query, global.ParseErrCode, res.Code)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// PublicConfig 对外公开的配置，不包含密钥路径等敏感信息
+type PublicConfig struct {
+	Net          string   `json:"net"`
+	ChainID      uint64   `json:"chainId"`
+	Amount       any      `json:"amount"`
+	TweetAmount  any      `json:"tweetAmount"`
+	ClaimLimit   any      `json:"claimLimit"`
+	TweetDomains []string `json:"tweetDomains"`
+	// FundingAddress 发送领取交易的资金账户地址
+	FundingAddress string `json:"fundingAddress,omitempty"`
+	// Event 限时活动的起止时间，未配置活动时为空
+	Event *internal.EventWindow `json:"event,omitempty"`
+}
+
+func (g *Server) publicConfig(c *gin.Context) {
+	format := g.amountFormat(c)
+	global.Result(global.SuccessDetail(&PublicConfig{
+		Net:            g.config.Axiom.TestNetName,
+		ChainID:        g.config.Axiom.ChainID,
+		Amount:         formatAmount(g.config.Axiom.Amount, format),
+		TweetAmount:    formatAmount(g.config.Axiom.TweetAmount, format),
+		ClaimLimit:     formatAmount(g.config.Axiom.ClaimLimit, format),
+		TweetDomains:   g.config.Scrapper.TweetDomains,
+		FundingAddress: g.client.FundingAddress(),
+		Event:          g.client.Event(time.Now()),
+	}), c)
+}
+
+func (g *Server) status(c *gin.Context) {
+	status, err := g.client.Status()
+	if err != nil {
+		g.requestLogger(c).Error(err)
+		global.Result(global.Fail(global.BlockChainCode, global.BlockChainMsg), c)
+		return
+	}
+
+	global.Result(global.SuccessDetail(status), c)
+}
+
+// VersionInfo 构建时通过 -ldflags -X 注入的版本信息
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Branch    string `json:"branch"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+func (g *Server) version(c *gin.Context) {
+	global.Result(global.SuccessDetail(&VersionInfo{
+		Version:   repo.BuildVersion,
+		Commit:    repo.BuildCommit,
+		Branch:    repo.BuildBranch,
+		BuildDate: repo.BuildDate,
+		GoVersion: repo.GoVersion,
+		Platform:  repo.Platform,
+	}), c)
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestVersionReportsBuildInfo(t *testing.T) {
+	commit, branch, date := repo.BuildCommit, repo.BuildBranch, repo.BuildDate
+	repo.BuildCommit, repo.BuildBranch, repo.BuildDate = "abc1234", "main", "2026-01-02T03:04:05Z"
+	t.Cleanup(func() {
+		repo.BuildCommit, repo.BuildBranch, repo.BuildDate = commit, branch, date
+	})
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/version", nil, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("version failed: %d %s", res.Code, res.Msg)
+	}
+	info := &VersionInfo{}
+	decodeDetail(t, res, info)
+	want := VersionInfo{
+		Version:   repo.BuildVersion,
+		Commit:    "abc1234",
+		Branch:    "main",
+		BuildDate: "2026-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if *info != want {
+		t.Fatalf("expect %+v, got %+v", want, *info)
+	}
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// claimFrom 从 remoteAddr 发起领取，forwardedFor 不为空时附带 X-Forwarded-For
+func claimFrom(t *testing.T, g *Server, remoteAddr string, forwardedFor string, address string) *global.Response {
+	t.Helper()
+	body, _ := json.Marshal(global.DirectClaimReq{Address: address, Net: g.config.Axiom.TestNetName})
+	req := httptest.NewRequest(http.MethodPost, "/faucet/directClaim", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	g.router.ServeHTTP(w, req)
+	return decodeResponse(t, w)
+}
+
+func newIPLimitServer(t *testing.T) *Server {
+	t.Helper()
+	return newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.MaxAddressesPerIP = 2
+	})
+}
+
+func TestMaxAddressesPerIP(t *testing.T) {
+	g := newIPLimitServer(t)
+
+	for i := 0; i < 2; i++ {
+		if res := claimFrom(t, g, "203.0.113.7:1234", "", testAddress(i)); res.Code != global.SUCCESS {
+			t.Fatalf("claim %d failed: %d %s", i, res.Code, res.Msg)
+		}
+	}
+	if res := claimFrom(t, g, "203.0.113.7:1234", "", testAddress(2)); res.Code != global.IPAddressLimitCode {
+		t.Fatalf("third address from the same ip should be refused with %d, got %d", global.IPAddressLimitCode, res.Code)
+	}
+	if res := claimFrom(t, g, "203.0.113.8:1234", "", testAddress(2)); res.Code != global.SUCCESS {
+		t.Fatalf("claim from another ip failed: %d %s", res.Code, res.Msg)
+	}
+}
+
+// 不可信来源的 X-Forwarded-For 被忽略，不能伪造 IP 绕过限制
+func TestMaxAddressesPerIPIgnoresUntrustedForwardedFor(t *testing.T) {
+	g := newIPLimitServer(t)
+
+	for i, forwarded := range []string{"198.51.100.1", "198.51.100.2"} {
+		if res := claimFrom(t, g, "203.0.113.7:1234", forwarded, testAddress(i)); res.Code != global.SUCCESS {
+			t.Fatalf("claim %d failed: %d %s", i, res.Code, res.Msg)
+		}
+	}
+	if res := claimFrom(t, g, "203.0.113.7:1234", "198.51.100.3", testAddress(2)); res.Code != global.IPAddressLimitCode {
+		t.Fatalf("spoofed forwarded ip should not bypass the limit, got %d", res.Code)
+	}
+}
+
+func TestMaxAddressesPerIPUsesForwardedForFromTrustedProxy(t *testing.T) {
+	g := newIPLimitServer(t)
+
+	for i, forwarded := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		if res := claimFrom(t, g, "127.0.0.1:1234", forwarded, testAddress(i)); res.Code != global.SUCCESS {
+			t.Fatalf("claim %d via trusted proxy failed: %d %s", i, res.Code, res.Msg)
+		}
+	}
+}
+
+// 同一 /64 内更换 IPv6 地址计入同一个网段
+func TestMaxAddressesPerIPv6Prefix(t *testing.T) {
+	g := newIPLimitServer(t)
+
+	for i, remoteAddr := range []string{"[2001:db8:1:2::1]:1234", "[2001:db8:1:2::2]:1234"} {
+		if res := claimFrom(t, g, remoteAddr, "", testAddress(i)); res.Code != global.SUCCESS {
+			t.Fatalf("claim %d failed: %d %s", i, res.Code, res.Msg)
+		}
+	}
+	if res := claimFrom(t, g, "[2001:db8:1:2::3]:1234", "", testAddress(2)); res.Code != global.IPAddressLimitCode {
+		t.Fatalf("third address from the same /64 should be refused with %d, got %d", global.IPAddressLimitCode, res.Code)
+	}
+	if res := claimFrom(t, g, "[2001:db8:1:3::1]:1234", "", testAddress(2)); res.Code != global.SUCCESS {
+		t.Fatalf("claim from another /64 failed: %d %s", res.Code, res.Msg)
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+)
+
+// ClaimDetail 领取成功响应的附加信息：发送交易的资金账户地址、回执 id、查看交易的链接，以及开启 wait_for_receipt 时回执中的 gas 信息
+type ClaimDetail struct {
+	FundingAddress    string `json:"fundingAddress,omitempty"`
+	ReceiptID         string `json:"receiptId,omitempty"`
+	ExplorerURL       string `json:"explorerUrl,omitempty"`
+	Deeplink          string `json:"deeplink,omitempty"`
+	GasEstimate       uint64 `json:"gasEstimate,omitempty"`
+	GasUsed           uint64 `json:"gasUsed,omitempty"`
+	EffectiveGasPrice string `json:"effectiveGasPrice,omitempty"`
+}
+
+// claimSuccess 构造领取成功的响应，配置了链接模板时附带浏览器链接与钱包 deeplink。
+// 模板中 {txHash}、{address} 替换为交易哈希与领取地址，deeplink 中的 {explorerUrl} 替换为转义后的浏览器链接
+func (g *Server) claimSuccess(txHash string, net string, address string) *global.Response {
+	res := global.Success(txHash)
+	detail := g.claimLinks(txHash, address)
+	if detail == nil {
+		detail = &ClaimDetail{}
+	}
+	detail.FundingAddress = g.client.FundingAddress()
+	if data := g.client.LastClaim(net, strings.ToLower(address)); data != nil && data.TxHash == txHash {
+		if data.From != "" {
+			detail.FundingAddress = data.From
+		}
+		detail.ReceiptID = internal.ReceiptID(txHash)
+		if g.config.Axiom.WaitForReceipt {
+			detail.GasEstimate = data.GasEstimate
+			detail.GasUsed = data.GasUsed
+			detail.EffectiveGasPrice = data.EffectiveGasPrice
+		}
+	}
+	res.Detail = detail
+	return res
+}
+
+func (g *Server) claimLinks(txHash string, address string) *ClaimDetail {
+	explorerTemplate, deeplinkTemplate := g.config.Axiom.ExplorerTxURL, g.config.Axiom.WalletDeeplink
+	if explorerTemplate == "" && deeplinkTemplate == "" {
+		return nil
+	}
+	replacer := strings.NewReplacer("{txHash}", txHash, "{address}", address)
+	links := &ClaimDetail{}
+	if explorerTemplate != "" {
+		links.ExplorerURL = replacer.Replace(explorerTemplate)
+	}
+	if deeplinkTemplate != "" {
+		links.Deeplink = strings.ReplaceAll(replacer.Replace(deeplinkTemplate), "{explorerUrl}", url.QueryEscape(links.ExplorerURL))
+	}
+	return links
+}
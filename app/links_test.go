@@ -0,0 +1,105 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestDirectClaimReturnsLinks(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.ExplorerTxURL = "https://scan.example/tx/{txHash}"
+		cfg.Axiom.WalletDeeplink = "wallet://open?address={address}&url={explorerUrl}"
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &ClaimDetail{}
+	decodeDetail(t, res, detail)
+	explorer := "https://scan.example/tx/" + res.Data
+	if detail.ExplorerURL != explorer {
+		t.Fatalf("expect explorer url %s, got %s", explorer, detail.ExplorerURL)
+	}
+	if want := "wallet://open?address=" + testRecipient + "&url=" + url.QueryEscape(explorer); detail.Deeplink != want {
+		t.Fatalf("expect deeplink %s, got %s", want, detail.Deeplink)
+	}
+}
+
+func TestDirectClaimOmitsLinksWithoutTemplates(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &ClaimDetail{}
+	decodeDetail(t, res, detail)
+	if detail.ExplorerURL != "" || detail.Deeplink != "" {
+		t.Fatalf("links should be omitted without templates, got %+v", detail)
+	}
+}
+
+func TestDirectClaimReturnsReceiptGas(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.WaitForReceipt = true
+		cfg.Axiom.GasLimitMultiplier = 1.2
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &ClaimDetail{}
+	decodeDetail(t, res, detail)
+	if detail.GasUsed != 21000 || detail.EffectiveGasPrice != "1000000000" || detail.GasEstimate == 0 {
+		t.Fatalf("expect receipt gas in the response, got %+v", detail)
+	}
+	if detail.ReceiptID == "" || detail.FundingAddress == "" {
+		t.Fatalf("expect receipt id and funding address, got %+v", detail)
+	}
+}
+
+func TestDirectClaimOmitsGasWithoutWaitForReceipt(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &ClaimDetail{}
+	decodeDetail(t, res, detail)
+	if detail.GasUsed != 0 || detail.EffectiveGasPrice != "" {
+		t.Fatalf("gas should be omitted without wait_for_receipt, got %+v", detail)
+	}
+}
+
+func TestReceiptEndpoint(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &ClaimDetail{}
+	decodeDetail(t, res, detail)
+	if detail.ReceiptID != internal.ReceiptID(res.Data) {
+		t.Fatalf("claim should return receipt id %s, got %s", internal.ReceiptID(res.Data), detail.ReceiptID)
+	}
+
+	receipt := &internal.Receipt{}
+	decodeDetail(t, decodeResponse(t, serve(g, http.MethodGet, "/faucet/receipt/"+detail.ReceiptID, nil, nil)), receipt)
+	if receipt.TxHash != res.Data || receipt.Address != testRecipient {
+		t.Fatalf("unexpected receipt %+v", receipt)
+	}
+
+	if res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/receipt/missing", nil, nil)); res.Code != global.ReceiptNotFoundCode {
+		t.Fatalf("expect %d for unknown receipt, got %d", global.ReceiptNotFoundCode, res.Code)
+	}
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/utils"
+)
+
+// 日志中按 IP 段输出的最大条数，只输出拒绝次数最多的几个
+const maxMetricsIPBuckets = 20
+
+// limiterMetrics 按分钟分桶统计限流拒绝次数，只保留 window 内的桶，total 为启动以来的累计值
+type limiterMetrics struct {
+	lock    sync.Mutex
+	window  time.Duration
+	buckets []*rejectBucket
+	total   map[string]int64
+}
+
+type rejectBucket struct {
+	minute    int64
+	endpoints map[string]int64
+	ips       map[string]int64
+}
+
+type rejectCount struct {
+	Key   string
+	Count int64
+}
+
+func newLimiterMetrics(window time.Duration) *limiterMetrics {
+	if window < time.Minute {
+		window = time.Minute
+	}
+	return &limiterMetrics{window: window, total: make(map[string]int64)}
+}
+
+// record 记录一次限流拒绝，endpoint 为路由路径，未匹配路由的请求记为 unmatched
+func (m *limiterMetrics) record(c *gin.Context) {
+	endpoint := c.FullPath()
+	if endpoint == "" {
+		endpoint = "unmatched"
+	}
+	ipBucket := ipBucketOf(c.ClientIP())
+	now := time.Now()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.prune(now)
+	minute := now.Unix() / 60
+	if len(m.buckets) == 0 || m.buckets[len(m.buckets)-1].minute != minute {
+		m.buckets = append(m.buckets, &rejectBucket{minute: minute, endpoints: make(map[string]int64), ips: make(map[string]int64)})
+	}
+	bucket := m.buckets[len(m.buckets)-1]
+	bucket.endpoints[endpoint]++
+	bucket.ips[ipBucket]++
+	m.total[endpoint]++
+}
+
+func (m *limiterMetrics) prune(now time.Time) {
+	oldest := now.Add(-m.window).Unix() / 60
+	i := 0
+	for i < len(m.buckets) && m.buckets[i].minute <= oldest {
+		i++
+	}
+	m.buckets = m.buckets[i:]
+}
+
+// snapshot 返回窗口内按接口、按 IP 段统计的拒绝次数，按次数从多到少排序
+func (m *limiterMetrics) snapshot() (endpoints []rejectCount, ips []rejectCount, total []rejectCount) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.prune(time.Now())
+	endpointCounts := make(map[string]int64)
+	ipCounts := make(map[string]int64)
+	for _, bucket := range m.buckets {
+		for endpoint, count := range bucket.endpoints {
+			endpointCounts[endpoint] += count
+		}
+		for ip, count := range bucket.ips {
+			ipCounts[ip] += count
+		}
+	}
+	return sortedCounts(endpointCounts), sortedCounts(ipCounts), sortedCounts(m.total)
+}
+
+func sortedCounts(counts map[string]int64) []rejectCount {
+	result := make([]rejectCount, 0, len(counts))
+	for key, count := range counts {
+		result = append(result, rejectCount{Key: key, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// ipBucketOf IPv4 按 /24、IPv6 按 /64 聚合，同一网段的请求计入同一个桶
+func ipBucketOf(ip string) string {
+	return utils.IPBucket(ip, 24, 64)
+}
+
+// metrics 以 Prometheus 文本格式输出限流拒绝次数、rpc 服务商限流次数、缓存的水龙头余额与领取耗时，
+// /metrics 无需鉴权，按 IP 段的拒绝次数只写入日志，不在此输出
+func (g *Server) metrics(c *gin.Context) {
+	endpoints, _, total := g.limiterMetrics.snapshot()
+	window := g.limiterMetrics.window.String()
+	var b strings.Builder
+	b.WriteString("# HELP faucet_ratelimit_rejections_total Rate limit rejections since start by endpoint.\n")
+	b.WriteString("# TYPE faucet_ratelimit_rejections_total counter\n")
+	for _, count := range total {
+		fmt.Fprintf(&b, "faucet_ratelimit_rejections_total{endpoint=%q} %d\n", count.Key, count.Count)
+	}
+	b.WriteString("# HELP faucet_ratelimit_rejections_window Rate limit rejections in the rolling window by endpoint.\n")
+	b.WriteString("# TYPE faucet_ratelimit_rejections_window gauge\n")
+	for _, count := range endpoints {
+		fmt.Fprintf(&b, "faucet_ratelimit_rejections_window{endpoint=%q,window=%q} %d\n", count.Key, window, count.Count)
+	}
+	b.WriteString("# HELP faucet_rpc_throttled_total RPC provider rate limit responses since start.\n")
+	b.WriteString("# TYPE faucet_rpc_throttled_total counter\n")
+	fmt.Fprintf(&b, "faucet_rpc_throttled_total %d\n", g.client.RPCThrottledCount())
+	if balance, ok := g.client.CachedBalance(); ok {
+		b.WriteString("# HELP faucet_balance Faucet contract balance in ether from the balance cache.\n")
+		b.WriteString("# TYPE faucet_balance gauge\n")
+		fmt.Fprintf(&b, "faucet_balance{net=%q,faucet=%q,funding_address=%q} %g\n", g.config.Axiom.TestNetName, g.config.Axiom.FaucetAddr, g.client.FundingAddress(), balance)
+	}
+	writeLatencyMetrics(&b, g.client.ClaimLatency())
+	c.Data(200, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+// writeLatencyMetrics 输出领取发出、确认耗时的 histogram 与确认耗时 SLO 的达标数和总数，达标比例为 good / total
+func writeLatencyMetrics(b *strings.Builder, latency *internal.ClaimLatency) {
+	writeHistogram(b, "faucet_claim_submit_seconds", "Time from receiving a claim to sending its tx.", latency.Submit)
+	writeHistogram(b, "faucet_claim_confirm_seconds", "Time from sending a claim tx to finding its successful receipt.", latency.Confirm)
+	slo := strconv.FormatFloat(latency.SLO.Seconds(), 'g', -1, 64)
+	b.WriteString("# HELP faucet_claim_slo_total Claim txs sent since start, the denominator of the confirmation SLO.\n")
+	b.WriteString("# TYPE faucet_claim_slo_total counter\n")
+	fmt.Fprintf(b, "faucet_claim_slo_total{slo=%q} %d\n", slo, latency.SLOTotal)
+	b.WriteString("# HELP faucet_claim_slo_good_total Claim txs confirmed within the SLO since start.\n")
+	b.WriteString("# TYPE faucet_claim_slo_good_total counter\n")
+	fmt.Fprintf(b, "faucet_claim_slo_good_total{slo=%q} %d\n", slo, latency.SLOGood)
+	b.WriteString("# HELP faucet_claim_unconfirmed_total Claim txs whose successful receipt was not found in time since start.\n")
+	b.WriteString("# TYPE faucet_claim_unconfirmed_total counter\n")
+	fmt.Fprintf(b, "faucet_claim_unconfirmed_total %d\n", latency.Unconfirmed)
+}
+
+func writeHistogram(b *strings.Builder, name string, help string, h internal.LatencyHistogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	for i, bound := range h.Bounds {
+		fmt.Fprintf(b, "%s_bucket{le=%q} %d\n", name, strconv.FormatFloat(bound, 'g', -1, 64), h.Counts[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{le=\"+Inf\"} %d\n", name, h.Count)
+	fmt.Fprintf(b, "%s_sum %g\n", name, h.Sum)
+	fmt.Fprintf(b, "%s_count %d\n", name, h.Count)
+}
+
+// logLimiterMetrics 定期将窗口内有拒绝的接口和 IP 段写入日志
+func (g *Server) logLimiterMetrics(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case <-ticker.C:
+			endpoints, ips, _ := g.limiterMetrics.snapshot()
+			if len(endpoints) == 0 {
+				continue
+			}
+			if len(ips) > maxMetricsIPBuckets {
+				ips = ips[:maxMetricsIPBuckets]
+			}
+			g.logger.Infof("rate limit rejections in last %s, endpoints: %v, ip buckets: %v", g.limiterMetrics.window, endpoints, ips)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestMetricsReportsRateLimitRejections(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableMetrics = true
+		cfg.Network.RateLimit.Read = 2
+	})
+
+	waitNextSecond()
+	for i := 0; i < 3; i++ {
+		serve(g, http.MethodGet, "/faucet/version", nil, nil)
+	}
+	w := serve(g, http.MethodGet, "/metrics", nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expect 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`faucet_ratelimit_rejections_total{endpoint="/faucet/version"} 2`,
+		`faucet_ratelimit_rejections_window{endpoint="/faucet/version",window="10m0s"} 2`,
+		"faucet_rpc_throttled_total 0",
+		"faucet_claim_slo_total",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics should contain %q, got:\n%s", want, body)
+		}
+	}
+	// 公开的 /metrics 不输出客户端 IP 段
+	if strings.Contains(body, "192.0.2") {
+		t.Fatalf("metrics should not expose client ip buckets, got:\n%s", body)
+	}
+}
+
+// 查询过余额后输出缓存的水龙头余额
+func TestMetricsReportsCachedBalance(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableMetrics = true
+	})
+
+	if body := serve(g, http.MethodGet, "/metrics", nil, nil).Body.String(); strings.Contains(body, "faucet_balance{") {
+		t.Fatalf("balance should be omitted before it is queried, got:\n%s", body)
+	}
+	serve(g, http.MethodGet, "/faucet/status", nil, nil)
+	body := serve(g, http.MethodGet, "/metrics", nil, nil).Body.String()
+	want := fmt.Sprintf("faucet_balance{net=%q,faucet=%q,funding_address=%q} 1e+06", g.config.Axiom.TestNetName, g.config.Axiom.FaucetAddr, g.client.FundingAddress())
+	if !strings.Contains(body, want) {
+		t.Fatalf("metrics should contain %q, got:\n%s", want, body)
+	}
+}
+
+func TestMetricsReportsClaimLatency(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableMetrics = true
+		cfg.Network.LatencyBuckets = []float64{1, 5}
+		cfg.Network.ClaimSLO = repo.Duration(5 * time.Second)
+	})
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+
+	body := serve(g, http.MethodGet, "/metrics", nil, nil).Body.String()
+	for _, want := range []string{
+		"# TYPE faucet_claim_submit_seconds histogram",
+		`faucet_claim_confirm_seconds_bucket{le="5"} 1`,
+		`faucet_claim_confirm_seconds_bucket{le="+Inf"} 1`,
+		"faucet_claim_confirm_seconds_count 1",
+		`faucet_claim_slo_total{slo="5"} 1`,
+		`faucet_claim_slo_good_total{slo="5"} 1`,
+		"faucet_claim_unconfirmed_total 0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics should contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.EnableMetrics = false
+	})
+
+	if w := serve(g, http.MethodGet, "/metrics", nil, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("expect 404 when metrics are disabled, got %d", w.Code)
+	}
+}
+
+func TestLimiterMetricsWindow(t *testing.T) {
+	m := newLimiterMetrics(time.Minute)
+	for _, remoteAddr := range []string{"203.0.113.7:1", "203.0.113.8:1", "198.51.100.1:1"} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.RemoteAddr = remoteAddr
+		m.record(c)
+	}
+	endpoints, ips, _ := m.snapshot()
+	if len(endpoints) != 1 || endpoints[0] != (rejectCount{Key: "unmatched", Count: 3}) {
+		t.Fatalf("unexpected endpoint counts %v", endpoints)
+	}
+	if len(ips) != 2 || ips[0] != (rejectCount{Key: ipBucketOf("203.0.113.7"), Count: 2}) {
+		t.Fatalf("ips of the same /24 should share a bucket, got %v", ips)
+	}
+
+	// 超出窗口的桶被清理，累计值保留
+	m.buckets[0].minute -= 2
+	endpoints, _, total := m.snapshot()
+	if len(endpoints) != 0 || len(total) != 1 || total[0].Count != 3 {
+		t.Fatalf("expect window to expire and total to be kept, got %v %v", endpoints, total)
+	}
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// mockClaim 模拟领取接口，只在 debug/test 模式下挂载，不访问链和存储。
+// 通过 query 参数 code 指定返回的错误码，未指定时返回成功
+func (g *Server) mockClaim(c *gin.Context) {
+	var directClaimInput global.DirectClaimReq
+	if !bindJSON(c, &directClaimInput) {
+		return
+	}
+	if judge := IsValidEthereumAddress(directClaimInput.Address); !judge {
+		global.Result(global.Fail(global.ErrAddrCode, global.ErrAddrMsg+directClaimInput.Address), c)
+		return
+	}
+
+	code, err := strconv.Atoi(c.DefaultQuery("code", strconv.Itoa(global.SUCCESS)))
+	if err != nil {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+	if code == global.SUCCESS {
+		// 由地址生成固定的交易哈希，相同输入得到相同结果
+		txHash := common.BytesToHash(crypto.Keccak256([]byte(directClaimInput.Net + directClaimInput.Address))).Hex()
+		res := global.Success(txHash)
+		if links := g.claimLinks(txHash, directClaimInput.Address); links != nil {
+			res.Detail = links
+		}
+		global.Result(res, c)
+		return
+	}
+	msg, ok := global.Message(code)
+	if !ok {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+	global.Result(global.Fail(code, msg), c)
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+// withGinMode 按 GIN_MODE 创建服务，结束后恢复 release 模式
+func withGinMode(t *testing.T, mode string) {
+	t.Helper()
+	t.Setenv(gin.EnvGinMode, mode)
+	gin.SetMode(mode)
+	t.Cleanup(func() { gin.SetMode(gin.ReleaseMode) })
+}
+
+func TestMockClaimInTestMode(t *testing.T) {
+	withGinMode(t, gin.TestMode)
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, nil)
+	body := global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}
+
+	first := decodeResponse(t, serve(g, http.MethodPost, "/faucet/mockClaim", body, nil))
+	second := decodeResponse(t, serve(g, http.MethodPost, "/faucet/mockClaim", body, nil))
+	if first.Code != global.SUCCESS || first.Data == "" || first.Data != second.Data {
+		t.Fatalf("mock claim should return the same tx hash for the same input, got %q and %q", first.Data, second.Data)
+	}
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/mockClaim?code="+strconv.Itoa(global.ReqWithinDayCode), body, nil))
+	if res.Code != global.ReqWithinDayCode || res.Msg != global.ReqWithinDayMsg {
+		t.Fatalf("expect the requested error %d, got %d %s", global.ReqWithinDayCode, res.Code, res.Msg)
+	}
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/mockClaim?code=1", body, nil)); res.Code != global.ParseErrCode {
+		t.Fatalf("unknown code should be rejected, got %d", res.Code)
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("mock claim should not send txs, got %d", len(sent))
+	}
+}
+
+func TestMockClaimNotMountedInReleaseMode(t *testing.T) {
+	withGinMode(t, gin.ReleaseMode)
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	w := serve(g, http.MethodPost, "/faucet/mockClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("mock claim should not be mounted in release mode, got %d", w.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+)
+
+// checkEmail 校验选填的回执邮箱，返回规范化后的邮箱地址，格式错误时返回失败响应
+func checkEmail(c *gin.Context, email string) (string, bool) {
+	if email == "" {
+		return "", true
+	}
+	parsed, err := mail.ParseAddress(email)
+	if err != nil {
+		global.Result(global.Fail(global.InvalidEmailCode, global.InvalidEmailMsg), c)
+		return "", false
+	}
+	return parsed.Address, true
+}
+
+// mailReceipt 领取成功后发送回执，数量取领取记录中实际发放的数量，找不到对应记录时不发送
+func (g *Server) mailReceipt(email string, net string, address string, txHash string) {
+	if email == "" {
+		return
+	}
+	data := g.client.LastClaim(net, strings.ToLower(address))
+	if data == nil || data.TxHash != txHash {
+		g.logger.Warnf("claim record of tx %s not found, skip mailing receipt to %s", txHash, email)
+		return
+	}
+	receipt := &internal.ClaimReceipt{Net: net, Address: address, Amount: data.Amount, TxHash: txHash}
+	if links := g.claimLinks(txHash, address); links != nil {
+		receipt.ExplorerURL = links.ExplorerURL
+	}
+	g.client.MailReceipt(email, receipt)
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+type sentMail struct {
+	to   string
+	body string
+}
+
+// chanMailer 将发送的邮件写入 channel
+type chanMailer chan sentMail
+
+func (m chanMailer) Send(to string, subject string, body string) error {
+	m <- sentMail{to: to, body: body}
+	return nil
+}
+
+func TestDirectClaimMailsReceipt(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.ExplorerTxURL = "https://scan.example/tx/{txHash}"
+	})
+	mailer := make(chanMailer, 1)
+	g.client.SetMailer(mailer)
+
+	req := global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName, Email: "User <user@example.com>"}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", req, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	select {
+	case mail := <-mailer:
+		if mail.to != "user@example.com" {
+			t.Fatalf("receipt should be sent to the parsed address, got %s", mail.to)
+		}
+		if !strings.Contains(mail.body, "Transaction: "+res.Data) || !strings.Contains(mail.body, "Explorer: https://scan.example/tx/"+res.Data) {
+			t.Fatalf("receipt should contain the tx and its link, got %q", mail.body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("receipt was not sent")
+	}
+}
+
+func TestDirectClaimRejectsInvalidEmail(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, nil)
+
+	req := global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName, Email: "not an email"}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", req, nil))
+	if res.Code != global.InvalidEmailCode {
+		t.Fatalf("expect %d, got %d %s", global.InvalidEmailCode, res.Code, res.Msg)
+	}
+	if len(node.Sent()) != 0 {
+		t.Fatal("invalid email should be rejected before sending")
+	}
+}
+
+// 未填写邮箱时不发送回执
+func TestDirectClaimWithoutEmail(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	mailer := make(chanMailer, 1)
+	g.client.SetMailer(mailer)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	select {
+	case mail := <-mailer:
+		t.Fatalf("no receipt should be sent, got one to %s", mail.to)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/pkg/audit"
+)
+
+// reserveReferral 开启推荐码时校验并占用一次使用次数，返回推荐码的额外奖励，校验失败时返回错误响应
+func (g *Server) reserveReferral(net string, code string) (float64, *global.Response) {
+	if !g.config.Referral.Enable {
+		return 0, nil
+	}
+	bonus, errCode, err := g.client.ReserveReferral(net, code)
+	if err != nil {
+		return 0, global.Fail(errCode, err.Error())
+	}
+	return bonus, nil
+}
+
+// settleReferral 领取成功时记录推荐码归因，失败时归还占用的使用次数
+func (g *Server) settleReferral(net string, code string, address string, txHash string, err error) {
+	if !g.config.Referral.Enable {
+		return
+	}
+	if err != nil {
+		g.client.ReleaseReferral(net, code)
+		return
+	}
+	g.client.RecordReferral(net, code, address, txHash)
+}
+
+type referralDetail struct {
+	*internal.ReferralCode
+	Claims []*internal.ReferralUse `json:"claims"`
+}
+
+func (g *Server) createReferralCode(c *gin.Context) {
+	var referralReq global.ReferralCodeReq
+	if !bindJSON(c, &referralReq) {
+		return
+	}
+	if referralReq.MaxUses < 0 || referralReq.Bonus < 0 || referralReq.Bonus > g.config.Referral.MaxBonus {
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return
+	}
+
+	net := g.config.Axiom.TestNetName
+	referral, err := g.client.CreateReferralCode(net, referralReq.Code, referralReq.MaxUses, referralReq.Bonus)
+	if err != nil {
+		g.requestLogger(c).Warn(err)
+		global.Result(global.Fail(global.ReferralErrCode, global.ReferralErrMsg+referralReq.Code), c)
+		return
+	}
+	g.requestLogger(c).Infof("admin created referral code %s, max uses: %d, bonus: %v", referral.Code, referral.MaxUses, referral.Bonus)
+	g.client.Audit(g.requestContext(c), &audit.Event{Type: audit.EventAdminAction, Net: net, Amount: referral.Bonus, Action: "create_referral", Detail: referral.Code})
+	global.Result(global.SuccessDetail(referral), c)
+}
+
+func (g *Server) getReferralCode(c *gin.Context) {
+	net := g.config.Axiom.TestNetName
+	code := c.Param("code")
+	referral := g.client.GetReferralCode(net, code)
+	if referral == nil {
+		global.Result(global.Fail(global.ReferralErrCode, global.ReferralErrMsg+code), c)
+		return
+	}
+
+	global.Result(global.SuccessDetail(&referralDetail{ReferralCode: referral, Claims: g.client.ReferralUses(net, code)}), c)
+}
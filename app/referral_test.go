@@ -0,0 +1,151 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newReferralServer(t *testing.T) *Server {
+	t.Helper()
+	return newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+		cfg.Referral = repo.Referral{Enable: true, MaxBonus: 10, Codes: []repo.ReferralCode{{Code: "friends", MaxUses: 2, Bonus: 1}}}
+	})
+}
+
+func fetchReferral(t *testing.T, g *Server, code string) *referralDetail {
+	t.Helper()
+	res := decodeResponse(t, serve(g, http.MethodGet, "/faucet/admin/referral/"+code, nil, adminHeader()))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("get referral failed: %d %s", res.Code, res.Msg)
+	}
+	detail := &referralDetail{}
+	decodeDetail(t, res, detail)
+	return detail
+}
+
+func TestDirectClaimWithReferral(t *testing.T) {
+	g := newReferralServer(t)
+	net := g.config.Axiom.TestNetName
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testAddress(0), Net: net, Referral: "friends"}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	if data := g.client.LastClaim(net, testAddress(0)); data == nil || data.Amount != g.config.Axiom.Amount+1 {
+		t.Fatalf("claim should include the referral bonus, got %+v", data)
+	}
+	detail := fetchReferral(t, g, "friends")
+	if detail.Uses != 1 || len(detail.Claims) != 1 || detail.Claims[0].TxHash != res.Data {
+		t.Fatalf("claim should be attributed to the code, got %+v %+v", detail.ReferralCode, detail.Claims)
+	}
+
+	// 领取失败时归还使用次数
+	res = decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testAddress(0), Net: net, Referral: "friends"}, nil))
+	if res.Code != global.ReqWithinDayCode {
+		t.Fatalf("expect %d, got %d", global.ReqWithinDayCode, res.Code)
+	}
+	if detail := fetchReferral(t, g, "friends"); detail.Uses != 1 {
+		t.Fatalf("failed claim should release its use, got %d", detail.Uses)
+	}
+}
+
+func TestDirectClaimRequiresValidReferral(t *testing.T) {
+	g := newReferralServer(t)
+	net := g.config.Axiom.TestNetName
+
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testAddress(0), Net: net}, nil)); res.Code != global.ReferralErrCode {
+		t.Fatalf("claim without code should be refused with %d, got %d", global.ReferralErrCode, res.Code)
+	}
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/admin/referral", global.ReferralCodeReq{Code: "greedy", Bonus: 11}, adminHeader())); res.Code != global.ParseErrCode {
+		t.Fatalf("bonus above max_bonus should be rejected, got %d", res.Code)
+	}
+}
+
+// 推荐码奖励在发放策略之后叠加，不随余额分档缩放，回执邮件中为实际发放数量
+func TestReferralBonusNotScaledByAmountPolicies(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Referral = repo.Referral{Enable: true, MaxBonus: 10, Codes: []repo.ReferralCode{{Code: "friends", MaxUses: 2, Bonus: 1}}}
+		cfg.Axiom.BalanceTiers = []repo.BalanceTier{{Above: 0, Multiplier: 0.5}}
+	})
+	mailer := make(chanMailer, 1)
+	g.client.SetMailer(mailer)
+	net := g.config.Axiom.TestNetName
+
+	req := global.DirectClaimReq{Address: testAddress(0), Net: net, Referral: "friends", Email: "user@example.com"}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", req, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	want := g.config.Axiom.Amount*0.5 + 1
+	if data := g.client.LastClaim(net, testAddress(0)); data == nil || data.Amount != want {
+		t.Fatalf("expect amount %v, got %+v", want, data)
+	}
+	select {
+	case mail := <-mailer:
+		if !strings.Contains(mail.body, "Amount: "+strconv.FormatFloat(want, 'f', -1, 64)+"\r\n") {
+			t.Fatalf("receipt should report the amount sent, got %q", mail.body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("receipt was not sent")
+	}
+}
+
+// 签名领取同样需要推荐码，签名校验失败时不占用使用次数
+func TestSignatureClaimWithReferral(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, func(cfg *repo.Config) {
+		cfg.Admin.Token = testAdminToken
+		cfg.SignatureClaim = repo.SignatureClaim{Enable: true, Window: repo.Duration(time.Minute)}
+		cfg.Referral = repo.Referral{Enable: true, MaxBonus: 10, Codes: []repo.ReferralCode{{Code: "friends", MaxUses: 2, Bonus: 1}}}
+	})
+	net := g.config.Axiom.TestNetName
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	timestamp := time.Now().Unix()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(internal.ClaimMessage(net, address, node.ChainID(), timestamp))), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := global.SignatureClaimReq{Net: net, Address: address, ChainId: node.ChainID(), Timestamp: timestamp, Signature: hexutil.Encode(sig)}
+
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/signatureClaim", req, nil)); res.Code != global.ReferralErrCode {
+		t.Fatalf("claim without code should be refused with %d, got %d", global.ReferralErrCode, res.Code)
+	}
+	bad := req
+	bad.Referral = "friends"
+	bad.Timestamp = timestamp - 1
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/signatureClaim", bad, nil)); res.Code != global.SignatureErrCode {
+		t.Fatalf("expect %d, got %d", global.SignatureErrCode, res.Code)
+	}
+	if detail := fetchReferral(t, g, "friends"); detail.Uses != 0 {
+		t.Fatalf("rejected signature should not use the code, got %d", detail.Uses)
+	}
+
+	req.Referral = "friends"
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/signatureClaim", req, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	if data := g.client.LastClaim(net, strings.ToLower(address)); data == nil || data.Amount != g.config.Axiom.Amount+1 {
+		t.Fatalf("claim should include the referral bonus, got %+v", data)
+	}
+	if detail := fetchReferral(t, g, "friends"); detail.Uses != 1 || len(detail.Claims) != 1 || detail.Claims[0].TxHash != res.Data {
+		t.Fatalf("claim should be attributed to the code, got %+v %+v", detail.ReferralCode, detail.Claims)
+	}
+}
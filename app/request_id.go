@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+)
+
+// 请求头中关联 ID 的最大长度，超过时重新生成
+const maxRequestIDLength = 128
+
+// RequestID 为每个请求生成关联 ID，请求头中带有合法的 X-Request-ID 时沿用，并在响应头中返回
+func (g *Server) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(global.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(global.RequestIDKey, id)
+		c.Header(global.RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID 只接受可打印的 ASCII 字符，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// requestLogger 带有请求关联 ID 的日志
+func (g *Server) requestLogger(c *gin.Context) logrus.FieldLogger {
+	if id := c.GetString(global.RequestIDKey); id != "" {
+		return g.logger.WithField("request_id", id)
+	}
+	return g.logger
+}
+
+// requestContext 把请求的关联 ID 和客户端 IP 放入 context，传给 client 用于日志、审计和按 IP 的限制
+func (g *Server) requestContext(c *gin.Context) context.Context {
+	return internal.WithRequest(c.Request.Context(), internal.Request{ID: c.GetString(global.RequestIDKey), IP: c.ClientIP()})
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestRequestIDGenerated(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	first := serve(g, http.MethodGet, "/faucet/config", nil, nil).Header().Get(global.RequestIDHeader)
+	second := serve(g, http.MethodGet, "/faucet/config", nil, nil).Header().Get(global.RequestIDHeader)
+	if len(first) != 32 || first == second {
+		t.Fatalf("each request should get a new id, got %q and %q", first, second)
+	}
+}
+
+func TestRequestIDEchoed(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	w := serve(g, http.MethodGet, "/faucet/config", nil, http.Header{http.CanonicalHeaderKey(global.RequestIDHeader): {"trace-123"}})
+	if id := w.Header().Get(global.RequestIDHeader); id != "trace-123" {
+		t.Fatalf("valid request id should be echoed, got %q", id)
+	}
+}
+
+// 非法的关联 ID 重新生成，避免写入日志
+func TestRequestIDReplacesInvalid(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	for _, id := range []string{"has space", "tab\tid", "中文", strings.Repeat("a", maxRequestIDLength+1)} {
+		w := serve(g, http.MethodGet, "/faucet/config", nil, http.Header{http.CanonicalHeaderKey(global.RequestIDHeader): {id}})
+		if got := w.Header().Get(global.RequestIDHeader); got == id || len(got) != 32 {
+			t.Fatalf("invalid request id %q should be replaced, got %q", id, got)
+		}
+	}
+	if !validRequestID(strings.Repeat("a", maxRequestIDLength)) {
+		t.Fatal("request id of the max length should be accepted")
+	}
+}
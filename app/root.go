@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// 根路径的落地页，方便运维确认服务是否在运行
+const rootPage = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%[1]s</title></head>
+<body>
+<h1>%[1]s</h1>
+<p>version: %[2]s</p>
+<p><a href="/faucet/status">/faucet/status</a></p>
+</body>
+</html>
+`
+
+type rootInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// root 浏览器访问返回 HTML，其他请求返回 JSON，不经过限流
+func (g *Server) root(c *gin.Context) {
+	info := &rootInfo{
+		Name:    repo.AppName,
+		Version: repo.BuildVersion,
+		Status:  "/faucet/status",
+	}
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(rootPage, html.EscapeString(info.Name), html.EscapeString(info.Version))))
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestRootServesHTMLToBrowsers(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	w := serve(g, http.MethodGet, "/", nil, http.Header{"Accept": {"text/html,application/xhtml+xml,*/*;q=0.8"}})
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("expect html, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if body := w.Body.String(); !strings.Contains(body, repo.AppName) || !strings.Contains(body, repo.BuildVersion) {
+		t.Fatalf("page should show name and version, got %s", body)
+	}
+}
+
+func TestRootServesJSON(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	w := serve(g, http.MethodGet, "/", nil, http.Header{"Accept": {"application/json"}})
+	info := &rootInfo{}
+	if err := json.Unmarshal(w.Body.Bytes(), info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != repo.AppName || info.Version != repo.BuildVersion || info.Status != "/faucet/status" {
+		t.Fatalf("unexpected root info %+v", info)
+	}
+	// 根路径同样经过请求 ID 等中间件
+	if w.Header().Get(global.RequestIDHeader) == "" {
+		t.Fatal("root should get a request id")
+	}
+}
+
+// 根路径不受全局限流影响，其他接口照常限流
+func TestRootSkipsGlobalLimiter(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.RateLimit.Global = 2
+	})
+
+	waitNextSecond()
+	for i := 0; i < 5; i++ {
+		if w := serve(g, http.MethodGet, "/", nil, nil); w.Code != http.StatusOK {
+			t.Fatalf("root request %d should not be limited, got %d", i, w.Code)
+		}
+	}
+	serve(g, http.MethodGet, "/faucet/version", nil, nil)
+	if w := serve(g, http.MethodGet, "/faucet/version", nil, nil); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("other endpoints should be limited, got %d", w.Code)
+	}
+}
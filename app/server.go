@@ -1,11 +1,19 @@
 package app
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -27,37 +35,131 @@ type Server struct {
 	logger logrus.FieldLogger
 	client *internal.Client
 
+	httpServer *http.Server
+
+	tweetURLRegex  *regexp.Regexp
+	overview       *overviewCache
+	limiterMetrics *limiterMetrics
+	supportedNets  *UnsupportedNet
+	dedupe         *claimDedupe
+	signatures     *signatureCache
+
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
 func NewServer(client *internal.Client, config *repo.Config) (*Server, error) {
+	tweetURLRegex, err := compileTweetURLRegex(config.Scrapper.TweetDomains)
+	if err != nil {
+		return nil, err
+	}
 	ctx, cancel := context.WithCancel(context.Background())
-	gin.SetMode(gin.ReleaseMode)
+	// 默认以 release 模式运行，开发环境可以通过 GIN_MODE 指定 debug/test
+	if os.Getenv(gin.EnvGinMode) == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	router := gin.New()
+	if err := router.SetTrustedProxies(config.Network.TrustedProxies); err != nil {
+		cancel()
+		return nil, fmt.Errorf("set trusted proxies: %w", err)
+	}
 	return &Server{
-		config: config,
-		router: router,
-		client: client,
-		ctx:    ctx,
-		cancel: cancel,
-		logger: loggers.Logger(loggers.ApiServer),
+		config:         config,
+		router:         router,
+		client:         client,
+		tweetURLRegex:  tweetURLRegex,
+		overview:       &overviewCache{},
+		limiterMetrics: newLimiterMetrics(config.Network.LimiterMetricsWindow.ToDuration()),
+		supportedNets:  &UnsupportedNet{SupportedNets: []string{config.Axiom.TestNetName}},
+		dedupe:         newClaimDedupe(config.Network.ClaimDedupeWindow.ToDuration()),
+		signatures:     newSignatureCache(),
+		ctx:            ctx,
+		cancel:         cancel,
+		logger:         loggers.Logger(loggers.ApiServer),
 	}, nil
 }
 
 func (g *Server) Start() error {
-	g.router.Use(gin.Recovery()).Use(cors.Default()).Use(g.MaxAllowed(200))
+	rateLimit := g.config.Network.RateLimit
+	// 限流在 cors 之前执行，预检请求经过限流器时按 OPTIONS 放行，再由 cors 应答
+	g.router.Use(g.RequestID()).Use(gin.Recovery()).Use(skipRoot(g.MaxAllowed(rateLimit.Global))).Use(cors.Default())
+	g.router.GET("/", g.root)
 	v := g.router.Group("/faucet")
 	{
-		v.POST("directClaim", g.directClaim)
-		v.POST("tweetClaim", g.tweetClaim)
-		v.POST("preCheck", g.preCheck)
+		v.POST("directClaim", g.MaxAllowedPerNet(rateLimit.DirectClaim), g.VerifySignature(), g.CheckMaintenance(), g.directClaim)
+		v.POST("tweetClaim", g.MaxAllowedPerNet(rateLimit.TweetClaim), g.VerifySignature(), g.CheckMaintenance(), g.tweetClaim)
+		v.POST("preCheck", g.MaxAllowedPerNet(rateLimit.PreCheck), g.MinIntervalPerIP(rateLimit.PreCheckInterval.ToDuration()), g.preCheck)
+		v.GET("stats", g.MaxAllowed(rateLimit.Read), g.Compress(), g.CacheControl(g.config.Network.StatsCacheTTL.ToDuration()), g.stats)
+		v.GET("config", g.MaxAllowed(rateLimit.Read), g.Compress(), g.CacheControl(g.config.Network.ConfigCacheTTL.ToDuration()), g.publicConfig)
+		v.GET("status", g.MaxAllowed(rateLimit.Read), g.Compress(), g.CacheControl(g.config.Network.StatusCacheTTL.ToDuration()), g.status)
+		v.GET("history", g.MaxAllowed(rateLimit.Read), g.Compress(), g.history)
+		v.GET("verifyAddress", g.MaxAllowed(rateLimit.Read), g.verifyAddress)
+		v.GET("version", g.MaxAllowed(rateLimit.Read), g.version)
+		v.GET("receipt/:id", g.MaxAllowed(rateLimit.Read), g.receipt)
+		if gin.Mode() != gin.ReleaseMode {
+			v.POST("mockClaim", g.mockClaim)
+		}
+		if g.config.Network.EnableExport {
+			v.GET("export", g.MaxAllowed(rateLimit.Read), g.Compress(), g.export)
+		}
+		if len(g.config.Axiom.Tokens) > 0 {
+			v.POST("multiClaim", g.MaxAllowedPerNet(rateLimit.DirectClaim), g.VerifySignature(), g.CheckMaintenance(), g.multiClaim)
+		}
+		if g.config.Queue.Enable {
+			v.POST("claimAsync", g.MaxAllowedPerNet(rateLimit.DirectClaim), g.VerifySignature(), g.CheckMaintenance(), g.claimAsync)
+			v.GET("claim/:id", g.MaxAllowed(rateLimit.Read), g.claimTicket)
+		}
+		// claim/:id 的 id 为排队凭证，claim/:id/events 的 id 为交易哈希，两者须使用相同的路由参数名
+		if g.config.ClaimEvents.Enable {
+			v.GET("claim/:id/events", g.MaxAllowed(rateLimit.Read), g.claimEvents)
+		}
+		if g.config.SignatureClaim.Enable {
+			v.POST("signatureClaim", g.MaxAllowedPerNet(rateLimit.DirectClaim), g.VerifySignature(), g.CheckMaintenance(), g.signatureClaim)
+		}
+		if g.config.AuthorizedClaim.Enable {
+			v.POST("authorizedClaim", g.MaxAllowedPerNet(rateLimit.AuthorizedClaim), g.VerifySignature(), g.CheckMaintenance(), g.authorizedClaim)
+		}
 	}
 
+	if g.config.Network.EnableMetrics {
+		g.router.GET("/metrics", g.metrics)
+		if interval := g.config.Network.LimiterMetricsLogInterval.ToDuration(); interval > 0 {
+			go g.logLimiterMetrics(interval)
+		}
+	}
+
+	if g.config.Admin.Token != "" {
+		admin := v.Group("/admin", g.AdminAuth())
+		{
+			admin.GET("overview", g.adminOverview)
+			admin.GET("claims", g.adminClaims)
+			admin.POST("claim", g.adminClaim)
+			admin.POST("funding-key", g.rotateFundingKey)
+			admin.GET("address/:address/note", g.getAddressNote)
+			admin.PUT("address/:address/note", g.setAddressNote)
+			admin.DELETE("address/:address/note", g.deleteAddressNote)
+			admin.POST("referral", g.createReferralCode)
+			admin.GET("referral/:code", g.getReferralCode)
+		}
+	}
+
+	if g.config.Queue.Enable {
+		g.client.StartQueue()
+	}
+
+	network := g.config.Network
+	g.httpServer = &http.Server{
+		Addr:              fmt.Sprintf(":%s", network.Port),
+		Handler:           g.router,
+		ReadTimeout:       network.ReadTimeout.ToDuration(),
+		ReadHeaderTimeout: network.ReadHeaderTimeout.ToDuration(),
+		WriteTimeout:      network.WriteTimeout.ToDuration(),
+		IdleTimeout:       network.IdleTimeout.ToDuration(),
+	}
 	go func() {
 		g.logger.Infoln("start gin success")
-		err := g.router.Run(fmt.Sprintf(":%s", g.client.Config.Network.Port))
-		if err != nil {
+		err := g.httpServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			g.logger.Error(err)
 			panic(err)
 		}
@@ -68,72 +170,232 @@ func (g *Server) Start() error {
 
 func (g *Server) directClaim(c *gin.Context) {
 	var directClaimInput global.DirectClaimReq
-	if err := c.BindJSON(&directClaimInput); err != nil {
-		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+	if !bindJSON(c, &directClaimInput) {
+		return
+	}
+
+	net, ok := g.validateClaim(c, directClaimInput.Address, directClaimInput.Net, directClaimInput.Source, nil)
+	if !ok {
+		return
+	}
+	directClaimInput.Net = net
+	email, ok := checkEmail(c, directClaimInput.Email)
+	if !ok {
+		return
+	}
+
+	res := g.dedupe.do(c.FullPath(), directClaimInput.Net, directClaimInput.Address, func() *global.Response {
+		bonus, failure := g.reserveReferral(directClaimInput.Net, directClaimInput.Referral)
+		if failure != nil {
+			return failure
+		}
+
+		txHash, code, err := g.client.SendTra(g.requestContext(c), directClaimInput.Net, directClaimInput.Address, g.client.Config.Axiom.Amount, bonus, "", directClaimInput.Source)
+		if !errors.Is(err, internal.ErrAddressLocked) {
+			internal.DeleteTxData(g.client, strings.ToLower(directClaimInput.Address), global.NativeToken, directClaimInput.Net)
+		}
+		g.settleReferral(directClaimInput.Net, directClaimInput.Referral, directClaimInput.Address, txHash, err)
+		if err != nil {
+			return global.Fail(code, err.Error())
+		}
+		g.mailReceipt(email, directClaimInput.Net, directClaimInput.Address, txHash)
+		return g.claimSuccess(txHash, directClaimInput.Net, directClaimInput.Address)
+	})
+	global.Result(res, c)
+}
+
+// multiClaim 一次领取原生代币与配置的全部测试代币，返回每个代币的结果
+func (g *Server) multiClaim(c *gin.Context) {
+	var directClaimInput global.DirectClaimReq
+	if !bindJSON(c, &directClaimInput) {
+		return
+	}
+
+	net, ok := g.validateClaim(c, directClaimInput.Address, directClaimInput.Net, directClaimInput.Source, nil)
+	if !ok {
+		return
+	}
+	directClaimInput.Net = net
+
+	bonus, failure := g.reserveReferral(directClaimInput.Net, directClaimInput.Referral)
+	if failure != nil {
+		global.Result(failure, c)
+		return
+	}
+	results := g.client.MultiClaim(g.requestContext(c), directClaimInput.Net, directClaimInput.Address, g.client.Config.Axiom.Amount, bonus, directClaimInput.Source)
+	// 推荐码按原生代币的领取结果结算
+	var err error
+	if native := results[0]; native.Code != global.SUCCESS {
+		err = errors.New(native.Msg)
+	}
+	g.settleReferral(directClaimInput.Net, directClaimInput.Referral, directClaimInput.Address, results[0].TxHash, err)
+	global.Result(global.SuccessDetail(results), c)
+}
+
+func (g *Server) tweetClaim(c *gin.Context) {
+	var tweetClaimReq global.TweetClaimReq
+	if !bindJSON(c, &tweetClaimReq) {
+		return
+	}
+
+	net, ok := g.validateClaim(c, tweetClaimReq.Address, tweetClaimReq.Net, tweetClaimReq.Source, &tweetClaimReq.TweetUrl)
+	if !ok {
+		return
+	}
+	tweetClaimReq.Net = net
+	email, ok := checkEmail(c, tweetClaimReq.Email)
+	if !ok {
 		return
 	}
 
-	if judge := IsValidEthereumAddress(directClaimInput.Address); !judge {
-		global.Result(global.Fail(global.ErrAddrCode, global.ErrAddrMsg+fmt.Sprintf(directClaimInput.Address)), c)
+	res := g.dedupe.do(c.FullPath(), tweetClaimReq.Net, tweetClaimReq.Address, func() *global.Response {
+		bonus, failure := g.reserveReferral(tweetClaimReq.Net, tweetClaimReq.Referral)
+		if failure != nil {
+			return failure
+		}
+
+		txHash, code, err := g.client.SendTra(g.requestContext(c), tweetClaimReq.Net, tweetClaimReq.Address, g.client.Config.Axiom.TweetAmount, bonus, tweetClaimReq.TweetUrl, tweetClaimReq.Source)
+		if !errors.Is(err, internal.ErrAddressLocked) {
+			internal.DeleteTxData(g.client, strings.ToLower(tweetClaimReq.Address), global.NativeToken, tweetClaimReq.Net)
+		}
+		g.settleReferral(tweetClaimReq.Net, tweetClaimReq.Referral, tweetClaimReq.Address, txHash, err)
+		if err != nil {
+			return global.Fail(code, err.Error())
+		}
+		g.mailReceipt(email, tweetClaimReq.Net, tweetClaimReq.Address, txHash)
+		return g.claimSuccess(txHash, tweetClaimReq.Net, tweetClaimReq.Address)
+	})
+	global.Result(res, c)
+}
+
+func (g *Server) claimAsync(c *gin.Context) {
+	var directClaimInput global.DirectClaimReq
+	if !bindJSON(c, &directClaimInput) {
 		return
 	}
 
-	if !strings.EqualFold(g.config.Axiom.TestNetName, directClaimInput.Net) {
-		global.Result(global.Fail(global.NotSupportCode, global.NotSupportMsg+fmt.Sprintf(directClaimInput.Net)), c)
+	net, ok := g.validateClaim(c, directClaimInput.Address, directClaimInput.Net, directClaimInput.Source, nil)
+	if !ok {
 		return
 	}
+	directClaimInput.Net = net
 
-	g.client.GinContext = c
-	txHash, code, err := g.client.SendTra(directClaimInput.Net, directClaimInput.Address, g.client.Config.Axiom.Amount, "")
-	if err == nil || err.Error() != global.AddrPreLockErrMsg {
-		internal.DeleteTxData(g.client, strings.ToLower(directClaimInput.Address), global.NativeToken, directClaimInput.Net)
+	bonus, failure := g.reserveReferral(directClaimInput.Net, directClaimInput.Referral)
+	if failure != nil {
+		global.Result(failure, c)
+		return
+	}
+	// 推荐码由 worker 按领取结果结算，入队失败时在这里归还
+	referral := ""
+	if g.config.Referral.Enable {
+		referral = directClaimInput.Referral
 	}
+	ticket, code, err := g.client.EnqueueClaim(g.requestContext(c), directClaimInput.Net, directClaimInput.Address, g.client.Config.Axiom.Amount, bonus, directClaimInput.Source, referral)
 	if err != nil {
+		g.settleReferral(directClaimInput.Net, directClaimInput.Referral, directClaimInput.Address, "", err)
 		global.Result(global.Fail(code, err.Error()), c)
 		return
 	}
 
-	global.Result(global.Success(txHash), c)
+	global.Result(global.SuccessDetail(ticket), c)
 }
 
-func (g *Server) tweetClaim(c *gin.Context) {
-	var tweetClaimReq global.TweetClaimReq
-	if err := c.BindJSON(&tweetClaimReq); err != nil {
-		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+func (g *Server) claimTicket(c *gin.Context) {
+	ticket, ok := g.client.GetTicket(c.Param("id"))
+	if !ok {
+		global.Result(global.Fail(global.TicketNotFoundCode, global.TicketNotFoundMsg+c.Param("id")), c)
 		return
 	}
 
-	if judge := IsValidEthereumAddress(tweetClaimReq.Address); !judge {
-		global.Result(global.Fail(global.ErrAddrCode, global.ErrAddrMsg+fmt.Sprintf(tweetClaimReq.Address)), c)
+	global.Result(global.SuccessDetail(ticket), c)
+}
+
+func (g *Server) receipt(c *gin.Context) {
+	receipt, ok := g.client.GetReceipt(c.Param("id"))
+	if !ok {
+		global.Result(global.Fail(global.ReceiptNotFoundCode, global.ReceiptNotFoundMsg+c.Param("id")), c)
 		return
 	}
 
-	if !strings.EqualFold(g.config.Axiom.TestNetName, tweetClaimReq.Net) {
-		global.Result(global.Fail(global.NotSupportCode, global.NotSupportMsg+fmt.Sprintf(tweetClaimReq.Net)), c)
+	global.Result(global.SuccessDetail(receipt), c)
+}
+
+func (g *Server) authorizedClaim(c *gin.Context) {
+	var authorizedClaimReq global.AuthorizedClaimReq
+	if !bindJSON(c, &authorizedClaimReq) {
 		return
 	}
-	if judge := isValidTwitterURL(tweetClaimReq.TweetUrl); !judge {
-		global.Result(global.Fail(global.TweetUrlErrCode, global.TweetUrlErrMsg), c)
+
+	net, ok := g.validateClaim(c, authorizedClaimReq.Address, authorizedClaimReq.Net, authorizedClaimReq.Source, nil)
+	if !ok {
 		return
 	}
+	authorizedClaimReq.Net = net
 
-	g.client.GinContext = c
-	txHash, code, err := g.client.SendTra(tweetClaimReq.Net, tweetClaimReq.Address, g.client.Config.Axiom.TweetAmount, tweetClaimReq.TweetUrl)
-	if err == nil || err.Error() != global.AddrPreLockErrMsg {
-		internal.DeleteTxData(g.client, strings.ToLower(tweetClaimReq.Address), global.NativeToken, tweetClaimReq.Net)
+	// 授权 token 由可信后端签发，校验通过后跳过推特验证
+	if code, err := g.client.VerifyClaimToken(authorizedClaimReq.Token, authorizedClaimReq.Address); err != nil {
+		global.Result(global.Fail(code, err.Error()), c)
+		return
+	}
+
+	// 授权领取与其他领取方式一样使用推荐码，token 校验通过后才占用使用次数
+	bonus, failure := g.reserveReferral(authorizedClaimReq.Net, authorizedClaimReq.Referral)
+	if failure != nil {
+		global.Result(failure, c)
+		return
+	}
+	txHash, code, err := g.client.SendTra(g.requestContext(c), authorizedClaimReq.Net, authorizedClaimReq.Address, g.client.Config.Axiom.Amount, bonus, "", authorizedClaimReq.Source)
+	if !errors.Is(err, internal.ErrAddressLocked) {
+		internal.DeleteTxData(g.client, strings.ToLower(authorizedClaimReq.Address), global.NativeToken, authorizedClaimReq.Net)
+	}
+	g.settleReferral(authorizedClaimReq.Net, authorizedClaimReq.Referral, authorizedClaimReq.Address, txHash, err)
+	if err != nil {
+		global.Result(global.Fail(code, err.Error()), c)
+		return
+	}
+
+	global.Result(g.claimSuccess(txHash, authorizedClaimReq.Net, authorizedClaimReq.Address), c)
+}
+
+func (g *Server) signatureClaim(c *gin.Context) {
+	var signatureClaimReq global.SignatureClaimReq
+	if !bindJSON(c, &signatureClaimReq) {
+		return
+	}
+
+	net, ok := g.validateClaim(c, signatureClaimReq.Address, signatureClaimReq.Net, signatureClaimReq.Source, nil)
+	if !ok {
+		return
+	}
+	signatureClaimReq.Net = net
+
+	if code, err := g.client.VerifyClaimSignature(signatureClaimReq.Net, signatureClaimReq.Address, signatureClaimReq.ChainId, signatureClaimReq.Timestamp, signatureClaimReq.Signature); err != nil {
+		global.Result(global.Fail(code, err.Error()), c)
+		return
+	}
+
+	// 签名校验通过后才占用推荐码使用次数
+	bonus, failure := g.reserveReferral(signatureClaimReq.Net, signatureClaimReq.Referral)
+	if failure != nil {
+		global.Result(failure, c)
+		return
+	}
+	txHash, code, err := g.client.SendTra(g.requestContext(c), signatureClaimReq.Net, signatureClaimReq.Address, g.client.Config.Axiom.Amount, bonus, "", signatureClaimReq.Source)
+	if !errors.Is(err, internal.ErrAddressLocked) {
+		internal.DeleteTxData(g.client, strings.ToLower(signatureClaimReq.Address), global.NativeToken, signatureClaimReq.Net)
 	}
+	g.settleReferral(signatureClaimReq.Net, signatureClaimReq.Referral, signatureClaimReq.Address, txHash, err)
 	if err != nil {
 		global.Result(global.Fail(code, err.Error()), c)
 		return
 	}
 
-	global.Result(global.Success(txHash), c)
+	global.Result(g.claimSuccess(txHash, signatureClaimReq.Net, signatureClaimReq.Address), c)
 }
 
 func (g *Server) preCheck(c *gin.Context) {
 	var preCheckReq global.PreCheckReq
-	if err := c.BindJSON(&preCheckReq); err != nil {
-		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+	if !bindJSON(c, &preCheckReq) {
 		return
 	}
 
@@ -142,50 +404,321 @@ func (g *Server) preCheck(c *gin.Context) {
 		return
 	}
 
-	if !strings.EqualFold(g.config.Axiom.TestNetName, preCheckReq.Net) {
-		global.Result(global.Fail(global.NotSupportCode, global.NotSupportMsg+fmt.Sprintf(preCheckReq.Net)), c)
+	net, ok := g.canonicalNet(preCheckReq.Net)
+	if !ok {
+		global.Result(g.unsupportedNet(preCheckReq.Net), c)
 		return
 	}
+	preCheckReq.Net = net
 
-	code, err := g.client.PreCheck(preCheckReq.Net, preCheckReq.Address)
+	code, err := g.client.PreCheck(g.requestContext(c), preCheckReq.Net, preCheckReq.Address)
 	if err != nil {
-		global.Result(global.Fail(code, err.Error()), c)
+		res := global.Fail(code, err.Error())
+		// 领取间隔内同样返回资格摘要与 Retry-After，便于页面展示冷却时间、客户端按时重试
+		if code == global.ReqWithinDayCode {
+			g.setRetryAfter(c, preCheckReq.Net, preCheckReq.Address)
+			g.attachEligibility(c, res, preCheckReq.Net, preCheckReq.Address)
+		}
+		global.Result(res, c)
+		return
+	}
+
+	res := global.Success("PreCheck Pass")
+	g.attachEligibility(c, res, preCheckReq.Net, preCheckReq.Address)
+	global.Result(res, c)
+}
+
+// attachEligibility 在预检响应中附带领取资格摘要，查询失败时只记录日志，不影响预检结果
+func (g *Server) attachEligibility(c *gin.Context, res *global.Response, net string, address string) {
+	eligibility, err := g.client.Eligibility(g.requestContext(c), net, address)
+	if err != nil {
+		g.requestLogger(c).Warnf("compute eligibility of %s: %v", address, err)
+		return
+	}
+	res.Detail = eligibility
+}
+
+// setRetryAfter 按地址下次可领取的时间设置 Retry-After 响应头，单位为秒
+func (g *Server) setRetryAfter(c *gin.Context, net string, address string) {
+	next := g.client.NextEligibleAt(net, address)
+	if next.IsZero() {
 		return
 	}
+	c.Header("Retry-After", strconv.FormatInt(internal.CooldownSeconds(next), 10))
+}
 
-	global.Result(global.Success("PreCheck Pass"), c)
+func (g *Server) stats(c *gin.Context) {
+	stats, err := g.client.Stats(g.config.Axiom.TestNetName)
+	if err != nil {
+		g.requestLogger(c).Error(err)
+		global.Result(global.Fail(global.CommonErrCode, global.CommonErrMsg), c)
+		return
+	}
+
+	global.Result(global.SuccessDetail(newFormattedStats(stats, g.amountFormat(c))), c)
 }
 
+// Stop 先停止接收新请求并等待处理中的请求完成，再排空异步领取队列，最后关闭客户端
 func (g *Server) Stop() error {
+	ctx := context.Background()
+	if timeout := g.config.Network.WriteTimeout.ToDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := g.httpServer.Shutdown(ctx); err != nil {
+		g.logger.Warnf("shutdown http server: %v", err)
+	}
+	if g.config.Queue.Enable {
+		g.client.StopQueue(g.config.Queue.DrainTimeout.ToDuration())
+	}
 	g.client.Close()
 	g.cancel()
 	g.logger.Infoln("gin service stop")
 	return nil
 }
 
-// MaxAllowed 限流器
+// CheckMaintenance 限时活动之外以及维护时间段内拒绝领取请求
+func (g *Server) CheckMaintenance() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		// detail 中返回活动起止时间
+		if now := time.Now(); !g.client.EventActive(now) {
+			res := global.Fail(global.EventInactiveCode, global.EventInactiveMsg)
+			res.Detail = g.client.Event(now)
+			global.Result(res, c)
+			c.Abort()
+			return
+		}
+		if paused, until := g.client.InMaintenance(time.Now()); paused {
+			global.Result(global.Fail(global.PausedCode, global.PausedMsg+until.Format(time.RFC3339)), c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// MaxAllowed 限流器，每次调用生成独立的限流器，limitValue 为 0 时不限流
 func (g *Server) MaxAllowed(limitValue int64) func(c *gin.Context) {
+	if limitValue <= 0 || g.config.DevMode {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
 	limiter := utils.NewLimiter(limitValue)
 	g.logger.Infof("limiter.SetMax: %d", limitValue)
 	// 返回限流逻辑
 	return func(c *gin.Context) {
+		// 预检请求不计入限流，避免高负载时跨域预检被限流导致页面无法领取
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
 		if !limiter.Ok() {
-			c.AbortWithStatus(http.StatusServiceUnavailable) // 超过每秒200，就返回503错误码
+			g.limiterMetrics.record(c)
+			c.AbortWithStatus(http.StatusServiceUnavailable) // 超过每秒限制，就返回503错误码
 			return
 		}
 		c.Next()
 	}
 }
 
+// skipRoot 根路径用于确认服务存活，不受全局限流影响，其余中间件照常执行
+func skipRoot(limiter gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == "/" {
+			c.Next()
+			return
+		}
+		limiter(c)
+	}
+}
+
+// MaxAllowedPerNet 按请求中的网络分别限流，一个网络繁忙时不影响其他网络。
+// 不支持的网络共用一个限流器，避免任意网络名导致限流器无限增长
+func (g *Server) MaxAllowedPerNet(limitValue int64) func(c *gin.Context) {
+	if limitValue <= 0 || g.config.DevMode {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	var lock sync.Mutex
+	limiters := make(map[string]*utils.Limiter)
+	g.logger.Infof("per net limiter.SetMax: %d", limitValue)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		net, _ := g.canonicalNet(requestNet(c))
+		lock.Lock()
+		limiter, ok := limiters[net]
+		if !ok {
+			limiter = utils.NewLimiter(limitValue)
+			limiters[net] = limiter
+		}
+		lock.Unlock()
+		if !limiter.Ok() {
+			g.limiterMetrics.record(c)
+			c.AbortWithStatus(http.StatusServiceUnavailable)
+			return
+		}
+		c.Next()
+	}
+}
+
+// 按 IP 记录的最近请求时间超过该数量时清理已过期的记录
+const minIntervalPruneSize = 10000
+
+// MinIntervalPerIP 同一 IP 两次请求的最小间隔，间隔内的请求返回 429，interval 为 0 时不限制。
+// 与按接口计数的限流器相互独立
+func (g *Server) MinIntervalPerIP(interval time.Duration) func(c *gin.Context) {
+	if interval <= 0 || g.config.DevMode {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	var lock sync.Mutex
+	lastSeen := make(map[string]time.Time)
+	g.logger.Infof("per ip min interval: %s", interval)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		ip := utils.IPBucket(c.ClientIP(), g.config.Network.IPv4Prefix, g.config.Network.IPv6Prefix)
+		now := time.Now()
+		lock.Lock()
+		last, ok := lastSeen[ip]
+		throttled := ok && now.Sub(last) < interval
+		if !throttled {
+			lastSeen[ip] = now
+			if len(lastSeen) > minIntervalPruneSize {
+				for key, seen := range lastSeen {
+					if now.Sub(seen) >= interval {
+						delete(lastSeen, key)
+					}
+				}
+			}
+		}
+		lock.Unlock()
+		if throttled {
+			g.limiterMetrics.record(c)
+			c.AbortWithStatus(http.StatusTooManyRequests)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestNet 读取请求中的网络名，GET 请求取 query 参数，其他请求取 JSON 请求体中的 net 字段，读取后恢复请求体
+func requestNet(c *gin.Context) string {
+	if c.Request.Method == http.MethodGet {
+		return c.Query("net")
+	}
+	if c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return ""
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	var req struct {
+		Net string `json:"net"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return req.Net
+}
+
+// bindJSON 解析请求体，空请求体单独返回缺少请求体的错误
+func bindJSON(c *gin.Context, obj any) bool {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		global.Result(global.Fail(global.EmptyBodyErrCode, global.EmptyBodyErrMsg), c)
+		return false
+	}
+	if err := c.BindJSON(obj); err != nil {
+		// chunked 请求无法提前得知长度，解析时遇到 EOF 说明请求体为空
+		if errors.Is(err, io.EOF) {
+			global.Result(global.Fail(global.EmptyBodyErrCode, global.EmptyBodyErrMsg), c)
+			return false
+		}
+		global.Result(global.Fail(global.ParseErrCode, global.ParseErrMsg), c)
+		return false
+	}
+	return true
+}
+
+// 以太坊地址为 0x 加 40 位十六进制字符
+const ethereumAddressLength = 42
+
+var ethereumAddressRegex = regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
+
+// IsValidEthereumAddress 长度不符的输入不进入正则匹配
 func IsValidEthereumAddress(address string) bool {
-	// 正则表达式模式匹配以太坊地址
-	pattern := "^0x[0-9a-fA-F]{40}$"
-	regex := regexp.MustCompile(pattern)
-	return regex.MatchString(address)
+	if len(address) != ethereumAddressLength {
+		return false
+	}
+	return ethereumAddressRegex.MatchString(address)
 }
 
-func isValidTwitterURL(url string) bool {
-	twitterURLPattern := `^(https?://(twitter\.com|x\.com)/[a-zA-Z0-9_]+/status/\d+).*`
-	re := regexp.MustCompile(twitterURLPattern)
-	return re.MatchString(url)
+// UnsupportedNet 不支持的网络的错误详情，列出可用的网络名
+type UnsupportedNet struct {
+	SupportedNets []string `json:"supportedNets"`
+}
+
+// unsupportedNet 返回不支持网络的错误，detail 中附带可用的网络名
+func (g *Server) unsupportedNet(net string) *global.Response {
+	res := global.Fail(global.NotSupportCode, global.NotSupportMsg+net)
+	res.Detail = g.supportedNets
+	return res
+}
+
+// canonicalNet 将请求中的网络名统一为配置中的写法，保证校验、限制 key 以及发送使用同一个网络名
+func (g *Server) canonicalNet(net string) (string, bool) {
+	if strings.EqualFold(g.config.Axiom.TestNetName, net) {
+		return g.config.Axiom.TestNetName, true
+	}
+	return "", false
+}
+
+// isValidSource 校验领取来源标记，未填写时不校验
+func (g *Server) isValidSource(source string) bool {
+	if source == "" {
+		return true
+	}
+	for _, allowed := range g.config.Campaign.Sources {
+		if allowed == source {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidTwitterURL 超过 max_url_length 的链接直接拒绝，为 0 时不限制。Go 的正则基于 RE2，匹配耗时与输入长度线性相关，
+// 长度上限进一步限制了单次请求的匹配开销
+func (g *Server) isValidTwitterURL(url string) bool {
+	if limit := g.config.Scrapper.MaxURLLength; limit > 0 && len(url) > limit {
+		return false
+	}
+	return g.tweetURLRegex.MatchString(url)
+}
+
+// compileTweetURLRegex 根据配置的推特域名生成推文链接校验规则
+func compileTweetURLRegex(domains []string) (*regexp.Regexp, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("scrapper.tweet_domains must not be empty")
+	}
+	domainRegex := regexp.MustCompile(`^[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+$`)
+	quoted := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if !domainRegex.MatchString(domain) {
+			return nil, fmt.Errorf("invalid tweet domain: %q", domain)
+		}
+		quoted = append(quoted, regexp.QuoteMeta(domain))
+	}
+	return regexp.Compile(fmt.Sprintf(`^(https?://(%s)/[a-zA-Z0-9_]+/status/\d+).*`, strings.Join(quoted, "|")))
 }
@@ -0,0 +1,442 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestBindJSONRejectsEmptyBody(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", nil, nil))
+	if res.Code != global.EmptyBodyErrCode {
+		t.Fatalf("empty body should be rejected with %d, got %d %s", global.EmptyBodyErrCode, res.Code, res.Msg)
+	}
+}
+
+// chunked 请求体的长度未知，读到 EOF 时同样按空请求体处理
+func TestBindJSONRejectsEmptyChunkedBody(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/faucet/directClaim", strings.NewReader(""))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	g.router.ServeHTTP(w, req)
+	if res := decodeResponse(t, w); res.Code != global.EmptyBodyErrCode {
+		t.Fatalf("empty chunked body should be rejected with %d, got %d %s", global.EmptyBodyErrCode, res.Code, res.Msg)
+	}
+}
+
+func TestBindJSONRejectsMalformedBody(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", `{"address":`, nil))
+	if res.Code != global.ParseErrCode {
+		t.Fatalf("malformed body should be rejected with %d, got %d %s", global.ParseErrCode, res.Code, res.Msg)
+	}
+}
+
+func TestDirectClaimSendsTx(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, nil)
+
+	body := global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", body, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	if sent := node.Sent(); len(sent) != 1 || sent[0].Hash().Hex() != res.Data {
+		t.Fatalf("expect the returned tx %s to be sent once, got %d txs", res.Data, len(sent))
+	}
+}
+
+func TestCompileTweetURLRegexAllowsConfiguredDomains(t *testing.T) {
+	regex, err := compileTweetURLRegex([]string{"twitter.com", " X.com "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for url, valid := range map[string]bool{
+		"https://twitter.com/axiomesh/status/1700000000000000000":     true,
+		"https://x.com/axiomesh/status/1700000000000000000?s=20":      true,
+		"http://x.com/axiomesh/status/1700000000000000000":            true,
+		"https://mobile.twitter.com/axiomesh/status/1700000000000000": false,
+		"https://xxcom/axiomesh/status/1700000000000000000":           false,
+		"https://evil.com/x.com/axiomesh/status/1700000000000000000":  false,
+		"https://x.com/axiomesh/likes":                                false,
+	} {
+		if got := regex.MatchString(url); got != valid {
+			t.Errorf("%s: expect valid=%v, got %v", url, valid, got)
+		}
+	}
+}
+
+func TestIsValidEthereumAddress(t *testing.T) {
+	for address, valid := range map[string]bool{
+		testRecipient: true,
+		"0x" + strings.ToUpper(testRecipient[2:]): true,
+		testRecipient[2:]:                         false,
+		testRecipient + "1":                       false,
+		"0x" + strings.Repeat("g", 40):            false,
+		"0x" + strings.Repeat("1", 40) + "\n":     false,
+		"0x" + strings.Repeat("1", 1<<16):         false,
+	} {
+		if got := IsValidEthereumAddress(address); got != valid {
+			t.Errorf("%.50s: expect valid=%v, got %v", address, valid, got)
+		}
+	}
+}
+
+// 超过长度上限的推文链接直接拒绝
+func TestIsValidTwitterURLLimitsLength(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Scrapper.MaxURLLength = 64
+	})
+	valid := "https://x.com/axiomesh/status/1700000000000000000"
+	if !g.isValidTwitterURL(valid) {
+		t.Fatalf("%s should be valid", valid)
+	}
+	if long := valid + "?s=" + strings.Repeat("a", 64); g.isValidTwitterURL(long) {
+		t.Fatal("url over the length limit should be rejected")
+	}
+
+	g.config.Scrapper.MaxURLLength = 0
+	if long := valid + "?s=" + strings.Repeat("a", 64); !g.isValidTwitterURL(long) {
+		t.Fatal("url length should not be limited when max_url_length is 0")
+	}
+}
+
+func TestCompileTweetURLRegexRejectsInvalidDomains(t *testing.T) {
+	for _, domains := range [][]string{nil, {"x.com", "not a domain"}, {"localhost"}} {
+		if _, err := compileTweetURLRegex(domains); err == nil {
+			t.Errorf("domains %q should be rejected", domains)
+		}
+	}
+}
+
+// waitNextSecond 限流器按秒计数，等到下一秒开始再发请求，避免跨秒导致计数被重置
+func waitNextSecond() {
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+}
+
+func TestRateLimitsArePerEndpoint(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.RateLimit.DirectClaim = 3
+		cfg.Network.RateLimit.TweetClaim = 100
+	})
+
+	waitNextSecond()
+	var limited int
+	for i := 0; i < 5; i++ {
+		if w := serve(g, http.MethodPost, "/faucet/directClaim", nil, nil); w.Code == http.StatusServiceUnavailable {
+			limited++
+		}
+		if w := serve(g, http.MethodPost, "/faucet/tweetClaim", nil, nil); w.Code != http.StatusOK {
+			t.Fatalf("tweetClaim has its own limit and should not be limited, got %d", w.Code)
+		}
+	}
+	if limited != 3 {
+		t.Fatalf("expect 3 of 5 directClaim requests limited, got %d", limited)
+	}
+}
+
+func TestDirectClaimTagsSource(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Campaign.Sources = []string{"hackathon"}
+	})
+	net := g.config.Axiom.TestNetName
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: net, Source: "unknown"}, nil))
+	if res.Code != global.SourceErrCode {
+		t.Fatalf("unknown source should be rejected, got %d %s", res.Code, res.Msg)
+	}
+
+	res = decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: net, Source: "hackathon"}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	if data := g.client.LastClaim(net, testRecipient); data == nil || data.Source != "hackathon" {
+		t.Fatalf("claim record should be tagged with the source, got %+v", data)
+	}
+	stats, err := g.client.Stats(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total.Sources["hackathon"] != 1 {
+		t.Fatalf("stats should count the source, got %v", stats.Total.Sources)
+	}
+}
+
+// 网络名大小写不同的请求按配置中的网络名记录，共用同一份领取限制
+func TestDirectClaimNormalizesNet(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	net := g.config.Axiom.TestNetName
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: strings.ToLower(net)}, nil))
+	if res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+	if data := g.client.LastClaim(net, testRecipient); data == nil {
+		t.Fatal("claim should be recorded under the configured net name")
+	}
+	res = decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: strings.ToUpper(net)}, nil))
+	if res.Code != global.ReqWithinDayCode {
+		t.Fatalf("claim with a differently cased net should share the limit, got %d %s", res.Code, res.Msg)
+	}
+	res = decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: "mainnet"}, nil))
+	if res.Code != global.NotSupportCode {
+		t.Fatalf("unknown net should be rejected with %d, got %d", global.NotSupportCode, res.Code)
+	}
+}
+
+// 大量跨域预检请求不消耗全局限流额度，不影响正常领取
+func TestPreflightFloodDoesNotStarveClaims(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.RateLimit.Global = 3
+	})
+	preflight := http.Header{
+		"Origin":                        []string{"https://faucet.example"},
+		"Access-Control-Request-Method": []string{http.MethodPost},
+	}
+
+	waitNextSecond()
+	for i := 0; i < 50; i++ {
+		if w := serve(g, http.MethodOptions, "/faucet/directClaim", nil, preflight); w.Code != http.StatusNoContent {
+			t.Fatalf("preflight %d should be answered by cors, got %d", i, w.Code)
+		}
+	}
+	w := serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("claim after a preflight flood should not be limited, got %d", w.Code)
+	}
+	if res := decodeResponse(t, w); res.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", res.Code, res.Msg)
+	}
+}
+
+func TestServerUsesConfiguredTimeouts(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.ReadTimeout = repo.Duration(3 * time.Second)
+		cfg.Network.ReadHeaderTimeout = repo.Duration(time.Second)
+		cfg.Network.WriteTimeout = repo.Duration(7 * time.Second)
+		cfg.Network.IdleTimeout = repo.Duration(11 * time.Second)
+	})
+
+	server := g.httpServer
+	if server.ReadTimeout != 3*time.Second || server.ReadHeaderTimeout != time.Second ||
+		server.WriteTimeout != 7*time.Second || server.IdleTimeout != 11*time.Second {
+		t.Fatalf("http server should use the configured timeouts, got read %s header %s write %s idle %s",
+			server.ReadTimeout, server.ReadHeaderTimeout, server.WriteTimeout, server.IdleTimeout)
+	}
+	if server.Handler != g.router {
+		t.Fatal("http server should serve the gin router")
+	}
+}
+
+func TestDefaultHTTPTimeoutsAreSet(t *testing.T) {
+	network := repo.DefaultConfig().Network
+	for name, timeout := range map[string]repo.Duration{
+		"read":        network.ReadTimeout,
+		"read header": network.ReadHeaderTimeout,
+		"write":       network.WriteTimeout,
+		"idle":        network.IdleTimeout,
+	} {
+		if timeout <= 0 {
+			t.Fatalf("default %s timeout should be set", name)
+		}
+	}
+}
+
+// 地址已被锁定时，失败的请求不能释放其他请求持有的锁
+func TestDirectClaimKeepsLockHeldByAnotherClaim(t *testing.T) {
+	node := testutil.NewNode(t)
+	g := newTestServer(t, node, nil)
+	net := g.config.Axiom.TestNetName
+	if code, err := g.client.ReserveClaim(context.Background(), net, testRecipient); err != nil {
+		t.Fatalf("reserve failed: %d %v", code, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: net}, nil))
+		if res.Code != global.AddrPreLockErrCode {
+			t.Fatalf("attempt %d: expect %d, got %d %s", i, global.AddrPreLockErrCode, res.Code, res.Msg)
+		}
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent while locked, got %d", len(sent))
+	}
+}
+
+// 各网络的领取接口独立限流，不支持的网络不占用已配置网络的额度；网络名大小写不同时共用额度
+func TestRateLimitsArePerNet(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.RateLimit.DirectClaim = 3
+	})
+	net := g.config.Axiom.TestNetName
+	claimStatus := func(net string, address string) int {
+		return serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: address, Net: net}, nil).Code
+	}
+
+	waitNextSecond()
+	for i := 0; i < 5; i++ {
+		claimStatus("mainnet", testRecipient)
+	}
+	// 限流器读取网络名后需保留请求体，领取仍能正常处理
+	w := serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: strings.ToLower(net)}, nil)
+	if res := decodeResponse(t, w); w.Code != http.StatusOK || res.Code != global.SUCCESS {
+		t.Fatalf("claim on the configured net should not be limited by other nets, got %d %d %s", w.Code, res.Code, res.Msg)
+	}
+	if code := claimStatus(strings.ToUpper(net), testAddress(1)); code != http.StatusOK {
+		t.Fatalf("second claim within the limit should pass, got %d", code)
+	}
+	if code := claimStatus(net, testAddress(2)); code != http.StatusServiceUnavailable {
+		t.Fatalf("differently cased nets should share the limit, got %d", code)
+	}
+}
+
+func TestPreCheckMinIntervalPerIP(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.RateLimit.PreCheckInterval = repo.Duration(time.Hour)
+	})
+	preCheck := func(remoteAddr string) int {
+		body, _ := json.Marshal(global.PreCheckReq{Address: testRecipient, Net: g.config.Axiom.TestNetName})
+		req := httptest.NewRequest(http.MethodPost, "/faucet/preCheck", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		g.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := preCheck("203.0.113.7:1234"); code != http.StatusOK {
+		t.Fatalf("first preCheck should pass, got %d", code)
+	}
+	if code := preCheck("203.0.113.7:5678"); code != http.StatusTooManyRequests {
+		t.Fatalf("preCheck within the interval should get 429, got %d", code)
+	}
+	if code := preCheck("203.0.113.8:1234"); code != http.StatusOK {
+		t.Fatalf("other ip should not be limited, got %d", code)
+	}
+}
+
+func TestDevModeSkipsRateLimits(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.DevMode = true
+		cfg.Network.RateLimit.PreCheckInterval = repo.Duration(time.Hour)
+	})
+	for i := 0; i < 2; i++ {
+		w := serve(g, http.MethodPost, "/faucet/preCheck", global.PreCheckReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("preCheck %d should not be limited in dev mode, got %d", i, w.Code)
+		}
+	}
+}
+
+func TestPreCheckReturnsEligibility(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	req := global.PreCheckReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/preCheck", req, nil))
+	eligibility := &internal.Eligibility{}
+	decodeDetail(t, res, eligibility)
+	if res.Code != global.SUCCESS || eligibility.RemainingToday != 1 || eligibility.Amount != g.config.Axiom.Amount {
+		t.Fatalf("expect eligibility of a new address, got %d %+v", res.Code, eligibility)
+	}
+
+	// 领取间隔内同样返回冷却时间
+	claimRes := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if claimRes.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", claimRes.Code, claimRes.Msg)
+	}
+	res = decodeResponse(t, serve(g, http.MethodPost, "/faucet/preCheck", req, nil))
+	eligibility = &internal.Eligibility{}
+	decodeDetail(t, res, eligibility)
+	if res.Code != global.ReqWithinDayCode || eligibility.RemainingToday != 0 || eligibility.Cooldown <= 0 {
+		t.Fatalf("expect cooldown within the claim interval, got %d %+v", res.Code, eligibility)
+	}
+}
+
+// 领取间隔内的预检返回 Retry-After，按秒向上取整
+func TestPreCheckSetsRetryAfter(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	req := global.PreCheckReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}
+	if w := serve(g, http.MethodPost, "/faucet/preCheck", req, nil); w.Header().Get("Retry-After") != "" {
+		t.Fatalf("eligible address should not get Retry-After, got %q", w.Header().Get("Retry-After"))
+	}
+
+	claimRes := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil))
+	if claimRes.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", claimRes.Code, claimRes.Msg)
+	}
+	w := serve(g, http.MethodPost, "/faucet/preCheck", req, nil)
+	retryAfter, err := strconv.ParseInt(w.Header().Get("Retry-After"), 10, 64)
+	if err != nil {
+		t.Fatalf("expect Retry-After in seconds, got %q", w.Header().Get("Retry-After"))
+	}
+	if retryAfter <= 24*3600-5 || retryAfter > 24*3600 {
+		t.Fatalf("expect Retry-After about 24 hours, got %d", retryAfter)
+	}
+	eligibility := &internal.Eligibility{}
+	decodeDetail(t, decodeResponse(t, w), eligibility)
+	if eligibility.NextEligibleAt == 0 || eligibility.NextEligibleAt-time.Now().Unix() > retryAfter {
+		t.Fatalf("expect next eligible time within Retry-After, got %+v", eligibility)
+	}
+}
+
+func TestPreCheckRejectsZeroAndFundingAddress(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	for _, tc := range []struct {
+		address string
+		code    int
+		reason  string
+	}{
+		{"0x0000000000000000000000000000000000000000", global.ZeroAddressCode, "zero_address"},
+		{g.client.FundingAddress(), global.SelfAddressCode, "funding_address"},
+	} {
+		res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/preCheck", global.PreCheckReq{Address: tc.address, Net: g.config.Axiom.TestNetName}, nil))
+		if res.Code != tc.code || res.Reason != tc.reason {
+			t.Fatalf("preCheck of %s: expect %d %q, got %d %q", tc.address, tc.code, tc.reason, res.Code, res.Reason)
+		}
+	}
+}
+
+// 预检的每种失败返回各自的错误码与原因
+func TestPreCheckReportsReason(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+	net := g.config.Axiom.TestNetName
+	blocked := "0x2222222222222222222222222222222222222222"
+	if _, err := g.client.SetAddressNote(net, blocked, "", internal.FlagBlocked); err != nil {
+		t.Fatal(err)
+	}
+	claimRes := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: net}, nil))
+	if claimRes.Code != global.SUCCESS {
+		t.Fatalf("claim failed: %d %s", claimRes.Code, claimRes.Msg)
+	}
+
+	for _, tc := range []struct {
+		address string
+		code    int
+		reason  string
+	}{
+		{"0x1234", global.ErrAddrCode, "invalid_address"},
+		{blocked, global.AddrBlockedCode, "blocklisted"},
+		{testRecipient, global.ReqWithinDayCode, "already_claimed"},
+	} {
+		res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/preCheck", global.PreCheckReq{Address: tc.address, Net: net}, nil))
+		if res.Code != tc.code || res.Reason != tc.reason {
+			t.Errorf("%s: expect %d %s, got %d %s", tc.address, tc.code, tc.reason, res.Code, res.Reason)
+		}
+	}
+}
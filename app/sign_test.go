@@ -0,0 +1,96 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const (
+	testApiKey    = "partner"
+	testApiSecret = "partner-secret"
+)
+
+func newSignServer(t *testing.T, required bool) *Server {
+	return newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.RequestSign.Enable = true
+		cfg.RequestSign.Required = required
+		cfg.RequestSign.Window = repo.Duration(time.Minute)
+		cfg.RequestSign.ApiKeys = []repo.ApiKey{{ID: testApiKey, Secret: testApiSecret}}
+	})
+}
+
+func signHeader(timestamp int64, method string, path string, body string) http.Header {
+	mac := hmac.New(sha256.New, []byte(testApiSecret))
+	mac.Write([]byte(signaturePrefix(timestamp, method, path) + body))
+	return http.Header{
+		HeaderApiKey:    {testApiKey},
+		HeaderTimestamp: {strconv.FormatInt(timestamp, 10)},
+		HeaderSignature: {hex.EncodeToString(mac.Sum(nil))},
+	}
+}
+
+// 地址无效的请求通过签名校验后由接口返回地址错误，以此区分签名是否通过
+const signedBody = `{"address":"0x1","net":"Taurus"}`
+
+func TestVerifySignatureAcceptsOnceAndRejectsReplay(t *testing.T) {
+	g := newSignServer(t, true)
+	header := signHeader(time.Now().Unix(), http.MethodPost, "/faucet/directClaim", signedBody)
+
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", signedBody, header)); res.Code != global.ErrAddrCode {
+		t.Fatalf("signed request should pass, got %d %s", res.Code, res.Msg)
+	}
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", signedBody, header)); res.Code != global.SignErrCode {
+		t.Fatalf("replayed request should be rejected, got %d %s", res.Code, res.Msg)
+	}
+}
+
+// 签名包含请求方法与路径，不能拿到其他接口使用
+func TestVerifySignatureBindsPath(t *testing.T) {
+	g := newSignServer(t, true)
+	header := signHeader(time.Now().Unix(), http.MethodPost, "/faucet/directClaim", signedBody)
+
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/tweetClaim", signedBody, header)); res.Code != global.SignErrCode {
+		t.Fatalf("signature for another path should be rejected, got %d %s", res.Code, res.Msg)
+	}
+}
+
+func TestVerifySignatureRejections(t *testing.T) {
+	g := newSignServer(t, true)
+	now := time.Now().Unix()
+	tampered := signHeader(now, http.MethodPost, "/faucet/directClaim", signedBody)
+	tampered.Set(HeaderSignature, hex.EncodeToString(make([]byte, sha256.Size)))
+	unknown := signHeader(now, http.MethodPost, "/faucet/directClaim", signedBody)
+	unknown.Set(HeaderApiKey, "unknown")
+
+	for name, tc := range map[string]struct {
+		header http.Header
+		code   int
+	}{
+		"missing api key": {nil, global.SignErrCode},
+		"unknown api key": {unknown, global.SignErrCode},
+		"bad signature":   {tampered, global.SignErrCode},
+		"expired":         {signHeader(now-120, http.MethodPost, "/faucet/directClaim", signedBody), global.SignExpiredCode},
+		"future":          {signHeader(now+120, http.MethodPost, "/faucet/directClaim", signedBody), global.SignExpiredCode},
+	} {
+		if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", signedBody, tc.header)); res.Code != tc.code {
+			t.Errorf("%s: expect %d, got %d %s", name, tc.code, res.Code, res.Msg)
+		}
+	}
+}
+
+func TestVerifySignatureOptional(t *testing.T) {
+	g := newSignServer(t, false)
+
+	if res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", signedBody, nil)); res.Code != global.ErrAddrCode {
+		t.Fatalf("unsigned request should pass when signing is optional, got %d %s", res.Code, res.Msg)
+	}
+}
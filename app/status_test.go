@@ -0,0 +1,26 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestDirectClaimOutOfFundsReturns503(t *testing.T) {
+	node := testutil.NewNode(t)
+	node.Handle("eth_sendRawTransaction", func([]json.RawMessage) (any, error) {
+		return nil, &testutil.RPCError{Code: -32000, Message: "insufficient funds for gas * price + value"}
+	})
+	g := newTestServer(t, node, nil)
+
+	w := serve(g, http.MethodPost, "/faucet/directClaim", global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName}, nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect http status 503, got %d", w.Code)
+	}
+	if res := decodeResponse(t, w); res.Code != global.OutOfFundsCode {
+		t.Fatalf("expect %d, got %d", global.OutOfFundsCode, res.Code)
+	}
+}
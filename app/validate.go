@@ -0,0 +1,36 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// validateClaim 校验领取请求的地址、网络、来源以及推文链接（tweetUrl 为 nil 时不校验），返回规范化后的网络名。
+// 默认只返回第一个失败原因；开启 network.report_all_errors 时在 errors 中返回全部失败原因
+func (g *Server) validateClaim(c *gin.Context, address string, net string, source string, tweetUrl *string) (string, bool) {
+	var failures []*global.Response
+	if !IsValidEthereumAddress(address) {
+		failures = append(failures, global.Fail(global.ErrAddrCode, global.ErrAddrMsg+address))
+	}
+	canonical, ok := g.canonicalNet(net)
+	if !ok {
+		failures = append(failures, g.unsupportedNet(net))
+	}
+	if !g.isValidSource(source) {
+		failures = append(failures, global.Fail(global.SourceErrCode, global.SourceErrMsg+source))
+	}
+	if tweetUrl != nil && !g.isValidTwitterURL(*tweetUrl) {
+		failures = append(failures, global.Fail(global.TweetUrlErrCode, global.TweetUrlErrMsg))
+	}
+
+	if len(failures) == 0 {
+		return canonical, true
+	}
+	if g.config.Network.ReportAllErrors {
+		global.Result(global.FailAll(failures), c)
+	} else {
+		global.Result(failures[0], c)
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+var invalidTweetClaim = global.TweetClaimReq{Address: "0x123", Net: "unknown", Source: "unknown", TweetUrl: "https://example.com/post/1"}
+
+func TestValidateClaimReportsFirstError(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/tweetClaim", invalidTweetClaim, nil))
+	if res.Code != global.ErrAddrCode || len(res.Errors) != 0 {
+		t.Fatalf("expect only %d, got %d %+v", global.ErrAddrCode, res.Code, res.Errors)
+	}
+}
+
+func TestValidateClaimReportsAllErrors(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.ReportAllErrors = true
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/tweetClaim", invalidTweetClaim, nil))
+	want := []int{global.ErrAddrCode, global.NotSupportCode, global.SourceErrCode, global.TweetUrlErrCode}
+	if res.Code != want[0] || len(res.Errors) != len(want) {
+		t.Fatalf("expect %v, got %d %+v", want, res.Code, res.Errors)
+	}
+	for i, code := range want {
+		if res.Errors[i].Code != code || res.Errors[i].Msg == "" {
+			t.Fatalf("error %d: expect %d, got %+v", i, code, res.Errors[i])
+		}
+	}
+}
+
+func TestValidateClaimLocalizesAllErrors(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.ReportAllErrors = true
+	})
+
+	req := global.DirectClaimReq{Address: testRecipient, Net: g.config.Axiom.TestNetName, Source: "unknown"}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", req, http.Header{"Accept-Language": {"zh-CN"}}))
+	want := global.Localize(global.SourceErrCode, global.SourceErrMsg+"unknown", global.ParseLanguage("zh-CN"))
+	if want == global.SourceErrMsg+"unknown" {
+		t.Fatal("source error should have a chinese message")
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Msg != want {
+		t.Fatalf("errors should be localized, got %+v", res.Errors)
+	}
+}
+
+// supportedNetsOf 从错误详情中取出可用的网络名
+func supportedNetsOf(t *testing.T, detail any) []any {
+	t.Helper()
+	m, ok := detail.(map[string]any)
+	if !ok {
+		t.Fatalf("detail should list supported nets, got %v", detail)
+	}
+	nets, _ := m["supportedNets"].([]any)
+	return nets
+}
+
+func TestUnsupportedNetListsSupportedNets(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), nil)
+
+	req := global.DirectClaimReq{Address: testRecipient, Net: "unknown"}
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/directClaim", req, nil))
+	if res.Code != global.NotSupportCode {
+		t.Fatalf("expect %d, got %d %s", global.NotSupportCode, res.Code, res.Msg)
+	}
+	if nets := supportedNetsOf(t, res.Detail); len(nets) != 1 || nets[0] != g.config.Axiom.TestNetName {
+		t.Fatalf("expect supported nets [%s], got %v", g.config.Axiom.TestNetName, nets)
+	}
+}
+
+// 返回全部错误时每个错误带上自己的详情
+func TestUnsupportedNetDetailInAllErrors(t *testing.T) {
+	g := newTestServer(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.ReportAllErrors = true
+	})
+
+	res := decodeResponse(t, serve(g, http.MethodPost, "/faucet/tweetClaim", invalidTweetClaim, nil))
+	if res.Detail != nil {
+		t.Fatalf("top level detail follows the first error, got %v", res.Detail)
+	}
+	for _, reason := range res.Errors {
+		if reason.Code == global.NotSupportCode {
+			if nets := supportedNetsOf(t, reason.Detail); len(nets) != 1 {
+				t.Fatalf("expect supported nets in the error, got %v", nets)
+			}
+		} else if reason.Detail != nil {
+			t.Fatalf("error %d should not carry detail, got %v", reason.Code, reason.Detail)
+		}
+	}
+}
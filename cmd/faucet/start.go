@@ -52,22 +52,37 @@ func start(ctx *cli.Context) error {
 	defer cancel()
 
 	log := loggers.Logger(loggers.Global)
+	if repo.Config.DevMode {
+		log.Warnln("==================================================================")
+		log.Warnln("DEV MODE ENABLED: tweet verification, claim limits and rate limits")
+		log.Warnln("are relaxed. NEVER enable dev_mode in production!")
+		log.Warnln("==================================================================")
+	}
 
-	var server *app.Server
-	var wg sync.WaitGroup
-	wg.Add(1)
-	handleShutdown(server, &wg)
 	var client internal.Client
 	err = client.Initialize(repo.Config, p)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
-	server, _ = app.NewServer(&client, repo.Config)
+	if repo.Config.Axiom.SelfTest {
+		if err := client.SelfTest(); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	server, err := app.NewServer(&client, repo.Config)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
 	if err := server.Start(); err != nil {
 		log.Error(err)
 		return err
 	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	handleShutdown(server, &wg)
 
 	printLogo(log)
 	wg.Wait()
@@ -75,7 +90,7 @@ func start(ctx *cli.Context) error {
 }
 
 func handleShutdown(server *app.Server, wg *sync.WaitGroup) {
-	var stop = make(chan os.Signal)
+	var stop = make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM)
 	signal.Notify(stop, syscall.SIGINT)
 
@@ -96,6 +111,7 @@ func printLogo(log logrus.FieldLogger) {
 	log.WithField("__format_only_write_msg_without_formatter", nil).Infof(`
 =========================================================================================
 %s
+version: %s, commit: %s, branch: %s, build date: %s
 =========================================================================================
-`, fig.String())
+`, fig.String(), repo.BuildVersion, repo.BuildCommit, repo.BuildBranch, repo.BuildDate)
 }
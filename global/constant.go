@@ -3,3 +3,10 @@ package global
 const (
 	NativeToken = "native"
 )
+
+const (
+	// RequestIDHeader 请求关联 ID 的请求头与响应头
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey 请求关联 ID 保存在 gin.Context 中的键
+	RequestIDKey = "requestID"
+)
@@ -12,6 +12,18 @@ const (
 	CommonErrCode int    = 100001
 	CommonErrMsg  string = "Axiomledger Network Error，Please Try Again Later！"
 
+	EmptyBodyErrCode int    = 100002
+	EmptyBodyErrMsg  string = "Missing request body"
+
+	SignErrCode int    = 100003
+	SignErrMsg  string = "Invalid request signature"
+
+	SignExpiredCode int    = 100004
+	SignExpiredMsg  string = "Request timestamp is outside the allowed window"
+
+	UnauthorizedCode int    = 100005
+	UnauthorizedMsg  string = "Unauthorized"
+
 	// Business Error
 	ErrAddrCode int    = 110000
 	ErrAddrMsg  string = "Invalid address: "
@@ -43,6 +55,114 @@ const (
 	AddrPreLockErrCode int    = 110009
 	AddrPreLockErrMsg  string = "The account is still being processed"
 
+	AccountActivityErrCode int    = 110010
+	AccountActivityErrMsg  string = "The address does not meet the on-chain activity requirement: "
+
+	AuthTokenErrCode int    = 110011
+	AuthTokenErrMsg  string = "Invalid authorization token"
+
+	AuthTokenExpiredCode int    = 110012
+	AuthTokenExpiredMsg  string = "Authorization token has expired"
+
+	AuthTokenAddrErrCode int    = 110013
+	AuthTokenAddrErrMsg  string = "Authorization token does not match the currently filled in address"
+
+	SourceErrCode int    = 110014
+	SourceErrMsg  string = "Invalid claim source: "
+
+	QueueFullCode int    = 110015
+	QueueFullMsg  string = "Too many claims in the queue, please try again later"
+
+	TicketNotFoundCode int    = 110016
+	TicketNotFoundMsg  string = "Claim ticket not found: "
+
+	PausedCode int    = 110017
+	PausedMsg  string = "The faucet is paused for maintenance, please try again after "
+
+	SignatureErrCode int    = 110018
+	SignatureErrMsg  string = "Invalid claim signature"
+
+	ChainMismatchCode int    = 110019
+	ChainMismatchMsg  string = "The signature was produced for a different chain, expected chain id: "
+
+	ReserveErrCode int    = 110020
+	ReserveErrMsg  string = "The faucet balance is running low, please try again later"
+
+	AddrBlockedCode int    = 110021
+	AddrBlockedMsg  string = "The address has been blocked from claiming"
+
+	IPAddressLimitCode int    = 110022
+	IPAddressLimitMsg  string = "Too many addresses have claimed from your IP"
+
+	NodeSyncingCode int    = 110023
+	NodeSyncingMsg  string = "The node is syncing, please try again later"
+
+	TweetUsedCode int    = 110024
+	TweetUsedMsg  string = "The tweet has already been used to claim"
+
+	ActionProofErrCode int    = 110025
+	ActionProofErrMsg  string = "The required on-chain action was not found for this address: "
+
+	OutOfFundsCode int    = 110026
+	OutOfFundsMsg  string = "The faucet is temporarily out of funds, please try again later"
+
+	AuthorLimitCode int    = 110027
+	AuthorLimitMsg  string = "Too many claims from tweets of this account today, please try again tomorrow"
+
+	InvalidKeyCode int    = 110028
+	InvalidKeyMsg  string = "Invalid funding key: "
+
+	NotHolderCode int    = 110029
+	NotHolderMsg  string = "The address does not hold the token required by this campaign: "
+
+	RiskRejectedCode int    = 110030
+	RiskRejectedMsg  string = "The claim was rejected by risk control"
+
+	RPCBusyCode int    = 110031
+	RPCBusyMsg  string = "The faucet is temporarily busy, please try again later"
+
+	ReferralErrCode int    = 110032
+	ReferralErrMsg  string = "Invalid referral code: "
+
+	ReferralExhaustedCode int    = 110033
+	ReferralExhaustedMsg  string = "The referral code has been used up"
+
+	SanctionedCode int    = 110034
+	SanctionedMsg  string = "The address is not eligible to claim from the faucet"
+
+	ShuttingDownCode int    = 110035
+	ShuttingDownMsg  string = "The faucet is shutting down, please try again later"
+
+	DailyRecipientsCode int    = 110036
+	DailyRecipientsMsg  string = "The faucet has reached its daily recipient limit, please try again tomorrow"
+
+	NetDisabledCode int    = 110037
+	NetDisabledMsg  string = "Claims on this net are temporarily disabled"
+
+	InvalidEmailCode int    = 110038
+	InvalidEmailMsg  string = "Invalid email address"
+
+	EventInactiveCode int    = 110039
+	EventInactiveMsg  string = "The event is not active"
+
+	ReceiptNotFoundCode int    = 110040
+	ReceiptNotFoundMsg  string = "Claim receipt not found: "
+
+	ScreeningErrCode int    = 110041
+	ScreeningErrMsg  string = "Address screening is temporarily unavailable, please try again later"
+
+	RecoveringCode int    = 110042
+	RecoveringMsg  string = "The faucet is recovering, please try again later"
+
+	ZeroAddressCode int    = 110043
+	ZeroAddressMsg  string = "Cannot claim to the zero address"
+
+	SelfAddressCode int    = 110044
+	SelfAddressMsg  string = "Cannot claim to the faucet funding address"
+
+	ChainDisabledCode int    = 110045
+	ChainDisabledMsg  string = "Claims on this net are disabled because its RPC node is on a different chain"
+
 	// BlockChain Error
 	BlockChainCode int    = 120000
 	BlockChainMsg  string = "Blockchain communication error"
@@ -50,4 +170,7 @@ const (
 	// Scrapper Error
 	ScrapperErrCode int    = 130000
 	ScrapperErrMsg  string = "Someting went wrong, please try again later."
+
+	VerifierDownCode int    = 130001
+	VerifierDownMsg  string = "Tweet verification is temporarily unavailable, please try again later"
 )
@@ -0,0 +1,164 @@
+package global
+
+import (
+	"strings"
+)
+
+const (
+	LangEn = "en"
+	LangZh = "zh"
+)
+
+// catalog 错误码对应的多语言消息，code 在各语言下保持一致
+var catalog = map[string]map[int]string{
+	LangEn: {
+		SUCCESS:                SUCCESSMsg,
+		ParseErrCode:           ParseErrMsg,
+		CommonErrCode:          CommonErrMsg,
+		EmptyBodyErrCode:       EmptyBodyErrMsg,
+		SignErrCode:            SignErrMsg,
+		SignExpiredCode:        SignExpiredMsg,
+		UnauthorizedCode:       UnauthorizedMsg,
+		ErrAddrCode:            ErrAddrMsg,
+		NotSupportCode:         NotSupportMsg,
+		ReqWithinDayCode:       ReqWithinDayMsg,
+		EnoughTokenCode:        EnoughTokenMsg,
+		InsufficientCode:       InsufficientMsg,
+		TweetAddrErrCode:       TweetAddrErrMsg,
+		TweetLinkErrCode:       TweetLinkErrMsg,
+		TweetTimeErrCode:       TweetTimeErrMsg,
+		TweetUrlErrCode:        TweetUrlErrMsg,
+		AddrPreLockErrCode:     AddrPreLockErrMsg,
+		AccountActivityErrCode: AccountActivityErrMsg,
+		AuthTokenErrCode:       AuthTokenErrMsg,
+		AuthTokenExpiredCode:   AuthTokenExpiredMsg,
+		AuthTokenAddrErrCode:   AuthTokenAddrErrMsg,
+		SourceErrCode:          SourceErrMsg,
+		QueueFullCode:          QueueFullMsg,
+		TicketNotFoundCode:     TicketNotFoundMsg,
+		PausedCode:             PausedMsg,
+		SignatureErrCode:       SignatureErrMsg,
+		ChainMismatchCode:      ChainMismatchMsg,
+		ReserveErrCode:         ReserveErrMsg,
+		AddrBlockedCode:        AddrBlockedMsg,
+		IPAddressLimitCode:     IPAddressLimitMsg,
+		NodeSyncingCode:        NodeSyncingMsg,
+		TweetUsedCode:          TweetUsedMsg,
+		ActionProofErrCode:     ActionProofErrMsg,
+		OutOfFundsCode:         OutOfFundsMsg,
+		AuthorLimitCode:        AuthorLimitMsg,
+		InvalidKeyCode:         InvalidKeyMsg,
+		NotHolderCode:          NotHolderMsg,
+		RiskRejectedCode:       RiskRejectedMsg,
+		RPCBusyCode:            RPCBusyMsg,
+		ReferralErrCode:        ReferralErrMsg,
+		ReferralExhaustedCode:  ReferralExhaustedMsg,
+		SanctionedCode:         SanctionedMsg,
+		ShuttingDownCode:       ShuttingDownMsg,
+		DailyRecipientsCode:    DailyRecipientsMsg,
+		NetDisabledCode:        NetDisabledMsg,
+		ChainDisabledCode:      ChainDisabledMsg,
+		InvalidEmailCode:       InvalidEmailMsg,
+		EventInactiveCode:      EventInactiveMsg,
+		ReceiptNotFoundCode:    ReceiptNotFoundMsg,
+		ScreeningErrCode:       ScreeningErrMsg,
+		RecoveringCode:         RecoveringMsg,
+		ZeroAddressCode:        ZeroAddressMsg,
+		SelfAddressCode:        SelfAddressMsg,
+		BlockChainCode:         BlockChainMsg,
+		ScrapperErrCode:        ScrapperErrMsg,
+		VerifierDownCode:       VerifierDownMsg,
+	},
+	LangZh: {
+		SUCCESS:                "成功",
+		ParseErrCode:           "参数解析错误",
+		CommonErrCode:          "Axiomledger 网络错误，请稍后重试！",
+		EmptyBodyErrCode:       "缺少请求体",
+		SignErrCode:            "请求签名无效",
+		SignExpiredCode:        "请求时间戳超出允许范围",
+		UnauthorizedCode:       "未授权",
+		ErrAddrCode:            "无效地址: ",
+		NotSupportCode:         "不支持的网络: ",
+		ReqWithinDayCode:       "抱歉！为了对所有开发者公平，我们每24小时只发放一次，请在首次领取24小时后再试。",
+		EnoughTokenCode:        "该地址已有足够的测试代币",
+		InsufficientCode:       "水龙头错误",
+		TweetAddrErrCode:       "推文中的地址与当前填写的地址不一致",
+		TweetLinkErrCode:       "推文不符合要求，缺少链接",
+		TweetTimeErrCode:       "推文不符合要求，该推文发送已超过24小时",
+		TweetUrlErrCode:        "无效的推文链接",
+		AddrPreLockErrCode:     "该账户仍在处理中",
+		AccountActivityErrCode: "该地址不满足链上活跃度要求: ",
+		AuthTokenErrCode:       "无效的授权 token",
+		AuthTokenExpiredCode:   "授权 token 已过期",
+		AuthTokenAddrErrCode:   "授权 token 与当前填写的地址不一致",
+		SourceErrCode:          "无效的领取来源: ",
+		QueueFullCode:          "排队领取人数过多，请稍后再试",
+		TicketNotFoundCode:     "领取凭证不存在: ",
+		PausedCode:             "水龙头维护中，请在以下时间后重试: ",
+		SignatureErrCode:       "领取签名无效",
+		ChainMismatchCode:      "签名所属的链与当前网络不一致，期望的链 id: ",
+		ReserveErrCode:         "水龙头余额不足，请稍后再试",
+		AddrBlockedCode:        "该地址已被禁止领取",
+		IPAddressLimitCode:     "您的 IP 领取过的地址数量已达上限",
+		NodeSyncingCode:        "节点正在同步，请稍后再试",
+		TweetUsedCode:          "该推文已被用于领取",
+		ActionProofErrCode:     "未找到该地址所需的链上操作: ",
+		OutOfFundsCode:         "水龙头资金暂时不足，请稍后再试",
+		AuthorLimitCode:        "该账号的推文今日领取次数已达上限，请明天再试",
+		InvalidKeyCode:         "无效的资金账户私钥：",
+		NotHolderCode:          "该地址未持有此活动要求的代币：",
+		RiskRejectedCode:       "领取请求未通过风险控制",
+		RPCBusyCode:            "水龙头服务繁忙，请稍后再试",
+		ReferralErrCode:        "无效的推荐码：",
+		ReferralExhaustedCode:  "推荐码使用次数已用完",
+		SanctionedCode:         "该地址不符合水龙头领取条件",
+		ShuttingDownCode:       "水龙头服务正在停止，请稍后再试",
+		DailyRecipientsCode:    "水龙头今日领取地址数已达上限，请明天再试",
+		NetDisabledCode:        "该网络暂时停止领取",
+		ChainDisabledCode:      "该网络的 rpc 节点与配置的链 id 不一致，已停止领取",
+		InvalidEmailCode:       "邮箱地址无效",
+		EventInactiveCode:      "活动未在进行中",
+		ReceiptNotFoundCode:    "领取回执不存在：",
+		ScreeningErrCode:       "地址筛查服务暂时不可用，请稍后再试",
+		RecoveringCode:         "水龙头正在恢复中，请稍后再试",
+		ZeroAddressCode:        "不能向零地址领取",
+		SelfAddressCode:        "不能向水龙头资金账户领取",
+		BlockChainCode:         "区块链通信错误",
+		ScrapperErrCode:        "出现错误，请稍后再试。",
+		VerifierDownCode:       "推文验证服务暂时不可用，请稍后再试",
+	},
+}
+
+// ParseLanguage 从 Accept-Language 中选出第一个支持的语言，默认英文
+func ParseLanguage(acceptLanguage string) string {
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag = strings.TrimSpace(strings.Split(tag, ";")[0])
+		primary := strings.ToLower(strings.Split(tag, "-")[0])
+		if _, ok := catalog[primary]; ok {
+			return primary
+		}
+	}
+	return LangEn
+}
+
+// Localize 将英文消息替换为指定语言，消息中附带的地址、网络等细节保持不变
+func Localize(code int, msg string, lang string) string {
+	if lang == LangEn {
+		return msg
+	}
+	en, ok := catalog[LangEn][code]
+	if !ok || !strings.HasPrefix(msg, en) {
+		return msg
+	}
+	localized, ok := catalog[lang][code]
+	if !ok {
+		return msg
+	}
+	return localized + strings.TrimPrefix(msg, en)
+}
+
+// Message 返回错误码对应的英文消息
+func Message(code int) (string, bool) {
+	msg, ok := catalog[LangEn][code]
+	return msg, ok
+}
@@ -0,0 +1,58 @@
+package global
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseLanguage(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                        LangEn,
+		"zh-CN,zh;q=0.9,en;q=0.8": LangZh,
+		"fr-FR, zh;q=0.5":         LangZh,
+		"EN-us":                   LangEn,
+		"fr, de":                  LangEn,
+	} {
+		if got := ParseLanguage(header); got != want {
+			t.Errorf("%q: expect %s, got %s", header, want, got)
+		}
+	}
+}
+
+func TestLocalizeKeepsDetailSuffix(t *testing.T) {
+	msg := ErrAddrMsg + "0xabc"
+	if got := Localize(ErrAddrCode, msg, LangZh); got != catalog[LangZh][ErrAddrCode]+"0xabc" {
+		t.Fatalf("expect localized prefix with the address kept, got %q", got)
+	}
+	if got := Localize(ErrAddrCode, msg, LangEn); got != msg {
+		t.Fatalf("english message should be unchanged, got %q", got)
+	}
+	// 消息不是该错误码的标准消息时原样返回
+	if got := Localize(CommonErrCode, "custom failure", LangZh); got != "custom failure" {
+		t.Fatalf("custom message should be unchanged, got %q", got)
+	}
+}
+
+func TestCatalogTranslatesEveryMessage(t *testing.T) {
+	for code := range catalog[LangEn] {
+		if _, ok := catalog[LangZh][code]; !ok {
+			t.Errorf("code %d has no %s translation", code, LangZh)
+		}
+	}
+}
+
+func TestResultLocalizesByAcceptLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/", nil)
+	c.Request.Header.Set("Accept-Language", "zh-CN")
+
+	res := Fail(ReqWithinDayCode, ReqWithinDayMsg)
+	Result(res, c)
+	if res.Msg != catalog[LangZh][ReqWithinDayCode] {
+		t.Fatalf("expect zh message, got %q", res.Msg)
+	}
+}
@@ -0,0 +1,29 @@
+package global
+
+// reasons 失败响应中供前端区分处理的原因标识，覆盖预检可能返回的全部错误码
+var reasons = map[int]string{
+	ParseErrCode:           "invalid_params",
+	ErrAddrCode:            "invalid_address",
+	NotSupportCode:         "unsupported_net",
+	NetDisabledCode:        "net_disabled",
+	ChainDisabledCode:      "chain_mismatch",
+	NodeSyncingCode:        "node_syncing",
+	BlockChainCode:         "node_unavailable",
+	AddrBlockedCode:        "blocklisted",
+	SanctionedCode:         "sanctioned",
+	ScreeningErrCode:       "sanction_check_unavailable",
+	RecoveringCode:         "recovering",
+	ZeroAddressCode:        "zero_address",
+	SelfAddressCode:        "funding_address",
+	ReqWithinDayCode:       "already_claimed",
+	AddrPreLockErrCode:     "claim_in_progress",
+	AccountActivityErrCode: "insufficient_activity",
+	EnoughTokenCode:        "balance_too_high",
+	PausedCode:             "paused",
+	EventInactiveCode:      "event_inactive",
+}
+
+// Reason 返回错误码对应的原因标识，没有对应标识时返回空
+func Reason(code int) string {
+	return reasons[code]
+}
@@ -0,0 +1,49 @@
+package global
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReasonsAreDistinct(t *testing.T) {
+	seen := make(map[string]int)
+	for code, reason := range reasons {
+		if reason == "" {
+			t.Errorf("code %d has an empty reason", code)
+		}
+		if other, ok := seen[reason]; ok {
+			t.Errorf("codes %d and %d share reason %s", code, other, reason)
+		}
+		seen[reason] = code
+	}
+	if Reason(SUCCESS) != "" {
+		t.Fatal("success should not have a reason")
+	}
+}
+
+func TestResultFillsReasons(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/", nil)
+
+	res := FailAll([]*Response{Fail(ErrAddrCode, ErrAddrMsg), Fail(NotSupportCode, NotSupportMsg), Fail(CommonErrCode, CommonErrMsg)})
+	Result(res, c)
+	if res.Reason != "invalid_address" {
+		t.Fatalf("expect reason invalid_address, got %q", res.Reason)
+	}
+	for i, want := range []string{"invalid_address", "unsupported_net", ""} {
+		if res.Errors[i].Reason != want {
+			t.Fatalf("error %d: expect reason %q, got %q", i, want, res.Errors[i].Reason)
+		}
+	}
+
+	// 已设置的原因不被覆盖
+	res = Fail(ReqWithinDayCode, ReqWithinDayMsg)
+	res.Reason = "custom"
+	Result(res, c)
+	if res.Reason != "custom" {
+		t.Fatalf("preset reason should be kept, got %q", res.Reason)
+	}
+}
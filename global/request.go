@@ -1,17 +1,66 @@
 package global
 
 type DirectClaimReq struct {
-	Net     string `json:"net"`
-	Address string `json:"address"`
+	Net      string `json:"net"`
+	Address  string `json:"address"`
+	Source   string `json:"source"`
+	Referral string `json:"referral"`
+	// Email 选填，配置了邮件服务时向该邮箱发送领取回执
+	Email string `json:"email"`
 }
 
 type TweetClaimReq struct {
 	Net      string `json:"net"`
 	Address  string `json:"address"`
 	TweetUrl string `json:"tweetUrl"`
+	Source   string `json:"source"`
+	Referral string `json:"referral"`
+	// Email 选填，配置了邮件服务时向该邮箱发送领取回执
+	Email string `json:"email"`
 }
 
 type PreCheckReq struct {
 	Net     string `json:"net"`
 	Address string `json:"address"`
 }
+
+type AuthorizedClaimReq struct {
+	Net      string `json:"net"`
+	Address  string `json:"address"`
+	Token    string `json:"token"`
+	Source   string `json:"source"`
+	Referral string `json:"referral"`
+}
+
+type SignatureClaimReq struct {
+	Net       string `json:"net"`
+	Address   string `json:"address"`
+	ChainId   uint64 `json:"chainId"`
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+	Source    string `json:"source"`
+	Referral  string `json:"referral"`
+}
+
+type AddressNoteReq struct {
+	Note string `json:"note"`
+	Flag string `json:"flag"`
+}
+
+type AdminClaimReq struct {
+	Net     string  `json:"net"`
+	Address string  `json:"address"`
+	Amount  float64 `json:"amount"`
+	Source  string  `json:"source"`
+}
+
+type RotateKeyReq struct {
+	Net string `json:"net"`
+	Key string `json:"key"`
+}
+
+type ReferralCodeReq struct {
+	Code    string  `json:"code"`
+	MaxUses int     `json:"maxUses"`
+	Bonus   float64 `json:"bonus"`
+}
@@ -10,11 +10,42 @@ type Response struct {
 	Msg  string `json:"msg"`
 	Data string `json:"txHash"`
 	Code int    `json:"code"`
+	// Reason 失败原因标识，前端可据此展示对应的提示
+	Reason string `json:"reason,omitempty"`
+	Detail any    `json:"detail,omitempty"`
+	// Errors 开启返回全部校验错误时的失败原因列表
+	Errors []*ErrorReason `json:"errors,omitempty"`
+}
+
+type ErrorReason struct {
+	Code   int    `json:"code"`
+	Msg    string `json:"msg"`
+	Reason string `json:"reason,omitempty"`
+	Detail any    `json:"detail,omitempty"`
 }
 
 func Result(res *Response, c *gin.Context) {
 	// 开始时间
-	c.JSON(http.StatusOK, res)
+	lang := ParseLanguage(c.GetHeader("Accept-Language"))
+	res.Msg = Localize(res.Code, res.Msg, lang)
+	if res.Reason == "" {
+		res.Reason = Reason(res.Code)
+	}
+	for _, reason := range res.Errors {
+		reason.Msg = Localize(reason.Code, reason.Msg, lang)
+		if reason.Reason == "" {
+			reason.Reason = Reason(reason.Code)
+		}
+	}
+	c.JSON(httpStatus(res.Code), res)
+}
+
+// httpStatus 错误码对应的 HTTP 状态码，除水龙头资金不足返回 503 外均为 200
+func httpStatus(code int) int {
+	if code == OutOfFundsCode {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
 }
 
 func Success(data string) *Response {
@@ -25,9 +56,27 @@ func Success(data string) *Response {
 	}
 }
 
+func SuccessDetail(detail any) *Response {
+	return &Response{
+		Code:   SUCCESS,
+		Detail: detail,
+		Msg:    SUCCESSMsg,
+	}
+}
+
 func Fail(code int, Msg string) *Response {
 	return &Response{
 		Code: code,
 		Msg:  Msg,
 	}
 }
+
+// FailAll 返回多个失败原因，code、msg 取第一个失败原因以兼容只读取单个错误的调用方
+func FailAll(failures []*Response) *Response {
+	res := Fail(failures[0].Code, failures[0].Msg)
+	res.Detail = failures[0].Detail
+	for _, failure := range failures {
+		res.Errors = append(res.Errors, &ErrorReason{Code: failure.Code, Msg: failure.Msg, Detail: failure.Detail})
+	}
+	return res
+}
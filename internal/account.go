@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// 估算出块间隔时回溯的区块数
+const blockTimeSampleSize = 100
+
+// checkAccountActivity 校验领取地址的链上活跃度，防止新建账户批量领取
+// 配置了 MinAccountAge 时要求地址在该时长之前已发送过交易
+func (c *Client) checkAccountActivity(ctx context.Context, address string) (int, error) {
+	minNonce := c.Config.Axiom.MinAccountNonce
+	minAge := c.Config.Axiom.MinAccountAge.ToDuration()
+	if minNonce == 0 && minAge == 0 {
+		return global.SUCCESS, nil
+	}
+
+	var blockNumber *big.Int
+	if minAge > 0 {
+		if minNonce == 0 {
+			minNonce = 1
+		}
+		number, err := c.blockNumberBefore(minAge)
+		if err != nil {
+			c.requestLogger(ctx).Error(err)
+			return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+		}
+		blockNumber = number
+	}
+
+	nonce, err := c.axiomClient.NonceAt(context.Background(), common.HexToAddress(address), blockNumber)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	if nonce < minNonce {
+		requirement := fmt.Sprintf("at least %d transaction(s) sent", minNonce)
+		if minAge > 0 {
+			requirement += fmt.Sprintf(" more than %s ago", minAge)
+		}
+		return global.AccountActivityErrCode, fmt.Errorf(global.AccountActivityErrMsg + requirement)
+	}
+	return global.SUCCESS, nil
+}
+
+// blockNumberBefore 根据最近区块的平均出块间隔估算 age 之前的区块高度
+func (c *Client) blockNumberBefore(age time.Duration) (*big.Int, error) {
+	latest, err := c.axiomClient.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Number.Uint64() <= blockTimeSampleSize {
+		return big.NewInt(0), nil
+	}
+	sample, err := c.axiomClient.HeaderByNumber(context.Background(), new(big.Int).Sub(latest.Number, big.NewInt(blockTimeSampleSize)))
+	if err != nil {
+		return nil, err
+	}
+	blockTime := time.Duration(latest.Time-sample.Time) * time.Second / blockTimeSampleSize
+	if blockTime <= 0 {
+		blockTime = time.Second
+	}
+	blocks := uint64(age / blockTime)
+	if blocks >= latest.Number.Uint64() {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).SetUint64(latest.Number.Uint64() - blocks), nil
+}
+
+// checkRecipient 拒绝向零地址和当前资金账户地址领取，前者多为填写错误，后者只会白白消耗 gas
+func (c *Client) checkRecipient(address string) (int, error) {
+	recipient := common.HexToAddress(address)
+	if recipient == (common.Address{}) {
+		return global.ZeroAddressCode, fmt.Errorf(global.ZeroAddressMsg)
+	}
+	if funding := c.FundingAddress(); funding != "" && recipient == common.HexToAddress(funding) {
+		return global.SelfAddressCode, fmt.Errorf(global.SelfAddressMsg)
+	}
+	return global.SUCCESS, nil
+}
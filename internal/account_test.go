@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestCheckAccountActivityRequiresMinNonce(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.MinAccountNonce = 2
+	})
+
+	node.SetNonce(testRecipient, 1)
+	if code, _ := c.checkAccountActivity(context.Background(), testRecipient); code != global.AccountActivityErrCode {
+		t.Fatalf("address with 1 tx should be rejected, got %d", code)
+	}
+	node.SetNonce(testRecipient, 2)
+	if code, err := c.checkAccountActivity(context.Background(), testRecipient); err != nil {
+		t.Fatalf("address with 2 txs should pass, got %d %v", code, err)
+	}
+}
+
+func TestCheckAccountActivityDisabledByDefault(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+
+	if code, err := c.checkAccountActivity(context.Background(), testRecipient); err != nil {
+		t.Fatalf("fresh address should pass without activity policy, got %d %v", code, err)
+	}
+	if calls := node.Calls("eth_getTransactionCount"); calls != 0 {
+		t.Fatalf("activity policy is off, expect no nonce query, got %d", calls)
+	}
+}
+
+// 出块间隔 10s 时，1 小时前约为 360 个区块之前，按该高度查询地址当时的 nonce
+func TestCheckAccountActivityQueriesNonceAtAgeBlock(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.MinAccountAge = repo.Duration(time.Hour)
+	})
+	now := time.Now()
+	node.Handle("eth_getBlockByNumber", func(params []json.RawMessage) (any, error) {
+		var tag string
+		if err := json.Unmarshal(params[0], &tag); err != nil {
+			return nil, err
+		}
+		number := uint64(1000)
+		if tag != "latest" {
+			number = hexutil.MustDecodeUint64(tag)
+		}
+		return &types.Header{
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: new(big.Int),
+			Time:       uint64(now.Unix()) - (1000-number)*10,
+			Extra:      []byte{},
+		}, nil
+	})
+	var queried string
+	node.Handle("eth_getTransactionCount", func(params []json.RawMessage) (any, error) {
+		if err := json.Unmarshal(params[1], &queried); err != nil {
+			return nil, err
+		}
+		return hexutil.Uint64(1), nil
+	})
+
+	if code, err := c.checkAccountActivity(context.Background(), testRecipient); err != nil {
+		t.Fatalf("address active an hour ago should pass, got %d %v", code, err)
+	}
+	if want := hexutil.EncodeUint64(640); queried != want {
+		t.Fatalf("expect nonce queried at block %s, got %s", want, queried)
+	}
+}
+
+func TestClaimRejectsZeroAndFundingAddress(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	for _, tc := range []struct {
+		address string
+		code    int
+	}{
+		{"0x0000000000000000000000000000000000000000", global.ZeroAddressCode},
+		// 资金账户地址不区分大小写
+		{strings.ToLower(c.FundingAddress()), global.SelfAddressCode},
+	} {
+		if code, err := c.PreCheck(ctx, net, tc.address); code != tc.code {
+			t.Fatalf("preCheck of %s should fail with %d, got %d %v", tc.address, tc.code, code, err)
+		}
+		if _, code, err := claim(c, ctx, tc.address, 1); code != tc.code {
+			t.Fatalf("claim to %s should fail with %d, got %d %v", tc.address, tc.code, code, err)
+		}
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent, got %d", len(sent))
+	}
+	if code, err := c.PreCheck(ctx, net, testRecipient); err != nil {
+		t.Fatalf("preCheck of other addresses should pass, got %d %v", code, err)
+	}
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// 数量规则可用的信号：balance 为领取地址当前余额（ether），claims 为此前成功领取次数，net 为网络名
+const (
+	ruleSignalBalance = "balance"
+	ruleSignalClaims  = "claims"
+	ruleSignalNet     = "net"
+)
+
+// 按长度从长到短匹配，避免 <= 被识别为 <
+var ruleOperators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// amountRule 编译后的数量规则，conditions 全部满足时发放 amount
+type amountRule struct {
+	conditions []ruleCondition
+	amount     float64
+}
+
+type ruleCondition struct {
+	signal string
+	op     string
+	text   string
+	number float64
+}
+
+// ruleSignals 计算规则时的信号取值
+type ruleSignals struct {
+	Balance float64
+	Claims  int
+	Net     string
+}
+
+// compileAmountRules 解析配置中的数量规则，when 由 && 连接的若干 "信号 运算符 值" 组成，为空时总是匹配。
+// 只支持固定的信号与比较运算，不执行任意代码
+func compileAmountRules(rules []repo.AmountRule) ([]*amountRule, error) {
+	compiled := make([]*amountRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Amount <= 0 {
+			return nil, fmt.Errorf("amount rule %d: amount must be positive", i)
+		}
+		compiledRule := &amountRule{amount: rule.Amount}
+		if strings.TrimSpace(rule.When) != "" {
+			for _, clause := range strings.Split(rule.When, "&&") {
+				condition, err := parseRuleCondition(strings.TrimSpace(clause))
+				if err != nil {
+					return nil, fmt.Errorf("amount rule %d: %w", i, err)
+				}
+				compiledRule.conditions = append(compiledRule.conditions, condition)
+			}
+		}
+		compiled = append(compiled, compiledRule)
+	}
+	return compiled, nil
+}
+
+func parseRuleCondition(clause string) (ruleCondition, error) {
+	for _, op := range ruleOperators {
+		index := strings.Index(clause, op)
+		if index < 0 {
+			continue
+		}
+		condition := ruleCondition{
+			signal: strings.TrimSpace(clause[:index]),
+			op:     op,
+			text:   strings.TrimSpace(clause[index+len(op):]),
+		}
+		switch condition.signal {
+		case ruleSignalNet:
+			if op != "==" && op != "!=" {
+				return condition, fmt.Errorf("operator %s is not supported for net", op)
+			}
+		case ruleSignalBalance, ruleSignalClaims:
+			number, err := strconv.ParseFloat(condition.text, 64)
+			if err != nil {
+				return condition, fmt.Errorf("invalid number %q in %q", condition.text, clause)
+			}
+			condition.number = number
+		default:
+			return condition, fmt.Errorf("unknown signal %q in %q", condition.signal, clause)
+		}
+		return condition, nil
+	}
+	return ruleCondition{}, fmt.Errorf("no operator in %q", clause)
+}
+
+func (r *ruleCondition) match(signals ruleSignals) bool {
+	if r.signal == ruleSignalNet {
+		equal := strings.EqualFold(signals.Net, r.text)
+		return equal == (r.op == "==")
+	}
+	value := signals.Balance
+	if r.signal == ruleSignalClaims {
+		value = float64(signals.Claims)
+	}
+	switch r.op {
+	case "<":
+		return value < r.number
+	case "<=":
+		return value <= r.number
+	case ">":
+		return value > r.number
+	case ">=":
+		return value >= r.number
+	case "==":
+		return value == r.number
+	default:
+		return value != r.number
+	}
+}
+
+// evaluateAmountRules 返回第一条匹配规则的数量，没有匹配的规则时返回 false
+func evaluateAmountRules(rules []*amountRule, signals ruleSignals) (float64, bool) {
+	for _, rule := range rules {
+		matched := true
+		for i := range rule.conditions {
+			if !rule.conditions[i].match(signals) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule.amount, true
+		}
+	}
+	return 0, false
+}
+
+// usesSignal 规则中是否引用了指定信号，未引用的信号不查询
+func usesSignal(rules []*amountRule, signal string) bool {
+	for _, rule := range rules {
+		for _, condition := range rule.conditions {
+			if condition.signal == signal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ruleAmount 按配置的数量规则决定发放数量，没有配置或没有匹配的规则时使用 amount
+func (c *Client) ruleAmount(net string, address string, amount float64) (float64, error) {
+	if len(c.amountRules) == 0 {
+		return amount, nil
+	}
+	signals := ruleSignals{Net: net}
+	if usesSignal(c.amountRules, ruleSignalBalance) {
+		balance, err := c.axiomClient.BalanceAt(context.Background(), common.HexToAddress(address), nil)
+		if err != nil {
+			return 0, err
+		}
+		signals.Balance = etherBigIntToFloat(balance)
+	}
+	if usesSignal(c.amountRules, ruleSignalClaims) {
+		signals.Claims = c.ClaimCount(net, strings.ToLower(address))
+	}
+	if ruleAmount, ok := evaluateAmountRules(c.amountRules, signals); ok {
+		return ruleAmount, nil
+	}
+	return amount, nil
+}
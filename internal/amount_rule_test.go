@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestCompileAmountRulesRejectsInvalidRules(t *testing.T) {
+	cases := []repo.AmountRule{
+		{When: "balance < 1", Amount: 0},
+		{When: "balance 1", Amount: 1},
+		{When: "age > 1", Amount: 1},
+		{When: "claims < many", Amount: 1},
+		{When: "net > testnet", Amount: 1},
+		{When: "balance < 1 && ", Amount: 1},
+	}
+	for _, rule := range cases {
+		if _, err := compileAmountRules([]repo.AmountRule{rule}); err == nil {
+			t.Fatalf("rule %+v should be rejected", rule)
+		}
+	}
+}
+
+func TestEvaluateAmountRules(t *testing.T) {
+	rules, err := compileAmountRules([]repo.AmountRule{
+		{When: "net != testnet", Amount: 1},
+		{When: "balance < 1 && claims == 0", Amount: 5},
+		{When: "balance <= 10", Amount: 3},
+		{When: "claims >= 2", Amount: 0.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		signals ruleSignals
+		amount  float64
+		matched bool
+	}{
+		{ruleSignals{Net: "othernet"}, 1, true},
+		{ruleSignals{Net: "TestNet", Balance: 0.5}, 5, true},
+		{ruleSignals{Net: "testnet", Balance: 0.5, Claims: 1}, 3, true},
+		{ruleSignals{Net: "testnet", Balance: 10}, 3, true},
+		{ruleSignals{Net: "testnet", Balance: 11, Claims: 2}, 0.5, true},
+		{ruleSignals{Net: "testnet", Balance: 11, Claims: 1}, 0, false},
+	}
+	for _, tc := range cases {
+		amount, ok := evaluateAmountRules(rules, tc.signals)
+		if amount != tc.amount || ok != tc.matched {
+			t.Fatalf("signals %+v: expect %v %v, got %v %v", tc.signals, tc.amount, tc.matched, amount, ok)
+		}
+	}
+}
+
+// 空条件总是匹配
+func TestEvaluateAmountRulesEmptyWhen(t *testing.T) {
+	rules, err := compileAmountRules([]repo.AmountRule{{Amount: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount, ok := evaluateAmountRules(rules, ruleSignals{}); !ok || amount != 2 {
+		t.Fatalf("empty when should always match, got %v %v", amount, ok)
+	}
+}
+
+func TestClaimUsesAmountRules(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.AmountRules = []repo.AmountRule{{When: "balance < 1", Amount: 3}}
+	})
+	poor := "0x2222222222222222222222222222222222222222"
+	node.SetBalance(testRecipient, 5)
+
+	for address, want := range map[string]float64{poor: 3, testRecipient: 1} {
+		if _, code, err := claim(c, context.Background(), address, 1); err != nil {
+			t.Fatalf("claim failed: %d %v", code, err)
+		}
+		sent := node.Sent()
+		if to, amount := dripValue(t, sent[len(sent)-1]); to != address || amount != want {
+			t.Fatalf("expect drip of %v to %s, got %v to %s", want, address, amount, to)
+		}
+	}
+}
+
+// 只在规则引用余额时查询余额
+func TestRuleAmountQueriesOnlyUsedSignals(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.AmountRules = []repo.AmountRule{{When: "claims == 1", Amount: 2}}
+	})
+	net := c.Config.Axiom.TestNetName
+	if amount, err := c.ruleAmount(net, testRecipient, 1); err != nil || amount != 1 {
+		t.Fatalf("first claim should use the default amount, got %v %v", amount, err)
+	}
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	calls := node.Calls("eth_getBalance")
+	if amount, err := c.ruleAmount(net, testRecipient, 1); err != nil || amount != 2 {
+		t.Fatalf("claims signal should count the previous claim, got %v %v", amount, err)
+	}
+	if node.Calls("eth_getBalance") != calls {
+		t.Fatal("balance should not be queried when no rule uses it")
+	}
+}
@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// checkAmountUnits 启动时校验配置的原生代币数量。数量按 18 位精度的整币单位配置，
+// 超出 [MinSaneAmount, MaxSaneAmount] 通常是把 wei 当成整币（或反之）填写，直接拒绝启动以免耗尽资金
+func (c *Client) checkAmountUnits() error {
+	axiom := c.Config.Axiom
+	amounts := []struct {
+		name  string
+		value float64
+	}{
+		{"amount", axiom.Amount},
+		{"tweet_amount", axiom.TweetAmount},
+		{"top_up_target", axiom.TopUpTarget},
+	}
+	for _, amount := range amounts {
+		if amount.value == 0 {
+			continue
+		}
+		if axiom.MaxSaneAmount > 0 && amount.value > axiom.MaxSaneAmount {
+			return fmt.Errorf("%s %v exceeds max_sane_amount %v, amounts are configured in whole tokens of 18 decimals, not wei", amount.name, amount.value, axiom.MaxSaneAmount)
+		}
+		if axiom.MinSaneAmount > 0 && amount.value < axiom.MinSaneAmount {
+			return fmt.Errorf("%s %v is below min_sane_amount %v, amounts are configured in whole tokens of 18 decimals, not gwei or wei", amount.name, amount.value, axiom.MinSaneAmount)
+		}
+	}
+	return nil
+}
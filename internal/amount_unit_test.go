@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestCheckAmountUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(axiom *repo.AXIOM)
+		wantErr bool
+	}{
+		{"默认配置", func(axiom *repo.AXIOM) {}, false},
+		{"amount 按 wei 填写", func(axiom *repo.AXIOM) { axiom.Amount = 1e20 }, true},
+		{"tweet_amount 按 gwei 填写", func(axiom *repo.AXIOM) { axiom.TweetAmount = 1e-12 }, true},
+		{"top_up_target 过大", func(axiom *repo.AXIOM) { axiom.TopUpTarget = 5000 }, true},
+		{"为 0 的数量不校验", func(axiom *repo.AXIOM) { axiom.TopUpTarget = 0; axiom.TweetAmount = 0 }, false},
+		{"上限为 0 时不校验", func(axiom *repo.AXIOM) { axiom.MaxSaneAmount = 0; axiom.Amount = 1e20 }, false},
+		{"下限为 0 时不校验", func(axiom *repo.AXIOM) { axiom.MinSaneAmount = 0; axiom.Amount = 1e-12 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{Config: repo.DefaultConfig()}
+			tt.setup(&c.Config.Axiom)
+			if err := c.checkAmountUnits(); (err != nil) != tt.wantErr {
+				t.Fatalf("checkAmountUnits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInitializeRefusesAmountInWei(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.Amount = 100e18
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("amount configured in wei should be refused")
+	}
+}
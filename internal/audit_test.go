@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/audit"
+)
+
+func TestClaimWritesAuditEvents(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	buf := &bytes.Buffer{}
+	c.auditLogger = audit.NewWithWriter(buf)
+	ctx := WithRequest(context.Background(), Request{IP: "203.0.113.7"})
+	net := c.Config.Axiom.TestNetName
+
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	event := &audit.Event{}
+	if err := json.NewDecoder(buf).Decode(event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != audit.EventClaim || event.Address != testRecipient || event.TxHash != txHash || event.IP != "203.0.113.7" {
+		t.Fatalf("unexpected claim event %+v", event)
+	}
+
+	other := "0x2222222222222222222222222222222222222222"
+	if _, err := c.SetAddressNote(net, other, "", FlagBlocked); err != nil {
+		t.Fatal(err)
+	}
+	if _, code, _ := claim(c, ctx, other, 1); code != global.AddrBlockedCode {
+		t.Fatalf("expect %d, got %d", global.AddrBlockedCode, code)
+	}
+	event = &audit.Event{}
+	if err := json.NewDecoder(buf).Decode(event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != audit.EventClaimBlocked || event.Address != other {
+		t.Fatalf("unexpected blocked event %+v", event)
+	}
+}
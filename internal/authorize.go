@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// ClaimToken 可信后端签发的领取授权 token，address 为被授权领取的地址
+type ClaimToken struct {
+	Address string `json:"address"`
+	jwt.RegisteredClaims
+}
+
+// loadClaimTokenKey 根据签名算法加载校验授权 token 的密钥
+func loadClaimTokenKey(algorithm string, keyPath string) (any, error) {
+	raw, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read authorized claim key: %w", err)
+	}
+	switch {
+	case strings.HasPrefix(algorithm, "HS"):
+		return []byte(strings.TrimSpace(string(raw))), nil
+	case strings.HasPrefix(algorithm, "RS"), strings.HasPrefix(algorithm, "PS"):
+		return jwt.ParseRSAPublicKeyFromPEM(raw)
+	case strings.HasPrefix(algorithm, "ES"):
+		return jwt.ParseECPublicKeyFromPEM(raw)
+	case algorithm == "EdDSA":
+		return jwt.ParseEdPublicKeyFromPEM(raw)
+	default:
+		return nil, fmt.Errorf("unsupported authorized claim algorithm: %s", algorithm)
+	}
+}
+
+// VerifyClaimToken 校验授权 token 的签名、有效期以及授权地址
+func (c *Client) VerifyClaimToken(tokenString string, address string) (int, error) {
+	if c.claimTokenKey == nil {
+		return global.AuthTokenErrCode, fmt.Errorf(global.AuthTokenErrMsg)
+	}
+	claims := &ClaimToken{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		if token.Method.Alg() != c.Config.AuthorizedClaim.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+		return c.claimTokenKey, nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return global.AuthTokenExpiredCode, fmt.Errorf(global.AuthTokenExpiredMsg)
+		}
+		c.logger.Warnf("invalid claim token for %s: %v", address, err)
+		return global.AuthTokenErrCode, fmt.Errorf(global.AuthTokenErrMsg)
+	}
+	// 授权 token 必须是短期有效的
+	if claims.ExpiresAt == nil || time.Until(claims.ExpiresAt.Time) > c.Config.AuthorizedClaim.MaxTTL.ToDuration() {
+		c.logger.Warnf("claim token for %s expires too late: %v", address, claims.ExpiresAt)
+		return global.AuthTokenErrCode, fmt.Errorf(global.AuthTokenErrMsg)
+	}
+	if !strings.EqualFold(claims.Address, address) {
+		return global.AuthTokenAddrErrCode, fmt.Errorf(global.AuthTokenAddrErrMsg)
+	}
+	return global.SUCCESS, nil
+}
+
+func (c *Client) initClaimTokenKey(configPath string) error {
+	if !c.Config.AuthorizedClaim.Enable {
+		return nil
+	}
+	if c.Config.AuthorizedClaim.MaxTTL <= 0 {
+		return fmt.Errorf("authorized_claim.max_ttl must be greater than 0")
+	}
+	key, err := loadClaimTokenKey(c.Config.AuthorizedClaim.Algorithm, filepath.Join(configPath, c.Config.AuthorizedClaim.KeyPath))
+	if err != nil {
+		return err
+	}
+	c.claimTokenKey = key
+	return nil
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+var testClaimTokenSecret = []byte("claim-token-secret")
+
+func newClaimTokenClient(t *testing.T) *Client {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.AuthorizedClaim.Algorithm = "HS256"
+		cfg.AuthorizedClaim.MaxTTL = repo.Duration(10 * time.Minute)
+	})
+	c.claimTokenKey = testClaimTokenSecret
+	return c
+}
+
+func signClaimToken(t *testing.T, method jwt.SigningMethod, key any, address string, expiresAt time.Time) string {
+	t.Helper()
+	claims := &ClaimToken{Address: address}
+	if !expiresAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	}
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestVerifyClaimToken(t *testing.T) {
+	c := newClaimTokenClient(t)
+	now := time.Now()
+	for _, tc := range []struct {
+		name  string
+		token string
+		code  int
+	}{
+		{"valid", signClaimToken(t, jwt.SigningMethodHS256, testClaimTokenSecret, testRecipient, now.Add(time.Minute)), global.SUCCESS},
+		{"expired", signClaimToken(t, jwt.SigningMethodHS256, testClaimTokenSecret, testRecipient, now.Add(-time.Minute)), global.AuthTokenExpiredCode},
+		{"wrong address", signClaimToken(t, jwt.SigningMethodHS256, testClaimTokenSecret, "0x2222222222222222222222222222222222222222", now.Add(time.Minute)), global.AuthTokenAddrErrCode},
+		{"bad signature", signClaimToken(t, jwt.SigningMethodHS256, []byte("other-secret"), testRecipient, now.Add(time.Minute)), global.AuthTokenErrCode},
+		{"other algorithm", signClaimToken(t, jwt.SigningMethodHS512, testClaimTokenSecret, testRecipient, now.Add(time.Minute)), global.AuthTokenErrCode},
+		{"no expiry", signClaimToken(t, jwt.SigningMethodHS256, testClaimTokenSecret, testRecipient, time.Time{}), global.AuthTokenErrCode},
+		{"beyond max ttl", signClaimToken(t, jwt.SigningMethodHS256, testClaimTokenSecret, testRecipient, now.Add(time.Hour)), global.AuthTokenErrCode},
+		{"malformed", "not-a-jwt", global.AuthTokenErrCode},
+	} {
+		if code, err := c.VerifyClaimToken(tc.token, testRecipient); code != tc.code {
+			t.Errorf("%s: expect %d, got %d %v", tc.name, tc.code, code, err)
+		}
+	}
+}
+
+func TestLoadClaimTokenKeyFromPEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "authorized_claim.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadClaimTokenKey("ES256", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signClaimToken(t, jwt.SigningMethodES256, key, testRecipient, time.Now().Add(time.Minute))
+	if _, err := jwt.ParseWithClaims(token, &ClaimToken{}, func(*jwt.Token) (any, error) { return loaded, nil }); err != nil {
+		t.Fatalf("token signed by the key should verify with the loaded public key: %v", err)
+	}
+	if _, err := loadClaimTokenKey("none", path); err == nil {
+		t.Fatal("unsupported algorithm should be rejected")
+	}
+}
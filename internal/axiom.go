@@ -4,37 +4,47 @@ import (
 	"bytes"
 	"context"
 	"crypto/ecdsa"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"math/big"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Rican7/retry"
 	"github.com/Rican7/retry/backoff"
 	"github.com/Rican7/retry/strategy"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
 	"github.com/axiomesh/axiom-kit/storage"
 	"github.com/axiomesh/axiom-kit/storage/leveldb"
 	"github.com/axiomesh/faucet/global"
-	"github.com/axiomesh/faucet/internal/utils"
 	"github.com/axiomesh/faucet/persist"
+	"github.com/axiomesh/faucet/pkg/audit"
 	"github.com/axiomesh/faucet/pkg/loggers"
 	"github.com/axiomesh/faucet/pkg/repo"
 )
 
+// preLockMargin 同步领取的预锁在 network.write_timeout 之外多保留的时间，覆盖请求超时后仍在进行的发送；
+// 进程异常退出遗留的锁在 write_timeout + preLockMargin 后失效
+const preLockMargin = time.Minute
+
+// queuedPreLockTTL 排队领取的预锁一直持有到处理结束，重启时由 restoreTickets 恢复处理并释放
+const queuedPreLockTTL = 24 * time.Hour
+
+// ErrAddressLocked 地址已有领取正在处理，此时不能释放预锁，否则会解开其他请求持有的锁
+var ErrAddressLocked = errors.New(global.AddrPreLockErrMsg)
+
 type Client struct {
 	Config          *repo.Config
 	ctx             context.Context
@@ -42,44 +52,302 @@ type Client struct {
 	axiomLock       sync.Mutex
 	axiomAuth       *bind.TransactOpts
 	axiomPrivateKey *ecdsa.PrivateKey
-	ldb             storage.Storage
-	logger          logrus.FieldLogger
-	GinContext      *gin.Context
-	preLockCheck    sync.Mutex
+	// fundingReady 为 1 时资金账户私钥可用，启动时私钥无效则为 0，轮换为有效私钥后恢复
+	fundingReady int32
+	// chainMismatch 为 1 时 rpc 节点的链 id 与配置不一致，该网络停止领取
+	chainMismatch int32
+	ldb           storage.Storage
+	logger        logrus.FieldLogger
+	preLockCheck  sync.Mutex
+	cancel        context.CancelFunc
+	queue         chan *Ticket
+	tickets       map[string]*Ticket
+	ticketLock    sync.RWMutex
+	// queueStop 通知 worker 停止，worker 退出后关闭 queueDone；queueClosed 为 1 时不再接收新的排队领取，
+	// queueBusy 为 1 时 worker 正在处理领取
+	queueStop       chan struct{}
+	queueDone       chan struct{}
+	queueClosed     int32
+	queueBusy       int32
+	statsLock       sync.Mutex
+	statsCache      map[string]*statsCache
+	claimTokenKey   any
+	erc20Abi        abi.ABI
+	tokenDecimals   map[string]uint8
+	chainID         *big.Int
+	chainIDErr      error
+	chainIDOnce     sync.Once
+	inFlight        int64
+	lastErr         error
+	lastErrTime     time.Time
+	lastErrLock     sync.Mutex
+	tweetCache      map[string]time.Time
+	tweetCacheLock  sync.Mutex
+	tweetLock       sync.Mutex
+	lowBalanceAt    int64
+	rpcThrottled    int64
+	referralLock    sync.Mutex
+	sanctionLock    sync.Mutex
+	sanctionChecker SanctionChecker
+	sanctionCache   map[string]*sanctionResult
+	amountRules     []*amountRule
+	auditLogger     *audit.Logger
+	mailer          Mailer
+
+	// balanceLock 保护 cachedBalance 与 reservations：缓存的水龙头合约余额，为 nil 时需要重新查询；
+	// 已发出、尚未确认的领取交易哈希及发放数量
+	balanceLock   sync.Mutex
+	cachedBalance *big.Int
+	reservations  map[string]*big.Int
+
+	maintenanceLocation *time.Location
+	maintenanceWindows  []maintenanceWindow
+
+	// eventStart、eventEnd 限时活动的起止时间，为零值时该端不限制
+	eventStart time.Time
+	eventEnd   time.Time
+
+	// unsavedLock 保护 unsavedClaims，交易已发出但写入存储失败的领取记录，按地址记录键索引
+	unsavedLock   sync.Mutex
+	unsavedClaims map[string]*unsavedClaim
+
+	// latency 领取的发出与确认耗时统计
+	latency *claimLatency
+
+	// stuckLock 保护 stuckTxs，发送后未能及时确认、等待加价重发的交易，按 nonce 索引
+	stuckLock sync.Mutex
+	stuckTxs  map[uint64]*stuckTx
+
+	// nextNonce 由 axiomLock 保护，本进程发出的最后一笔交易的 nonce + 1，为 0 时尚未发送；
+	// nonceGap 为 1 时检测到 nonce 缺口并按 refuse 策略拒绝领取
+	nextNonce uint64
+	nonceGap  int32
+
+	// fundingAddress 当前资金账户地址，只保存公开地址，供状态与配置接口读取而不必等待 axiomLock
+	fundingAddress atomic.Value
 }
 
 type AddressData struct {
 	SendTxTime int64   `json:"sendTxTime"`
 	TxHash     string  `json:"txHash"`
 	Amount     float64 `json:"amount"`
+	Source     string  `json:"source,omitempty"`
+	Nonce      uint64  `json:"nonce"`
+	// Override 管理员指定数量的领取
+	Override bool `json:"override,omitempty"`
+	// Bonus 首次推文领取的额外奖励，已包含在 Amount 中
+	Bonus float64 `json:"bonus,omitempty"`
+	// GasEstimate 发送前的预估 gas，GasUsed、EffectiveGasPrice 取自交易回执
+	GasEstimate       uint64 `json:"gasEstimate,omitempty"`
+	GasUsed           uint64 `json:"gasUsed,omitempty"`
+	EffectiveGasPrice string `json:"effectiveGasPrice,omitempty"`
+	// From 发送该笔交易的资金账户地址，轮换私钥后仍记录实际使用的地址
+	From string `json:"from,omitempty"`
+	// Status、BlockNumber 交易确认后的最终状态与所在区块，历史查询直接读取，不再查询节点；早期记录的 status 为空，均为已确认
+	Status      string `json:"status,omitempty"`
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+}
+
+// SendTra 领取原生代币，amount 为按发放策略调整前的基础数量，referralBonus 为推荐码奖励，在发放策略之后叠加
+func (c *Client) SendTra(ctx context.Context, net string, address string, amount float64, referralBonus float64, tweetUrl string, source string) (string, int, error) {
+	if code, err := c.ReserveClaim(ctx, net, address); err != nil {
+		return "", code, err
+	}
+	return c.processClaim(ctx, net, address, amount, referralBonus, tweetUrl, source, false)
 }
 
-func (c *Client) SendTra(net string, address string, amount float64, tweetUrl string) (string, int, error) {
+// AdminClaim 管理员指定数量领取，不按领取次数分档，记录中标记为管理员指定。
+// 只做准入校验并加地址预锁，不受领取间隔、IP 与每日地址数配额以及风险评分限制，这些限制针对的是公开领取的请求方
+func (c *Client) AdminClaim(ctx context.Context, net string, address string, amount float64, source string) (string, int, error) {
+	if code, err := c.checkClaimGates(ctx, net, address); err != nil {
+		return "", code, err
+	}
+	if err := c.preflightBalance(ctx); err != nil {
+		if err.Error() == global.ReserveErrMsg {
+			return "", global.ReserveErrCode, err
+		}
+		return "", global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+	if err := c.lockAddress(net, global.NativeToken, strings.ToLower(address)); err != nil {
+		return "", global.AddrPreLockErrCode, err
+	}
+	return c.processClaim(ctx, net, address, amount, 0, "", source, true)
+}
+
+// ReserveClaim 预检水龙头余额后加地址预锁并校验每日领取限制，调用方在领取结束后通过 DeleteTxData 释放预锁
+func (c *Client) ReserveClaim(ctx context.Context, net string, address string) (int, error) {
+	if code, err := c.checkClaimGates(ctx, net, address); err != nil {
+		return code, err
+	}
+	return c.reserveNativeClaim(ctx, net, address)
+}
+
+// checkClaimGates 领取的准入校验：资金账户、nonce 恢复、接收地址、黑名单与制裁名单，与具体代币无关
+func (c *Client) checkClaimGates(ctx context.Context, net string, address string) (int, error) {
+	lowerAddress := strings.ToLower(address)
+	if code, err := c.checkChain(); err != nil {
+		return code, err
+	}
+	if !c.FundingReady() {
+		return global.NetDisabledCode, fmt.Errorf(global.NetDisabledMsg)
+	}
+	if code, err := c.checkRecovering(); err != nil {
+		return code, err
+	}
+	if code, err := c.checkRecipient(address); err != nil {
+		return code, err
+	}
+	if c.isBlocked(net, lowerAddress) {
+		c.Audit(ctx, &audit.Event{Type: audit.EventClaimBlocked, Net: net, Address: lowerAddress})
+		return global.AddrBlockedCode, fmt.Errorf(global.AddrBlockedMsg)
+	}
+	if code, err := c.checkSanction(ctx, net, lowerAddress); err != nil {
+		return code, err
+	}
+	return global.SUCCESS, nil
+}
+
+// reserveNativeClaim 预检余额后加原生代币的地址预锁并校验领取间隔与领取配额
+func (c *Client) reserveNativeClaim(ctx context.Context, net string, address string) (int, error) {
+	lowerAddress := strings.ToLower(address)
+	if err := c.preflightBalance(ctx); err != nil {
+		if err.Error() == global.ReserveErrMsg {
+			return global.ReserveErrCode, err
+		}
+		return global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+	// 合法校验：每天每个(net + type + addr)只发一个
+	if err := c.checkLimit(ctx, net, global.NativeToken, lowerAddress, c.ldb); err != nil {
+		if errors.Is(err, ErrAddressLocked) {
+			return global.AddrPreLockErrCode, err
+		}
+		return global.ReqWithinDayCode, err
+	}
+	return c.checkClaimQuota(ctx, net, lowerAddress)
+}
+
+// checkClaimQuota 校验单个 IP 领取的地址数与网络每天发放的地址数
+func (c *Client) checkClaimQuota(ctx context.Context, net string, lowerAddress string) (int, error) {
+	if err := c.checkIPAddressLimit(ctx, net, lowerAddress); err != nil {
+		if err.Error() == global.IPAddressLimitMsg {
+			return global.IPAddressLimitCode, err
+		}
+		c.requestLogger(ctx).Error(err)
+		return global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+	if err := c.checkDailyRecipients(ctx, net, lowerAddress); err != nil {
+		if err.Error() == global.DailyRecipientsMsg {
+			return global.DailyRecipientsCode, err
+		}
+		c.requestLogger(ctx).Error(err)
+		return global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+	return global.SUCCESS, nil
+}
+
+// processClaim 在已通过 ReserveClaim 的前提下完成校验并发送交易。amount 按规则、分档与拥堵倍数调整，
+// referralBonus 与首次推文奖励一样在调整之后叠加，不随这些策略变化
+func (c *Client) processClaim(ctx context.Context, net string, address string, amount float64, referralBonus float64, tweetUrl string, source string, override bool) (string, int, error) {
 	var (
 		txHash string
 		err    error
 	)
 	lowerAddress := strings.ToLower(address)
-	// 合法校验：每天每个(net + type + addr)只发一个
-	if err := c.checkLimit(net, global.NativeToken, lowerAddress, c.ldb); err != nil {
-		if err.Error() == global.AddrPreLockErrMsg {
-			return "", global.AddrPreLockErrCode, err
+	atomic.AddInt64(&c.inFlight, 1)
+	defer atomic.AddInt64(&c.inFlight, -1)
+	start := time.Now()
+
+	if code, err := c.checkChainLag(); err != nil {
+		return "", code, err
+	}
+	if code, err := c.checkAccountActivity(ctx, address); err != nil {
+		return "", code, err
+	}
+	if !override {
+		if code, err := c.checkRisk(ctx, net, lowerAddress); err != nil {
+			return "", code, err
 		}
-		return "", global.ReqWithinDayCode, err
+	}
+	if code, err := c.checkActionProof(ctx, source, address); err != nil {
+		return "", code, err
+	}
+	if code, err := c.checkHolding(ctx, source, address); err != nil {
+		return "", code, err
 	}
 
+	var degraded bool
 	if tweetUrl != "" {
-		code, msg := c.TweetReqCheck(tweetUrl, address)
-		if code != global.SUCCESS {
+		code, msg := c.TweetReqCheck(ctx, tweetUrl, address)
+		if code == global.VerifierDownCode && c.Config.Scrapper.UnavailablePolicy == repo.VerifierPolicyDegraded {
+			c.requestLogger(ctx).Warnf("tweet verifier is unavailable, accept %s of %s by url only with degraded amount", tweetUrl, address)
+			degraded = true
+		} else if code != global.SUCCESS {
 			return "", code, fmt.Errorf(msg)
 		}
+		day, code, err := c.reserveTweet(ctx, net, tweetUrl, address)
+		if err != nil {
+			return "", code, err
+		}
+		// 交易发出前失败时释放推文
+		defer func() {
+			if txHash == "" {
+				c.releaseTweet(ctx, net, tweetUrl, day)
+			}
+		}()
+	}
+
+	if !override {
+		if amount, err = c.ruleAmount(net, address, amount); err != nil {
+			c.requestLogger(ctx).Error(err)
+			return "", global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+		}
+		amount = c.tieredAmount(net, lowerAddress, amount)
+		if amount, err = c.balanceTierAmount(amount); err != nil {
+			c.requestLogger(ctx).Error(err)
+			return "", global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+		}
+		if amount, err = c.congestionAmount(amount); err != nil {
+			c.requestLogger(ctx).Error(err)
+			return "", global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+		}
+	}
+	var bonus float64
+	if degraded {
+		amount *= c.Config.Scrapper.DegradedMultiplier
+	} else if tweetUrl != "" {
+		bonus = c.firstTweetBonus(net, lowerAddress)
+		amount += bonus
+	}
+	amount += referralBonus
+	amount, err = topUpAmount(ctx, c, address, amount)
+	if err != nil {
+		if err.Error() == global.EnoughTokenMsg {
+			return "", global.EnoughTokenCode, err
+		}
+		return "", global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
 	}
 
-	txHash, err = sendTxAxm(c, address, amount)
+	tx, estimate, err := c.sendTxAxmWithRetry(ctx, address, amount)
 	if err != nil {
+		c.recordError(err)
+		c.invalidateFaucetBalance()
+		if isRPCThrottled(err) {
+			return "", global.RPCBusyCode, fmt.Errorf(global.RPCBusyMsg)
+		}
 		if err.Error() == global.EnoughTokenMsg {
 			return "", global.EnoughTokenCode, err
 		}
+		if err.Error() == global.ReserveErrMsg {
+			return "", global.ReserveErrCode, err
+		}
+		if err.Error() == global.RecoveringMsg {
+			return "", global.RecoveringCode, err
+		}
+		if isInsufficientFunds(err) {
+			c.requestLogger(ctx).Errorf("faucet out of funds: %v", err)
+			c.markLowBalance()
+			return "", global.OutOfFundsCode, fmt.Errorf(global.OutOfFundsMsg)
+		}
 		matched, matchErr := regexp.MatchString("Failed dripping", err.Error())
 		if matchErr != nil {
 			return "", global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
@@ -89,44 +357,141 @@ func (c *Client) SendTra(net string, address string, amount float64, tweetUrl st
 		}
 		return "", global.CommonErrCode, fmt.Errorf("%s-%s %s", "Axiomledger", c.Config.Axiom.TestNetName, "Network Error，Please Try Again Later！")
 	}
-	if checkTxSuccess(c, txHash) {
-		if err := putTxData(txHash, c, lowerAddress, global.NativeToken, net); err != nil {
-			return "", global.CommonErrCode, fmt.Errorf("%s-%s %s", "Axiomledger", c.Config.Axiom.TestNetName, "Network Error，Please Try Again Later！")
+	txHash = tx.Hash().Hex()
+	sentAt := time.Now()
+	c.latency.observeSubmit(sentAt.Sub(start))
+	if bonus > 0 {
+		c.markTweetBonus(net, lowerAddress)
+	}
+	receipt, ok := checkTxSuccess(c, txHash)
+	c.latency.observeConfirm(time.Since(sentAt), ok)
+	if ok {
+		c.releaseFunds(txHash, true)
+		data := &AddressData{
+			SendTxTime: time.Now().Unix(),
+			TxHash:     txHash,
+			Amount:     amount,
+			Source:     source,
+			Nonce:      tx.Nonce(),
+			Override:   override,
+			Bonus:      bonus,
+			From:       txSender(tx),
+			Status:     ReceiptConfirmed,
+		}
+		c.recordGas(ctx, data, estimate, receipt)
+		c.storeClaimData(ctx, net, global.NativeToken, lowerAddress, data)
+		c.notifyWebhook(newClaimEvent(net, lowerAddress, data))
+		event := &audit.Event{Type: audit.EventClaim, Net: net, Address: lowerAddress, Token: global.NativeToken, TxHash: txHash, Amount: amount}
+		if override {
+			event.Detail = "admin override"
 		}
+		c.Audit(ctx, event)
+	} else {
+		// 未确认的交易继续预留发放数量，同时写入 pending 状态的领取记录，避免确认前重复领取，
+		// 查到最终回执后由 finishReceipt 更新
+		if c.putUnconfirmedReceipt(ctx, net, global.NativeToken, lowerAddress, tx, amount) == ReceiptPending {
+			c.storeClaimData(ctx, net, global.NativeToken, lowerAddress, &AddressData{
+				SendTxTime: time.Now().Unix(),
+				TxHash:     txHash,
+				Amount:     amount,
+				Source:     source,
+				Nonce:      tx.Nonce(),
+				Override:   override,
+				Bonus:      bonus,
+				From:       txSender(tx),
+				Status:     ReceiptPending,
+			})
+		}
+		c.trackStuckTx(ctx, tx)
 	}
 	return txHash, global.SUCCESS, nil
 }
 
-func (c *Client) PreCheck(net string, address string) (int, error) {
+// PreCheck 领取前的预检，每种失败原因返回各自的错误码
+func (c *Client) PreCheck(ctx context.Context, net string, address string) (int, error) {
 	lowerAddress := strings.ToLower(address)
+	if code, err := c.checkChain(); err != nil {
+		return code, err
+	}
+	if !c.FundingReady() {
+		return global.NetDisabledCode, fmt.Errorf(global.NetDisabledMsg)
+	}
+	if code, err := c.checkRecovering(); err != nil {
+		return code, err
+	}
+	if code, err := c.checkRecipient(address); err != nil {
+		return code, err
+	}
+	if code, err := c.checkChainLag(); err != nil {
+		return code, err
+	}
+	if c.isBlocked(net, lowerAddress) {
+		c.Audit(ctx, &audit.Event{Type: audit.EventClaimBlocked, Net: net, Address: lowerAddress})
+		return global.AddrBlockedCode, fmt.Errorf(global.AddrBlockedMsg)
+	}
+	if code, err := c.checkSanction(ctx, net, lowerAddress); err != nil {
+		return code, err
+	}
 	// 合法校验：每天每个(net + type + addr)只发一个
-	if err := c.precheckLimit(net, global.NativeToken, lowerAddress, c.ldb); err != nil {
-		if err.Error() == global.AddrPreLockErrMsg {
+	if err := c.precheckLimit(ctx, net, global.NativeToken, lowerAddress, c.ldb); err != nil {
+		if errors.Is(err, ErrAddressLocked) {
 			return global.AddrPreLockErrCode, err
 		}
 		return global.ReqWithinDayCode, err
 	}
-	judge, err := checkBalance(c, address)
+	if code, err := c.checkAccountActivity(ctx, address); err != nil {
+		return code, err
+	}
+	judge, err := checkBalance(ctx, c, address)
 	if err != nil && !judge {
 		if err.Error() == global.EnoughTokenMsg {
 			return global.EnoughTokenCode, err
 		}
-		return global.CommonErrCode, fmt.Errorf("%s-%s %s", "Axiomledger", c.Config.Axiom.TestNetName, "Network Error，Please Try Again Later！")
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
 	}
 	return global.SUCCESS, nil
 }
 
-func putTxData(txHash string, c *Client, address string, typ string, net string) error {
-	p := &AddressData{
-		SendTxTime: time.Now().Unix(),
-		TxHash:     txHash,
-		Amount:     c.Config.Axiom.Amount,
+// tieredAmount 按地址此前成功领取的次数选择发放倍数，超过配置档位数时使用最后一档
+func (c *Client) tieredAmount(net string, address string, amount float64) float64 {
+	tiers := c.Config.Axiom.ClaimTierMultipliers
+	if len(tiers) == 0 {
+		return amount
+	}
+	count := c.ClaimCount(net, address)
+	if count >= len(tiers) {
+		count = len(tiers) - 1
 	}
+	return amount * tiers[count]
+}
+
+func putTxData(ctx context.Context, c *Client, address string, typ string, net string, p *AddressData) (err error) {
 	structJSON, err := json.Marshal(p)
 	if err != nil {
 		return fmt.Errorf("json marshal failed: %w", err)
 	}
-	c.ldb.Put(c.construAddressKey(net, typ, address), structJSON)
+	// leveldb 写入失败时 panic，转为错误交由调用方重试
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("write claim data: %v", r)
+		}
+	}()
+	key := c.construAddressKey(net, typ, address)
+	firstClaim := !c.ldb.Has(key)
+	c.ldb.Put(key, structJSON)
+	if err := c.putClaimRecord(net, typ, address, p); err != nil {
+		c.requestLogger(ctx).Errorf("put claim record of %s failed: %v", address, err)
+	}
+	if err := c.putReceipt(net, typ, address, p); err != nil {
+		c.requestLogger(ctx).Errorf("put receipt of %s failed: %v", p.TxHash, err)
+	}
+	// 统计只累计原生代币，代币数量单位不同
+	if typ == global.NativeToken {
+		c.markDailyRecipient(ctx, net, address)
+		if err := c.incrStats(net, address, p.Amount, firstClaim, p.Source); err != nil {
+			c.requestLogger(ctx).Errorf("update stats of %s failed: %v", net, err)
+		}
+	}
 	return nil
 }
 
@@ -144,135 +509,224 @@ func (c *Client) construAddressKey(net string, typ string, address string) []byt
 	return persist.CompositeKey(net, buffer)
 }
 
+// construPreLockAddressKey 预锁 key 只由网络、地址和代币组成，所有领取接口共用同一把锁；
+// key 中不含日期，避免跨零点时两个请求分别拿到不同日期的锁
 func (c *Client) construPreLockAddressKey(net string, typ string, address string) []byte {
-	// get public_ip and ip with address and net tobe key
 	var buffer bytes.Buffer
-	buffer.WriteString(time.Now().Format("2006-01-02"))
-	buffer.WriteString("-")
+	buffer.WriteString("prelock-")
 	buffer.WriteString(address)
 	buffer.WriteString("-")
 	buffer.WriteString(typ)
 	return persist.CompositeKey(net, buffer)
 }
 
-func (c *Client) construIpKey(net string) []byte {
-	// get public_ip and ip with address and net tobe key
-	var buffer bytes.Buffer
-	buffer.WriteString(time.Now().Format("2006-01-02"))
-	buffer.WriteString("-")
-	buffer.WriteString(utils.ClientPublicIP(c.GinContext.Request))
-	buffer.WriteString("-")
-	buffer.WriteString(utils.ClientIp(c.GinContext.Request))
-	return persist.CompositeKey(net, buffer)
+func (c *Client) checkLimit(ctx context.Context, net string, typ string, address string, ldb storage.Storage) error {
+	c.preLockCheck.Lock()
+	defer c.preLockCheck.Unlock()
+	if err := c.putPreLock(net, typ, address, ldb); err != nil {
+		return err
+	}
+	key := c.construAddressKey(net, typ, address)
+	value := ldb.Get(key)
+	if value != nil {
+		value = c.repairFutureClaim(ctx, key, address, value)
+	}
+	if unsaved := c.unsavedClaimData(key); unsaved != nil {
+		value = unsaved
+	}
+	if value != nil {
+		return c.checkClaimInterval(ctx, address, value)
+	}
+	return nil
 }
 
-func (c *Client) checkLimit(net string, typ string, address string, ldb storage.Storage) error {
+// lockAddress 只加地址预锁，不校验领取间隔
+func (c *Client) lockAddress(net string, typ string, address string) error {
 	c.preLockCheck.Lock()
 	defer c.preLockCheck.Unlock()
+	return c.putPreLock(net, typ, address, c.ldb)
+}
+
+// putPreLock 地址未被锁定时加预锁，调用方需持有 preLockCheck
+func (c *Client) putPreLock(net string, typ string, address string, ldb storage.Storage) error {
 	valuePreLockData := ldb.Get(c.construPreLockAddressKey(net, typ, address))
-	if valuePreLockData != nil {
-		return fmt.Errorf(global.AddrPreLockErrMsg)
+	if isPreLocked(valuePreLockData) {
+		return ErrAddressLocked
 	}
-	c.ldb.Put(c.construPreLockAddressKey(net, global.NativeToken, address), []byte("preLock"))
-	value := ldb.Get(c.construAddressKey(net, typ, address))
-	if value != nil {
-		data := AddressData{}
-		if err := json.Unmarshal(value, &data); err != nil {
-			return errors.New("unmarshal error")
-		}
-		// 获取当前时间的 Unix 时间戳
-		currentUnixTime := time.Now().Unix()
+	c.ldb.Put(c.construPreLockAddressKey(net, typ, address), preLockValue(c.preLockTTL()))
+	return nil
+}
 
-		// 计算时间差（以秒为单位）
-		timeDifference := currentUnixTime - data.SendTxTime
+// preLockTTL 同步领取的预锁有效期，略长于请求超时
+func (c *Client) preLockTTL() time.Duration {
+	return c.Config.Network.WriteTimeout.ToDuration() + preLockMargin
+}
 
-		// 定义一天的秒数
-		oneDayInSeconds := int64(24 * 60 * 60)
+// extendPreLock 延长已持有的预锁，排队领取入队前调用
+func (c *Client) extendPreLock(net string, typ string, address string, ttl time.Duration) {
+	c.preLockCheck.Lock()
+	defer c.preLockCheck.Unlock()
+	c.ldb.Put(c.construPreLockAddressKey(net, typ, address), preLockValue(ttl))
+}
 
-		// 比较时间差与一天的秒数
-		if timeDifference <= oneDayInSeconds {
-			return fmt.Errorf(global.ReqWithinDayMsg)
-		}
+// preLockValue 预锁记录失效时间
+func preLockValue(ttl time.Duration) []byte {
+	return []byte(strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+}
+
+// isPreLocked 预锁记录失效时间，进程异常退出遗留的锁过期后失效
+func isPreLocked(value []byte) bool {
+	if value == nil {
+		return false
 	}
-	return nil
+	expireAt, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return true
+	}
+	return time.Now().Before(time.Unix(expireAt, 0))
 }
 
-func (c *Client) precheckLimit(net string, typ string, address string, ldb storage.Storage) error {
+func (c *Client) precheckLimit(ctx context.Context, net string, typ string, address string, ldb storage.Storage) error {
 	valuePreLockData := ldb.Get(c.construPreLockAddressKey(net, typ, address))
-	if valuePreLockData != nil {
-		return fmt.Errorf(global.AddrPreLockErrMsg)
+	if isPreLocked(valuePreLockData) {
+		return ErrAddressLocked
 	}
 	value := ldb.Get(c.construAddressKey(net, typ, address))
+	if unsaved := c.unsavedClaimData(c.construAddressKey(net, typ, address)); unsaved != nil {
+		value = unsaved
+	}
 	if value != nil {
-		data := AddressData{}
-		if err := json.Unmarshal(value, &data); err != nil {
-			return errors.New("unmarshal error")
+		return c.checkClaimInterval(ctx, address, value)
+	}
+	return nil
+}
+
+// isTester 测试地址白名单只跳过每日领取间隔，其他限制照常生效
+func (c *Client) isTester(address string) bool {
+	for _, tester := range c.Config.Axiom.TesterAllowlist {
+		if strings.EqualFold(tester, address) {
+			return true
 		}
-		// 获取当前时间的 Unix 时间戳
-		currentUnixTime := time.Now().Unix()
+	}
+	return false
+}
 
-		// 计算时间差（以秒为单位）
-		timeDifference := currentUnixTime - data.SendTxTime
+// checkClaimInterval 校验上次领取记录是否仍在24小时限制内，只读取记录，预检与领取共用
+func (c *Client) checkClaimInterval(ctx context.Context, address string, value []byte) error {
+	if c.isTester(address) || c.Config.DevMode {
+		c.requestLogger(ctx).Infof("%s bypasses the claim interval", address)
+		return nil
+	}
+	data := AddressData{}
+	if err := json.Unmarshal(value, &data); err != nil {
+		return errors.New("unmarshal error")
+	}
+	// 获取当前时间的 Unix 时间戳
+	currentUnixTime := time.Now().Unix()
 
-		// 定义一天的秒数
-		oneDayInSeconds := int64(24 * 60 * 60)
+	// 记录时间晚于当前时间且超出容忍范围，说明时钟回拨或多节点时钟不一致，按刚领取处理，
+	// 避免地址在时钟追上记录时间之前一直无法领取；存储的记录只在领取时由 repairFutureClaim 修正
+	if c.beyondClockSkew(data.SendTxTime, currentUnixTime) {
+		data.SendTxTime = currentUnixTime
+	}
 
-		// 比较时间差与一天的秒数
-		if timeDifference <= oneDayInSeconds {
-			return fmt.Errorf(global.ReqWithinDayMsg)
-		}
+	// 计算时间差（以秒为单位），时钟偏差为负时差值为负，同样视为限制期内
+	timeDifference := currentUnixTime - data.SendTxTime
+
+	// 定义一天的秒数
+	oneDayInSeconds := int64(24 * 60 * 60)
+
+	// 比较时间差与一天的秒数
+	if timeDifference <= oneDayInSeconds {
+		return fmt.Errorf(global.ReqWithinDayMsg)
 	}
 	return nil
 }
 
-func checkTxSuccess(c *Client, txHash string) bool {
+// repairFutureClaim 领取时将超出时钟容忍范围的未来领取记录修正为当前时间并写回，返回修正后的记录；
+// 调用方需持有 preLockCheck 与地址预锁，预检等只读路径不修改记录
+func (c *Client) repairFutureClaim(ctx context.Context, key []byte, address string, value []byte) []byte {
+	data := AddressData{}
+	if err := json.Unmarshal(value, &data); err != nil {
+		return value
+	}
+	now := time.Now().Unix()
+	if !c.beyondClockSkew(data.SendTxTime, now) {
+		return value
+	}
+	c.requestLogger(ctx).Warnf("claim record of %s is %ds in the future, exceeds max clock skew %s, clamp it to now", address, data.SendTxTime-now, c.Config.Axiom.MaxClockSkew.String())
+	data.SendTxTime = now
+	clamped, err := json.Marshal(&data)
+	if err != nil {
+		return value
+	}
+	c.ldb.Put(key, clamped)
+	return clamped
+}
+
+// beyondClockSkew 领取记录时间晚于当前时间且超出 max_clock_skew
+func (c *Client) beyondClockSkew(sendTxTime int64, now int64) bool {
+	return sendTxTime-now > int64(c.Config.Axiom.MaxClockSkew.ToDuration().Seconds())
+}
+
+// checkTxSuccess 等待交易回执，回执状态为失败或查询不到时返回 false
+func checkTxSuccess(c *Client, txHash string) (*types.Receipt, bool) {
 	client := c.axiomClient
+	var receipt *types.Receipt
 	err := retry.Retry(func(attempt uint) error {
-		receipt, err := client.TransactionReceipt(context.Background(), common.HexToHash(txHash))
+		r, err := client.TransactionReceipt(context.Background(), common.HexToHash(txHash))
 		if err != nil {
 			return err
 		}
-		if err == nil && receipt != nil {
-			if receipt.Status == types.ReceiptStatusFailed {
-				return errors.New("faucet transfer failed")
-			}
+		if r != nil && r.Status == types.ReceiptStatusFailed {
+			return errors.New("faucet transfer failed")
 		}
+		receipt = r
 		return nil
 	}, strategy.Limit(3), strategy.Backoff(backoff.Fibonacci(200*time.Millisecond)))
 	if err != nil {
-		return false
+		return nil, false
 	}
-	return true
+	return receipt, true
 }
 
 func (c *Client) Initialize(cfg *repo.Config, configPath string) error {
-	c.ctx = context.Background()
+	c.ctx, c.cancel = context.WithCancel(context.Background())
 	c.Config = cfg
+	c.logger = loggers.Logger(loggers.ApiServer)
+	auditLogger, err := audit.New(cfg.Audit, configPath, cfg.Log.RedactAddresses)
+	if err != nil {
+		return err
+	}
+	c.auditLogger = auditLogger
 	// 构建axiom客户端
 	axiomClient, err := ethclient.Dial(cfg.Axiom.AxiomAddr)
 	if err != nil {
 		return fmt.Errorf("dial axiom node: %w", err)
 	}
 	c.axiomClient = axiomClient
-
-	// 构建auth_axm
-	keyPathAxm := filepath.Join(configPath, cfg.Axiom.AxiomKeyPath)
-	keyByteAxm, err := os.ReadFile(keyPathAxm)
-	if err != nil {
+	if err := c.verifyChainID(); err != nil {
 		return err
 	}
-	private := strings.TrimSpace(string(keyByteAxm))
-	privateKeyBytes, err := hex.DecodeString(private)
-	if err != nil {
-		return fmt.Errorf("Error decoding private key hex:", err)
+	if err := c.initTokens(); err != nil {
+		return err
 	}
-	privateKey, err := crypto.ToECDSA(privateKeyBytes)
+
+	// 构建auth_axm，私钥无效时只停用该网络的领取，strict_key 为 true 时直接退出
+	privateKey, err := loadFundingKey(cfg, configPath)
 	if err != nil {
-		return fmt.Errorf("Error converting to ECDSA private key:", err)
+		if cfg.Axiom.StrictKey {
+			return err
+		}
+		c.logger.Errorf("funding key of %s is invalid, claims on %s are disabled until a valid key is rotated in: %v", cfg.Axiom.TestNetName, cfg.Axiom.TestNetName, err)
+	} else {
+		c.axiomPrivateKey = privateKey
+		authAxm := bind.NewKeyedTransactor(privateKey)
+		c.axiomAuth = authAxm
+		atomic.StoreInt32(&c.fundingReady, 1)
+		c.fundingAddress.Store(authAxm.From.Hex())
+		c.logger.Infof("funding address: %s", authAxm.From.Hex())
 	}
-	c.axiomPrivateKey = privateKey
-	authAxm := bind.NewKeyedTransactor(privateKey)
-	c.axiomAuth = authAxm
 
 	// 初始化leveldb
 	leveldb, err := leveldb.New(filepath.Join(configPath, "store"), nil)
@@ -280,11 +734,117 @@ func (c *Client) Initialize(cfg *repo.Config, configPath string) error {
 		return fmt.Errorf("create tm-leveldb: %w", err)
 	}
 	c.ldb = leveldb
-	c.logger = loggers.Logger(loggers.ApiServer)
+	if err := c.initClaimTokenKey(configPath); err != nil {
+		return err
+	}
+	switch cfg.Scrapper.UnavailablePolicy {
+	case repo.VerifierPolicyFail, repo.VerifierPolicyDegraded:
+		c.logger.Infof("tweet verifier unavailable policy: %s", cfg.Scrapper.UnavailablePolicy)
+	default:
+		return fmt.Errorf("unknown tweet verifier unavailable policy %q, expect %s or %s", cfg.Scrapper.UnavailablePolicy, repo.VerifierPolicyFail, repo.VerifierPolicyDegraded)
+	}
+	if err := c.checkAmountUnits(); err != nil {
+		return err
+	}
+	switch cfg.Axiom.NonceGapPolicy {
+	case repo.NonceGapPolicyReuse, repo.NonceGapPolicyRefuse:
+		c.logger.Infof("nonce gap policy: %s", cfg.Axiom.NonceGapPolicy)
+	default:
+		return fmt.Errorf("unknown nonce gap policy %q, expect %s or %s", cfg.Axiom.NonceGapPolicy, repo.NonceGapPolicyReuse, repo.NonceGapPolicyRefuse)
+	}
+	if cfg.Axiom.StuckTxThreshold > 0 && cfg.Axiom.StuckTxCheckInterval <= 0 {
+		return fmt.Errorf("stuck_tx_check_interval must be positive when stuck_tx_threshold is set, got %s", cfg.Axiom.StuckTxCheckInterval.String())
+	}
+	if cfg.Axiom.DroppedTxTimeout > 0 && cfg.Axiom.DroppedTxCheckInterval <= 0 {
+		return fmt.Errorf("dropped_tx_check_interval must be positive when dropped_tx_timeout is set, got %s", cfg.Axiom.DroppedTxCheckInterval.String())
+	}
+	if err := c.initMaintenance(); err != nil {
+		return err
+	}
+	if err := c.initEvent(); err != nil {
+		return err
+	}
+	if err := c.initReferralCodes(); err != nil {
+		return err
+	}
+	if err := c.initSanctionChecker(configPath); err != nil {
+		return err
+	}
+	if c.amountRules, err = compileAmountRules(cfg.Axiom.AmountRules); err != nil {
+		return err
+	}
+	c.statsCache = make(map[string]*statsCache)
+	c.queue = make(chan *Ticket, cfg.Queue.Size)
+	c.tickets = make(map[string]*Ticket)
+	c.tweetCache = make(map[string]time.Time)
+	c.unsavedClaims = make(map[string]*unsavedClaim)
+	c.stuckTxs = make(map[uint64]*stuckTx)
+	c.latency = newClaimLatency(cfg.Network.LatencyBuckets, cfg.Network.ClaimSLO.ToDuration())
+	if cfg.Axiom.StuckTxThreshold > 0 {
+		c.StartGasBumper()
+	}
+	if cfg.Email.Host != "" {
+		email := cfg.Email
+		email.Timeout = repo.Duration(c.outboundTimeout(email.Timeout))
+		c.mailer = &smtpMailer{cfg: email}
+	}
+	if cfg.Axiom.RecordRetryInterval > 0 {
+		c.StartUnsavedClaimWriter()
+	}
+	if cfg.Axiom.DroppedTxTimeout > 0 {
+		c.StartConfirmTracker()
+	}
+	if cfg.Axiom.BalanceCacheInterval > 0 {
+		c.StartBalanceRefresher()
+	}
+	return nil
+}
+
+// verifyChainID 校验 rpc 节点的链 id 与配置一致，防止 rpc 地址配错后向其他链发放代币，不一致时停止该网络的领取
+func (c *Client) verifyChainID() error {
+	if c.Config.Axiom.ChainID == 0 {
+		c.logger.Warnf("chain id of %s is not configured, skip chain id verification", c.Config.Axiom.TestNetName)
+		return nil
+	}
+	chainId, err := c.axiomClient.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("query chain id of %s: %w", c.Config.Axiom.TestNetName, err)
+	}
+	if chainId.Uint64() != c.Config.Axiom.ChainID {
+		c.logger.Errorf("chain id mismatch, claims on %s are disabled: expect %d but %s returns %s", c.Config.Axiom.TestNetName, c.Config.Axiom.ChainID, c.Config.Axiom.AxiomAddr, chainId)
+		atomic.StoreInt32(&c.chainMismatch, 1)
+	}
 	return nil
 }
 
+// checkChain rpc 节点的链 id 与配置不一致时拒绝领取
+func (c *Client) checkChain() (int, error) {
+	if atomic.LoadInt32(&c.chainMismatch) == 1 {
+		return global.ChainDisabledCode, fmt.Errorf(global.ChainDisabledMsg)
+	}
+	return global.SUCCESS, nil
+}
+
+// requestLogger 领取流程中的日志，带有 ctx 中请求的关联 ID
+func (c *Client) requestLogger(ctx context.Context) logrus.FieldLogger {
+	if id := RequestFrom(ctx).ID; id != "" {
+		return c.logger.WithField("request_id", id)
+	}
+	return c.logger
+}
+
+// Audit 写入审计日志，客户端 IP 从 ctx 中的请求信息获取
+func (c *Client) Audit(ctx context.Context, event *audit.Event) {
+	if event.IP == "" {
+		event.IP = RequestFrom(ctx).IP
+	}
+	if err := c.auditLogger.Write(event); err != nil {
+		c.logger.Errorf("write audit event %s failed: %v", event.Type, err)
+	}
+}
+
 func (c *Client) Close() {
+	c.cancel()
 	c.ldb.Close()
 	c.axiomClient.Close()
 }
@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func putAddressData(t *testing.T, c *Client, key []byte, data *AddressData) []byte {
+	t.Helper()
+	value, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ldb.Put(key, value)
+	return value
+}
+
+func storedAddressData(t *testing.T, c *Client, key []byte) *AddressData {
+	t.Helper()
+	data := &AddressData{}
+	if err := json.Unmarshal(c.ldb.Get(key), data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// 预检只在内存中修正未来的领取记录，不修改存储
+func TestPreCheckKeepsFutureRecord(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.MaxClockSkew = repo.Duration(5 * time.Minute)
+	})
+	key := c.construAddressKey(c.Config.Axiom.TestNetName, global.NativeToken, testRecipient)
+	sendTxTime := time.Now().Unix() + 3600
+	putAddressData(t, c, key, &AddressData{SendTxTime: sendTxTime, TxHash: "0x01"})
+
+	if code, _ := c.PreCheck(context.Background(), c.Config.Axiom.TestNetName, testRecipient); code != global.ReqWithinDayCode {
+		t.Fatalf("expect %d, got %d", global.ReqWithinDayCode, code)
+	}
+	if got := storedAddressData(t, c, key).SendTxTime; got != sendTxTime {
+		t.Fatalf("preCheck should not modify the record, got %d want %d", got, sendTxTime)
+	}
+}
+
+// 领取时持有地址预锁，将未来的领取记录修正为当前时间并写回
+func TestCheckLimitClampsFutureRecord(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.MaxClockSkew = repo.Duration(5 * time.Minute)
+	})
+	net := c.Config.Axiom.TestNetName
+	key := c.construAddressKey(net, global.NativeToken, testRecipient)
+	now := time.Now().Unix()
+	putAddressData(t, c, key, &AddressData{SendTxTime: now + 3600, TxHash: "0x01"})
+
+	err := c.checkLimit(context.Background(), net, global.NativeToken, testRecipient, c.ldb)
+	if err == nil || err.Error() != global.ReqWithinDayMsg {
+		t.Fatalf("expect %q, got %v", global.ReqWithinDayMsg, err)
+	}
+	data := storedAddressData(t, c, key)
+	if data.SendTxTime < now || data.SendTxTime > time.Now().Unix() {
+		t.Fatalf("future record should be clamped to now, got %d (now %d)", data.SendTxTime, now)
+	}
+	if data.TxHash != "0x01" {
+		t.Fatalf("clamping should keep the rest of the record, got tx %q", data.TxHash)
+	}
+}
+
+func TestCheckClaimIntervalKeepsRecordWithinSkew(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.MaxClockSkew = repo.Duration(5 * time.Minute)
+	})
+	key := c.construAddressKey(c.Config.Axiom.TestNetName, global.NativeToken, testRecipient)
+	sendTxTime := time.Now().Unix() + 60
+	value := putAddressData(t, c, key, &AddressData{SendTxTime: sendTxTime})
+	if got := c.repairFutureClaim(context.Background(), key, testRecipient, value); string(got) != string(value) {
+		t.Fatalf("record within the skew should not be repaired, got %s", got)
+	}
+
+	err := c.checkClaimInterval(context.Background(), testRecipient, value)
+	if err == nil || err.Error() != global.ReqWithinDayMsg {
+		t.Fatalf("expect %q, got %v", global.ReqWithinDayMsg, err)
+	}
+	if got := storedAddressData(t, c, key).SendTxTime; got != sendTxTime {
+		t.Fatalf("record within the skew should be kept, got %d want %d", got, sendTxTime)
+	}
+}
+
+func TestCheckClaimIntervalAllowsAfterOneDay(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	key := c.construAddressKey(c.Config.Axiom.TestNetName, global.NativeToken, testRecipient)
+	value := putAddressData(t, c, key, &AddressData{SendTxTime: time.Now().Add(-25 * time.Hour).Unix()})
+
+	if err := c.checkClaimInterval(context.Background(), testRecipient, value); err != nil {
+		t.Fatalf("claim after one day should pass, got %v", err)
+	}
+}
+
+func TestSendTraRejectsSecondClaimWithinDay(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	ctx := context.Background()
+
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("first claim failed: %d %v", code, err)
+	}
+	if _, code, err := claim(c, ctx, testRecipient, 1); code != global.ReqWithinDayCode {
+		t.Fatalf("second claim should be rejected with %d, got %d %v", global.ReqWithinDayCode, code, err)
+	}
+}
+
+func TestChainIDMismatchDisablesNet(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.ChainID = node.ChainID() + 1
+	})
+	ctx := context.Background()
+
+	if _, code, err := claim(c, ctx, testRecipient, 1); code != global.ChainDisabledCode {
+		t.Fatalf("claim should be refused with %d, got %d %v", global.ChainDisabledCode, code, err)
+	}
+	if code, _ := c.PreCheck(ctx, c.Config.Axiom.TestNetName, testRecipient); code != global.ChainDisabledCode {
+		t.Fatalf("preCheck should report %d, got %d", global.ChainDisabledCode, code)
+	}
+	if err := c.SelfTest(); err == nil {
+		t.Fatal("self-test should fail on a chain id mismatch")
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent to the wrong chain, got %d", len(sent))
+	}
+}
+
+func TestChainIDVerificationSkippedWithoutConfig(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.ChainID = 0
+	})
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+}
+
+func TestTieredAmountByPriorClaims(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.ClaimTierMultipliers = []float64{1, 0.5, 0.25}
+	})
+	net := c.Config.Axiom.TestNetName
+
+	// 第 4 次及之后的领取使用最后一档
+	for i, want := range []float64{100, 50, 25, 25} {
+		if got := c.tieredAmount(net, testRecipient, 100); got != want {
+			t.Fatalf("after %d claims expect %v, got %v", i, want, got)
+		}
+		if err := c.putClaimRecord(net, global.NativeToken, testRecipient, &AddressData{SendTxTime: int64(i + 1), TxHash: "0x01"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTieredAmountDisabledWithoutTiers(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	net := c.Config.Axiom.TestNetName
+	if err := c.putClaimRecord(net, global.NativeToken, testRecipient, &AddressData{SendTxTime: 1, TxHash: "0x01"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.tieredAmount(net, testRecipient, 100); got != 100 {
+		t.Fatalf("amount should not be scaled without tiers, got %v", got)
+	}
+}
+
+// 地址被其他请求锁定时返回 ErrAddressLocked，且不释放其他请求持有的锁
+func TestSendTraKeepsLockHeldByAnotherClaim(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	if code, err := c.ReserveClaim(ctx, net, testRecipient); err != nil {
+		t.Fatalf("reserve failed: %d %v", code, err)
+	}
+	for i := 0; i < 2; i++ {
+		_, code, err := claim(c, ctx, testRecipient, 1)
+		if !errors.Is(err, ErrAddressLocked) || code != global.AddrPreLockErrCode {
+			t.Fatalf("attempt %d: expect the address to stay locked, got %d %v", i, code, err)
+		}
+	}
+	if code, err := c.PreCheck(ctx, net, testRecipient); !errors.Is(err, ErrAddressLocked) || code != global.AddrPreLockErrCode {
+		t.Fatalf("preCheck should report the lock, got %d %v", code, err)
+	}
+}
+
+// 测试地址只跳过领取间隔，其他限制照常生效
+func TestTesterSkipsClaimInterval(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.TesterAllowlist = []string{"0x" + strings.ToUpper(testRecipient[2:])}
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+			t.Fatalf("tester claim %d failed: %d %v", i, code, err)
+		}
+	}
+	if _, err := c.SetAddressNote(c.Config.Axiom.TestNetName, testRecipient, "", FlagBlocked); err != nil {
+		t.Fatal(err)
+	}
+	if _, code, _ := claim(c, ctx, testRecipient, 1); code != global.AddrBlockedCode {
+		t.Fatalf("blocked tester should be refused with %d, got %d", global.AddrBlockedCode, code)
+	}
+}
+
+// 所有领取接口共用同一把地址预锁
+func TestPreLockSharedAcrossEndpoints(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	if code, err := c.ReserveClaim(ctx, net, testRecipient); err != nil {
+		t.Fatalf("reserve failed: %d %v", code, err)
+	}
+	if _, code, err := c.AdminClaim(ctx, net, testRecipient, 1, ""); !errors.Is(err, ErrAddressLocked) {
+		t.Fatalf("admin claim should see the lock, got %d %v", code, err)
+	}
+	if _, code, err := c.EnqueueClaim(ctx, net, testRecipient, 1, 0, "", ""); !errors.Is(err, ErrAddressLocked) {
+		t.Fatalf("queued claim should see the lock, got %d %v", code, err)
+	}
+}
+
+func TestStalePreLockExpires(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	key := c.construPreLockAddressKey(net, global.NativeToken, testRecipient)
+
+	// 进程异常退出遗留的过期预锁不再阻止领取
+	c.ldb.Put(key, preLockValue(-time.Second))
+	if code, err := c.ReserveClaim(ctx, net, testRecipient); err != nil {
+		t.Fatalf("expired lock should be replaced, got %d %v", code, err)
+	}
+	if !isPreLocked(c.ldb.Get(key)) {
+		t.Fatal("address should be locked again")
+	}
+}
+
+func TestIsPreLocked(t *testing.T) {
+	for _, tc := range []struct {
+		value []byte
+		want  bool
+	}{
+		{nil, false},
+		{preLockValue(time.Minute), true},
+		{preLockValue(-time.Minute), false},
+		// 无法解析的旧格式记录视为仍被锁定
+		{[]byte("preLock"), true},
+	} {
+		if got := isPreLocked(tc.value); got != tc.want {
+			t.Fatalf("%q: expect %v, got %v", tc.value, tc.want, got)
+		}
+	}
+}
+
+func TestPreLockTTLFollowsWriteTimeout(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.WriteTimeout = repo.Duration(30 * time.Second)
+	})
+	if got, want := c.preLockTTL(), 30*time.Second+preLockMargin; got != want {
+		t.Fatalf("expect %v, got %v", want, got)
+	}
+}
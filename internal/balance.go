@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// faucetBalance 查询水龙头合约余额。配置了 balance_cache_interval 时非 fresh 读取直接使用缓存，
+// 缓存由后台定时刷新，交易确认后按发放数量扣减，发送失败后失效；fresh 读取总是查询链上余额并更新缓存
+func (c *Client) faucetBalance(fresh bool) (*big.Int, error) {
+	if c.Config.Axiom.BalanceCacheInterval > 0 && !fresh {
+		c.balanceLock.Lock()
+		cached := c.cachedBalance
+		c.balanceLock.Unlock()
+		if cached != nil {
+			return new(big.Int).Set(cached), nil
+		}
+	}
+	return c.refreshFaucetBalance()
+}
+
+func (c *Client) refreshFaucetBalance() (*big.Int, error) {
+	balance, err := c.axiomClient.BalanceAt(context.Background(), common.HexToAddress(c.Config.Axiom.FaucetAddr), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.balanceLock.Lock()
+	c.cachedBalance = new(big.Int).Set(balance)
+	c.balanceLock.Unlock()
+	return balance, nil
+}
+
+// CachedBalance 最近一次查询或刷新得到的水龙头余额（ether），尚未查询或缓存已失效时 ok 为 false
+func (c *Client) CachedBalance() (float64, bool) {
+	c.balanceLock.Lock()
+	defer c.balanceLock.Unlock()
+	if c.cachedBalance == nil {
+		return 0, false
+	}
+	return etherBigIntToFloat(c.cachedBalance), true
+}
+
+// reserveFunds 交易发出后按交易哈希预留发放数量，在交易确认、失败或被丢弃前不计入可用余额
+func (c *Client) reserveFunds(txHash string, value *big.Int) {
+	c.balanceLock.Lock()
+	defer c.balanceLock.Unlock()
+	if c.reservations == nil {
+		c.reservations = make(map[string]*big.Int)
+	}
+	c.reservations[txHash] = new(big.Int).Set(value)
+}
+
+// releaseFunds 释放交易预留的发放数量，已释放或未预留时忽略；spent 为 true 时交易已确认，同时扣减缓存余额，不等待下一次刷新
+func (c *Client) releaseFunds(txHash string, spent bool) {
+	c.balanceLock.Lock()
+	defer c.balanceLock.Unlock()
+	value, ok := c.reservations[txHash]
+	if !ok {
+		return
+	}
+	delete(c.reservations, txHash)
+	if spent && c.cachedBalance != nil {
+		c.cachedBalance = new(big.Int).Sub(c.cachedBalance, value)
+	}
+}
+
+// reservedFunds 已发出、尚未确认的领取数量之和
+func (c *Client) reservedFunds() *big.Int {
+	c.balanceLock.Lock()
+	defer c.balanceLock.Unlock()
+	reserved := new(big.Int)
+	for _, value := range c.reservations {
+		reserved.Add(reserved, value)
+	}
+	return reserved
+}
+
+// invalidateFaucetBalance 发送失败时缓存余额可能已不准确，下次读取重新查询
+func (c *Client) invalidateFaucetBalance() {
+	c.balanceLock.Lock()
+	defer c.balanceLock.Unlock()
+	c.cachedBalance = nil
+}
+
+// StartBalanceRefresher 按 balance_cache_interval 定时刷新缓存余额
+func (c *Client) StartBalanceRefresher() {
+	interval := c.Config.Axiom.BalanceCacheInterval.ToDuration()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := c.refreshFaucetBalance(); err != nil {
+					c.logger.Warnf("refresh faucet balance: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// balanceTier 按水龙头余额选择发放档位，余额不低于 above 的最高一档生效，余额低于所有档位时使用最低一档，未配置时返回 nil
+func balanceTier(tiers []repo.BalanceTier, balance float64) *repo.BalanceTier {
+	if len(tiers) == 0 {
+		return nil
+	}
+	sorted := make([]repo.BalanceTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Above > sorted[j].Above })
+	for i := range sorted {
+		if balance >= sorted[i].Above {
+			return &sorted[i]
+		}
+	}
+	return &sorted[len(sorted)-1]
+}
+
+// currentBalanceTier 使用缓存余额计算当前生效的档位
+func (c *Client) currentBalanceTier() (*repo.BalanceTier, error) {
+	if len(c.Config.Axiom.BalanceTiers) == 0 {
+		return nil, nil
+	}
+	balance, err := c.faucetBalance(false)
+	if err != nil {
+		return nil, err
+	}
+	return balanceTier(c.Config.Axiom.BalanceTiers, etherBigIntToFloat(balance)), nil
+}
+
+// balanceTierAmount 按当前余额档位调整发放数量
+func (c *Client) balanceTierAmount(amount float64) (float64, error) {
+	tier, err := c.currentBalanceTier()
+	if err != nil || tier == nil {
+		return amount, err
+	}
+	return amount * tier.Multiplier, nil
+}
@@ -0,0 +1,242 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newBalanceCacheClient(t *testing.T, node *testutil.Node) *Client {
+	t.Helper()
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.BalanceCacheInterval = repo.Duration(time.Hour)
+	})
+}
+
+func TestFaucetBalanceUsesCache(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newBalanceCacheClient(t, node)
+	node.SetBalance(testFaucetAddress, 100)
+
+	if _, err := c.faucetBalance(false); err != nil {
+		t.Fatal(err)
+	}
+	node.SetBalance(testFaucetAddress, 50)
+	balance, err := c.faucetBalance(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := etherBigIntToFloat(balance); got != 100 {
+		t.Fatalf("cached read should keep 100, got %v", got)
+	}
+	// fresh 读取查询链上余额并更新缓存
+	if balance, err = c.faucetBalance(true); err != nil || etherBigIntToFloat(balance) != 50 {
+		t.Fatalf("fresh read should return 50, got %v %v", balance, err)
+	}
+	if cached, ok := c.CachedBalance(); !ok || cached != 50 {
+		t.Fatalf("cache should be refreshed to 50, got %v %v", cached, ok)
+	}
+}
+
+func TestFaucetBalanceWithoutCache(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetBalance(testFaucetAddress, 100)
+
+	if _, err := c.faucetBalance(false); err != nil {
+		t.Fatal(err)
+	}
+	node.SetBalance(testFaucetAddress, 50)
+	if balance, err := c.faucetBalance(false); err != nil || etherBigIntToFloat(balance) != 50 {
+		t.Fatalf("every read should query the chain without cache, got %v %v", balance, err)
+	}
+}
+
+// 领取确认后扣减缓存余额，发送失败后缓存失效
+func TestClaimUpdatesCachedBalance(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newBalanceCacheClient(t, node)
+	ctx := context.Background()
+	node.SetBalance(testFaucetAddress, 100)
+	if _, err := c.faucetBalance(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, code, err := claim(c, ctx, testRecipient, 10); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if cached, _ := c.CachedBalance(); cached != 90 {
+		t.Fatalf("expect cached balance 90 after the claim, got %v", cached)
+	}
+
+	node.Handle("eth_sendRawTransaction", func([]json.RawMessage) (any, error) {
+		return nil, &testutil.RPCError{Code: -32000, Message: "nonce too low"}
+	})
+	if _, _, err := claim(c, ctx, "0x2222222222222222222222222222222222222222", 10); err == nil {
+		t.Fatal("claim should fail")
+	}
+	if _, ok := c.CachedBalance(); ok {
+		t.Fatal("cache should be invalidated after a failed send")
+	}
+}
+
+func TestBalanceTier(t *testing.T) {
+	// 配置顺序不影响选择
+	tiers := []repo.BalanceTier{{Above: 100, Multiplier: 0.5}, {Above: 1000, Multiplier: 1}, {Above: 10, Multiplier: 0.1}}
+	cases := []struct {
+		balance    float64
+		multiplier float64
+	}{
+		{5000, 1},
+		{1000, 1},
+		{999, 0.5},
+		{100, 0.5},
+		{50, 0.1},
+		{1, 0.1},
+	}
+	for _, tc := range cases {
+		if tier := balanceTier(tiers, tc.balance); tier == nil || tier.Multiplier != tc.multiplier {
+			t.Fatalf("balance %v: expect multiplier %v, got %+v", tc.balance, tc.multiplier, tier)
+		}
+	}
+	if tier := balanceTier(nil, 1); tier != nil {
+		t.Fatalf("no tiers should give nil, got %+v", tier)
+	}
+	if tiers[0].Above != 100 {
+		t.Fatal("configured tiers should not be reordered")
+	}
+}
+
+func TestClaimStepsDownByBalanceTier(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.BalanceTiers = []repo.BalanceTier{{Above: 1e5, Multiplier: 1}, {Above: 0, Multiplier: 0.5}}
+	})
+	node.SetBalance(testFaucetAddress, 1000)
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 2); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	sent := node.Sent()
+	if _, amount := dripValue(t, sent[0]); amount != 1 {
+		t.Fatalf("low balance should halve the payout, got %v", amount)
+	}
+	status, err := c.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.BalanceTier == nil || status.BalanceTier.Multiplier != 0.5 {
+		t.Fatalf("status should report the active tier, got %+v", status.BalanceTier)
+	}
+}
+
+func TestCachedBalance(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if _, ok := c.CachedBalance(); ok {
+		t.Fatal("balance should not be cached before the first query")
+	}
+	if _, err := c.faucetBalance(true); err != nil {
+		t.Fatal(err)
+	}
+	if balance, ok := c.CachedBalance(); !ok || balance != 1e6 {
+		t.Fatalf("expect cached balance 1e6, got %v %v", balance, ok)
+	}
+}
+
+func TestReleaseFunds(t *testing.T) {
+	c := &Client{cachedBalance: floatToEtherBigInt(100)}
+	c.reserveFunds("0xa", floatToEtherBigInt(1))
+	c.reserveFunds("0xb", floatToEtherBigInt(2))
+	if reserved := etherBigIntToFloat(c.reservedFunds()); reserved != 3 {
+		t.Fatalf("expect 3 reserved, got %v", reserved)
+	}
+
+	// 已确认的交易同时扣减缓存余额，重复释放或未预留的交易被忽略
+	c.releaseFunds("0xa", true)
+	c.releaseFunds("0xa", true)
+	c.releaseFunds("0xc", true)
+	if reserved := etherBigIntToFloat(c.reservedFunds()); reserved != 2 {
+		t.Fatalf("expect 2 reserved, got %v", reserved)
+	}
+	if balance, _ := c.CachedBalance(); balance != 99 {
+		t.Fatalf("expect cached balance 99, got %v", balance)
+	}
+
+	// 失败或被丢弃的交易不扣减缓存余额
+	c.releaseFunds("0xb", false)
+	if reserved := c.reservedFunds(); reserved.Sign() != 0 {
+		t.Fatalf("expect nothing reserved, got %v", reserved)
+	}
+	if balance, _ := c.CachedBalance(); balance != 99 {
+		t.Fatalf("expect cached balance 99, got %v", balance)
+	}
+}
+
+// 未确认的领取持续预留发放数量，查到最终回执后释放
+func TestUnconfirmedClaimKeepsReservation(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newConfirmClient(t, node)
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	status, err := c.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ReservedBalance != 1 || status.AvailableBalance != status.FaucetBalance-c.Config.Axiom.GasReserve-1 {
+		t.Fatalf("expect the unconfirmed claim to be reserved, got %+v", status)
+	}
+
+	node.Mine(common.HexToHash(txHash), 1)
+	c.checkPendingTxs()
+	if reserved := c.reservedFunds(); reserved.Sign() != 0 {
+		t.Fatalf("reservation should be released after confirmation, got %v", reserved)
+	}
+}
+
+func TestDroppedClaimReleasesReservation(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newConfirmClient(t, node)
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	node.Drop(common.HexToHash(txHash))
+	c.checkPendingTxs()
+	if reserved := c.reservedFunds(); reserved.Sign() != 0 {
+		t.Fatalf("reservation should be released after the tx is dropped, got %v", reserved)
+	}
+}
+
+// 开启 reserve_in_flight 时在途领取占用可用余额，余额只够一次发放时拒绝第二次领取
+func TestReserveInFlight(t *testing.T) {
+	for _, reserveInFlight := range []bool{true, false} {
+		node := testutil.NewNode(t)
+		node.SetAutoReceipt(false)
+		c := newTestClient(t, node, func(cfg *repo.Config) {
+			cfg.Axiom.ReserveInFlight = reserveInFlight
+		})
+		node.SetBalance(testFaucetAddress, c.Config.Axiom.GasReserve+1.5)
+
+		if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+			t.Fatalf("claim failed: %d %v", code, err)
+		}
+		_, code, err := claim(c, context.Background(), "0x2222222222222222222222222222222222222222", 1)
+		if reserveInFlight && code != global.ReserveErrCode {
+			t.Fatalf("second claim should be refused with %d while the first is in flight, got %d %v", global.ReserveErrCode, code, err)
+		}
+		if !reserveInFlight && err != nil {
+			t.Fatalf("second claim should pass without reserve_in_flight, got %d %v", code, err)
+		}
+	}
+}
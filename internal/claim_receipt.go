@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/persist"
+)
+
+const (
+	ReceiptPending   = "pending"
+	ReceiptConfirmed = "confirmed"
+	ReceiptReverted  = "reverted"
+	ReceiptDropped   = "dropped"
+)
+
+// Receipt 领取交易的回执，通过由交易哈希派生的 id 查询。交易确认后记录最终状态与所在区块，
+// 查询时不再请求节点；发出后未能及时确认的交易记为 pending，查询时再确认并保存最终状态
+type Receipt struct {
+	ID          string  `json:"id"`
+	Net         string  `json:"net"`
+	Address     string  `json:"address"`
+	Token       string  `json:"token"`
+	Amount      float64 `json:"amount"`
+	TxHash      string  `json:"txHash"`
+	Time        int64   `json:"time"`
+	Status      string  `json:"status"`
+	BlockNumber uint64  `json:"blockNumber,omitempty"`
+}
+
+// TxStatus 回执已记录交易上链结果时转换为交易状态，pending 与 dropped 的回执返回 false
+func (r *Receipt) TxStatus() (*TxStatus, bool) {
+	switch r.Status {
+	case ReceiptConfirmed:
+		return &TxStatus{TxHash: r.TxHash, Status: TxMined, BlockNumber: r.BlockNumber}, true
+	case ReceiptReverted:
+		return &TxStatus{TxHash: r.TxHash, Status: TxReverted, BlockNumber: r.BlockNumber}, true
+	}
+	return nil, false
+}
+
+// ReceiptID 由交易哈希派生回执 id，同一笔交易的 id 保持不变
+func ReceiptID(txHash string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(txHash)))
+	return hex.EncodeToString(sum[:12])
+}
+
+// putReceipt 按领取记录写入回执，记录未带状态时视为已确认
+func (c *Client) putReceipt(net string, typ string, address string, data *AddressData) error {
+	status := data.Status
+	if status == "" {
+		status = ReceiptConfirmed
+	}
+	receipt := &Receipt{
+		ID:          ReceiptID(data.TxHash),
+		Net:         net,
+		Address:     address,
+		Token:       typ,
+		Amount:      data.Amount,
+		TxHash:      data.TxHash,
+		Time:        data.SendTxTime,
+		Status:      status,
+		BlockNumber: data.BlockNumber,
+	}
+	return c.writeReceipt(receipt)
+}
+
+// putUnconfirmedReceipt 交易发出后未能及时查到成功回执时记录回执，已失败的交易记为 reverted 并释放预留的发放数量，其余记为 pending，
+// 返回记录的状态
+func (c *Client) putUnconfirmedReceipt(ctx context.Context, net string, typ string, address string, tx *types.Transaction, amount float64) string {
+	receipt := &Receipt{
+		ID:      ReceiptID(tx.Hash().Hex()),
+		Net:     net,
+		Address: address,
+		Token:   typ,
+		Amount:  amount,
+		TxHash:  tx.Hash().Hex(),
+		Time:    time.Now().Unix(),
+		Status:  ReceiptPending,
+	}
+	if status, err := c.TxStatus(context.Background(), receipt.TxHash); err == nil && status.Status == TxReverted {
+		receipt.Status = ReceiptReverted
+		receipt.BlockNumber = status.BlockNumber
+		c.releaseFunds(receipt.TxHash, false)
+	}
+	if err := c.writeReceipt(receipt); err != nil {
+		c.requestLogger(ctx).Errorf("put receipt of %s failed: %v", receipt.TxHash, err)
+	}
+	return receipt.Status
+}
+
+// finishReceipt 按交易回执记录 pending 回执的最终状态并释放预留的发放数量，txHash 为领取时发出的原交易哈希，回执不存在或已是最终状态时忽略
+func (c *Client) finishReceipt(txHash string, r *types.Receipt) {
+	receipt, ok := c.loadReceipt(ReceiptID(txHash))
+	if !ok || receipt.Status != ReceiptPending {
+		return
+	}
+	receipt.Status = ReceiptConfirmed
+	if r.Status == types.ReceiptStatusFailed {
+		receipt.Status = ReceiptReverted
+	}
+	receipt.BlockNumber = receiptBlock(r)
+	if err := c.writeReceipt(receipt); err != nil {
+		c.logger.Errorf("update receipt of %s failed: %v", txHash, err)
+	}
+	c.releaseFunds(txHash, receipt.Status == ReceiptConfirmed)
+	c.finishClaimRecord(receipt)
+}
+
+func (c *Client) writeReceipt(receipt *Receipt) error {
+	value, err := json.Marshal(receipt)
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+	c.ldb.Put(c.construReceiptKey(receipt.ID), value)
+	return nil
+}
+
+// GetReceipt 按 id 查询领取回执，pending 的回执查询节点确认最终状态，已上链时保存后返回
+func (c *Client) GetReceipt(id string) (*Receipt, bool) {
+	receipt, ok := c.loadReceipt(id)
+	if !ok || receipt.Status != ReceiptPending {
+		return receipt, ok
+	}
+	r, err := c.axiomClient.TransactionReceipt(context.Background(), common.HexToHash(receipt.TxHash))
+	if err != nil {
+		if !errors.Is(err, ethereum.NotFound) {
+			c.logger.Warnf("query receipt of %s failed: %v", receipt.TxHash, err)
+		}
+		return receipt, true
+	}
+	c.finishReceipt(receipt.TxHash, r)
+	return c.loadReceipt(id)
+}
+
+func (c *Client) loadReceipt(id string) (*Receipt, bool) {
+	value := c.ldb.Get(c.construReceiptKey(strings.ToLower(id)))
+	if value == nil {
+		return nil, false
+	}
+	receipt := &Receipt{}
+	if err := json.Unmarshal(value, receipt); err != nil {
+		c.logger.Errorf("unmarshal receipt %s failed: %v", id, err)
+		return nil, false
+	}
+	return receipt, true
+}
+
+// setReceiptStatus 更新回执状态，回执不存在时忽略
+func (c *Client) setReceiptStatus(txHash string, status string) {
+	receipt, ok := c.loadReceipt(ReceiptID(txHash))
+	if !ok {
+		return
+	}
+	receipt.Status = status
+	if err := c.writeReceipt(receipt); err != nil {
+		c.logger.Errorf("update receipt %s failed: %v", receipt.ID, err)
+	}
+}
+
+func (c *Client) construReceiptKey(id string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("receipt-")
+	buffer.WriteString(id)
+	return persist.CompositeKey(c.Config.Axiom.TestNetName, buffer)
+}
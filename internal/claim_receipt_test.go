@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestReceiptID(t *testing.T) {
+	txHash := "0x" + strings.Repeat("ab", 32)
+	id := ReceiptID(txHash)
+	if len(id) != 24 || id != ReceiptID(strings.ToUpper(txHash)) {
+		t.Fatalf("receipt id should be stable and case insensitive, got %s", id)
+	}
+	if id == ReceiptID("0x"+strings.Repeat("cd", 32)) {
+		t.Fatal("different txs should have different receipt ids")
+	}
+}
+
+func TestClaimStoresReceipt(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	receipt, ok := c.GetReceipt(ReceiptID(txHash))
+	if !ok {
+		t.Fatal("receipt should be stored after the claim")
+	}
+	if receipt.TxHash != txHash || receipt.Address != testRecipient || receipt.Amount != 1 || receipt.Token != global.NativeToken || receipt.Net != c.Config.Axiom.TestNetName {
+		t.Fatalf("unexpected receipt %+v", receipt)
+	}
+	// id 大小写不影响查询
+	if _, ok := c.GetReceipt(strings.ToUpper(receipt.ID)); !ok {
+		t.Fatal("receipt lookup should be case insensitive")
+	}
+
+	c.setReceiptStatus(txHash, ReceiptDropped)
+	if receipt, _ := c.GetReceipt(receipt.ID); receipt.Status != ReceiptDropped {
+		t.Fatalf("expect status %s, got %s", ReceiptDropped, receipt.Status)
+	}
+}
+
+func TestGetReceiptUnknownID(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if _, ok := c.GetReceipt("missing"); ok {
+		t.Fatal("unknown receipt should not be found")
+	}
+	// 不存在的回执不写入
+	c.setReceiptStatus("0x"+strings.Repeat("ab", 32), ReceiptDropped)
+	if _, ok := c.GetReceipt(ReceiptID("0x" + strings.Repeat("ab", 32))); ok {
+		t.Fatal("status update should not create a receipt")
+	}
+}
+
+func TestReceiptTxStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		want   string
+	}{
+		{ReceiptConfirmed, TxMined},
+		{ReceiptReverted, TxReverted},
+		{ReceiptPending, ""},
+		{ReceiptDropped, ""},
+	} {
+		status, ok := (&Receipt{TxHash: "0xabc", Status: tc.status, BlockNumber: 7}).TxStatus()
+		if tc.want == "" {
+			if ok {
+				t.Fatalf("%s receipt should not have a final tx status, got %+v", tc.status, status)
+			}
+			continue
+		}
+		if !ok || status.Status != tc.want || status.BlockNumber != 7 || status.TxHash != "0xabc" {
+			t.Fatalf("%s receipt: expect %s at block 7, got %+v", tc.status, tc.want, status)
+		}
+	}
+}
+
+func TestConfirmedClaimRecordsBlockNumber(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	receipt, ok := c.GetReceipt(ReceiptID(txHash))
+	if !ok || receipt.Status != ReceiptConfirmed || receipt.BlockNumber == 0 {
+		t.Fatalf("expect a confirmed receipt with block number, got %+v", receipt)
+	}
+	data := c.LastClaim(c.Config.Axiom.TestNetName, testRecipient)
+	if data == nil || data.Status != ReceiptConfirmed || data.BlockNumber != receipt.BlockNumber {
+		t.Fatalf("claim record should keep the final status, got %+v", data)
+	}
+}
+
+// 未及时确认的领取记为 pending，查询回执时确认最终状态并保存
+func TestGetReceiptFinishesPendingReceipt(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetAutoReceipt(false)
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if receipt, _ := c.GetReceipt(ReceiptID(txHash)); receipt == nil || receipt.Status != ReceiptPending {
+		t.Fatalf("expect a pending receipt, got %+v", receipt)
+	}
+
+	node.Mine(common.HexToHash(txHash), 1)
+	if receipt, _ := c.GetReceipt(ReceiptID(txHash)); receipt.Status != ReceiptConfirmed || receipt.BlockNumber == 0 {
+		t.Fatalf("expect the receipt to be confirmed, got %+v", receipt)
+	}
+	// 最终状态已保存，不再查询节点
+	calls := node.Calls("eth_getTransactionReceipt")
+	c.GetReceipt(ReceiptID(txHash))
+	if node.Calls("eth_getTransactionReceipt") != calls {
+		t.Fatal("final receipt should not query the node again")
+	}
+}
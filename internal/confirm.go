@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/persist"
+)
+
+// pendingTx 等待确认的领取交易，记录清理领取记录所需的 key 信息
+type pendingTx struct {
+	Net        string `json:"net"`
+	Address    string `json:"address"`
+	Type       string `json:"type"`
+	TxHash     string `json:"txHash"`
+	SendTxTime int64  `json:"sendTxTime"`
+	RecentTime int64  `json:"recentTime"`
+}
+
+// StartConfirmTracker 定期检查已记录的领取交易，超过 dropped_tx_timeout 仍查不到回执的交易视为被重组丢弃，
+// 清除对应的领取记录使用户可以重新领取
+func (c *Client) StartConfirmTracker() {
+	interval := c.Config.Axiom.DroppedTxCheckInterval.ToDuration()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkPendingTxs()
+			}
+		}
+	}()
+}
+
+func (c *Client) checkPendingTxs() {
+	timeout := c.Config.Axiom.DroppedTxTimeout.ToDuration()
+	c.axiomLock.Lock()
+	client := c.axiomClient
+	c.axiomLock.Unlock()
+	it := c.ldb.Prefix(c.construPendingKey(c.Config.Axiom.TestNetName, ""))
+	for it.Next() {
+		pending := &pendingTx{}
+		if err := json.Unmarshal(it.Value(), pending); err != nil {
+			c.logger.Errorf("unmarshal pending tx %s failed: %v", it.Key(), err)
+			continue
+		}
+		if time.Since(time.Unix(pending.SendTxTime, 0)) < timeout {
+			continue
+		}
+		r, err := client.TransactionReceipt(context.Background(), common.HexToHash(pending.TxHash))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			c.logger.Warnf("query receipt of %s failed: %v", pending.TxHash, err)
+			continue
+		}
+		if err == nil {
+			c.finishReceipt(pending.TxHash, r)
+			c.ldb.Delete(c.construPendingKey(pending.Net, pending.TxHash))
+			continue
+		}
+		c.clearDroppedClaim(pending)
+	}
+}
+
+// clearDroppedClaim 删除被丢弃交易对应的领取记录，统计数据不回滚
+func (c *Client) clearDroppedClaim(pending *pendingTx) {
+	c.logger.Warnf("tx %s of %s not found after %s, clear the claim record", pending.TxHash, pending.Address, c.Config.Axiom.DroppedTxTimeout.String())
+	batch := c.ldb.NewBatch()
+	addressKey := c.construAddressKey(pending.Net, pending.Type, pending.Address)
+	data := &AddressData{}
+	if value := c.ldb.Get(addressKey); value != nil && json.Unmarshal(value, data) == nil && data.TxHash == pending.TxHash {
+		batch.Delete(addressKey)
+	}
+	batch.Delete(c.construHistoryKey(pending.Net, pending.Address, pending.SendTxTime))
+	batch.Delete(c.construRecentKey(pending.Net, pending.Address, pending.RecentTime))
+	batch.Delete(c.construPendingKey(pending.Net, pending.TxHash))
+	batch.Commit()
+	c.setReceiptStatus(pending.TxHash, ReceiptDropped)
+	c.releaseFunds(pending.TxHash, false)
+}
+
+// construPendingKey 生成待确认交易 key，txHash 为空时生成用于遍历的前缀
+func (c *Client) construPendingKey(net string, txHash string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("pending-")
+	buffer.WriteString(txHash)
+	return persist.CompositeKey(net, buffer)
+}
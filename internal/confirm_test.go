@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// newConfirmClient 开启交易确认跟踪，发出的交易不会自动上链
+func newConfirmClient(t *testing.T, node *testutil.Node) *Client {
+	t.Helper()
+	node.SetAutoReceipt(false)
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.DroppedTxTimeout = repo.Duration(time.Nanosecond)
+	})
+}
+
+func TestCheckPendingTxsClearsDroppedClaim(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newConfirmClient(t, node)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	node.Drop(common.HexToHash(txHash))
+	c.checkPendingTxs()
+
+	if data := c.LastClaim(net, testRecipient); data != nil {
+		t.Fatalf("claim record of the dropped tx should be cleared, got %+v", data)
+	}
+	if count := c.ClaimCount(net, testRecipient); count != 0 {
+		t.Fatalf("history of the dropped tx should be cleared, got %d", count)
+	}
+	if c.ldb.Has(c.construPendingKey(net, txHash)) {
+		t.Fatal("pending index should be removed")
+	}
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("address should be able to claim again, got %d %v", code, err)
+	}
+}
+
+func TestCheckPendingTxsKeepsConfirmedClaim(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newConfirmClient(t, node)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	node.Mine(common.HexToHash(txHash), 1)
+	c.checkPendingTxs()
+
+	if data := c.LastClaim(net, testRecipient); data == nil || data.TxHash != txHash {
+		t.Fatalf("claim record of the confirmed tx should be kept, got %+v", data)
+	}
+	if c.ldb.Has(c.construPendingKey(net, txHash)) {
+		t.Fatal("pending index should be removed after confirmation")
+	}
+	if _, code, _ := claim(c, ctx, testRecipient, 1); code != global.ReqWithinDayCode {
+		t.Fatalf("confirmed claim should still limit the address, got %d", code)
+	}
+}
+
+func TestCheckPendingTxsWaitsForTimeout(t *testing.T) {
+	node := testutil.NewNode(t)
+	node.SetAutoReceipt(false)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.DroppedTxTimeout = repo.Duration(time.Hour)
+	})
+
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	node.Drop(common.HexToHash(txHash))
+	c.checkPendingTxs()
+
+	if data := c.LastClaim(c.Config.Axiom.TestNetName, testRecipient); data == nil {
+		t.Fatal("claim record should be kept before the timeout")
+	}
+}
+
+func TestInitializeRejectsZeroDroppedTxCheckInterval(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.DroppedTxTimeout = repo.Duration(time.Minute)
+	cfg.Axiom.DroppedTxCheckInterval = 0
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("initialize should reject a zero check interval")
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// baseFeeGwei 区块的 base fee（gwei），不支持 EIP-1559 的链为 0
+func baseFeeGwei(header *types.Header) float64 {
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(baseFeeOf(header)), big.NewFloat(params.GWei)).Float64()
+	return gwei
+}
+
+// congestionTier 按 base fee 选择拥堵档位，base fee 不低于 base_fee_above 的最高一档生效，低于所有档位或未配置时返回 nil
+func congestionTier(tiers []repo.CongestionTier, baseFee float64) *repo.CongestionTier {
+	sorted := make([]repo.CongestionTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BaseFeeAbove > sorted[j].BaseFeeAbove })
+	for i := range sorted {
+		if baseFee >= sorted[i].BaseFeeAbove {
+			return &sorted[i]
+		}
+	}
+	return nil
+}
+
+// congestionAmount 按节点最新区块的 base fee 所在拥堵档位调整发放数量
+func (c *Client) congestionAmount(amount float64) (float64, error) {
+	if len(c.Config.Axiom.CongestionTiers) == 0 {
+		return amount, nil
+	}
+	header, err := c.axiomClient.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return amount, err
+	}
+	tier := congestionTier(c.Config.Axiom.CongestionTiers, baseFeeGwei(header))
+	if tier == nil {
+		return amount, nil
+	}
+	return amount * tier.Multiplier, nil
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestCongestionTier(t *testing.T) {
+	// 配置顺序不影响选择
+	tiers := []repo.CongestionTier{{BaseFeeAbove: 50, Multiplier: 0.5}, {BaseFeeAbove: 200, Multiplier: 0.2}}
+	cases := []struct {
+		baseFee    float64
+		multiplier float64
+	}{
+		{500, 0.2},
+		{200, 0.2},
+		{199, 0.5},
+		{50, 0.5},
+	}
+	for _, tc := range cases {
+		if tier := congestionTier(tiers, tc.baseFee); tier == nil || tier.Multiplier != tc.multiplier {
+			t.Fatalf("base fee %v: expect multiplier %v, got %+v", tc.baseFee, tc.multiplier, tier)
+		}
+	}
+	// 低于所有档位时不调整
+	if tier := congestionTier(tiers, 10); tier != nil {
+		t.Fatalf("base fee below all tiers should give nil, got %+v", tier)
+	}
+	if tier := congestionTier(nil, 10); tier != nil {
+		t.Fatalf("no tiers should give nil, got %+v", tier)
+	}
+}
+
+func TestClaimScalesByCongestionTier(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.CongestionTiers = []repo.CongestionTier{{BaseFeeAbove: 100, Multiplier: 0.5}}
+	})
+	node.SetBlock(1, time.Now(), big.NewInt(150e9))
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 2); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if _, amount := dripValue(t, node.Sent()[0]); amount != 1 {
+		t.Fatalf("congestion should halve the payout, got %v", amount)
+	}
+	status, err := c.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.BaseFee != 150 || status.CongestionTier == nil || status.CongestionTier.Multiplier != 0.5 {
+		t.Fatalf("status should report the base fee and active tier, got %v %+v", status.BaseFee, status.CongestionTier)
+	}
+
+	// base fee 回落后按原数量发放
+	node.SetBlock(2, time.Now(), big.NewInt(1e9))
+	if _, code, err := claim(c, context.Background(), "0x2222222222222222222222222222222222222222", 2); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if _, amount := dripValue(t, node.Sent()[1]); amount != 2 {
+		t.Fatalf("low base fee should not scale the payout, got %v", amount)
+	}
+}
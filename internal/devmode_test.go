@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newDevModeClient(t *testing.T, node *testutil.Node) *Client {
+	t.Helper()
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.DevMode = true
+		cfg.Axiom.MaxAddressesPerIP = 1
+	})
+}
+
+func TestDevModeSkipsClaimInterval(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newDevModeClient(t, node)
+	for i := 0; i < 2; i++ {
+		if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+			t.Fatalf("claim %d should pass in dev mode: %d %v", i, code, err)
+		}
+	}
+	if sent := node.Sent(); len(sent) != 2 {
+		t.Fatalf("expect 2 txs, got %d", len(sent))
+	}
+}
+
+// 同一 IP 领取多个地址不受限制
+func TestDevModeSkipsIPAddressLimit(t *testing.T) {
+	c := newDevModeClient(t, testutil.NewNode(t))
+	ctx := WithRequest(context.Background(), Request{IP: "203.0.113.7"})
+	for _, address := range []string{testRecipient, "0x2222222222222222222222222222222222222222"} {
+		if _, code, err := claim(c, ctx, address, 1); err != nil {
+			t.Fatalf("claim of %s should pass in dev mode: %d %v", address, code, err)
+		}
+	}
+}
+
+func TestDevModeSkipsTweetVerification(t *testing.T) {
+	c := newDevModeClient(t, testutil.NewNode(t))
+	if code, _ := c.TweetReqCheck(context.Background(), "https://twitter.com/axiomesh/status/1", testRecipient); code != global.SUCCESS {
+		t.Fatalf("tweet should not be verified in dev mode, got %d", code)
+	}
+}
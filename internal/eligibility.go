@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// Eligibility 地址当前的领取资格摘要。每个地址每 24 小时可领取一次，RemainingToday 为当前还可领取的次数，
+// Cooldown 为距离下次可领取的秒数（向上取整），NextEligibleAt 为下次可领取的 unix 时间戳，RemainingAllowance 为地址余额距离 claim_limit 的差额，
+// Amount 为此时直接领取将发放的数量，不含推文与推荐码奖励
+type Eligibility struct {
+	RemainingToday     int     `json:"remainingToday"`
+	RemainingAllowance float64 `json:"remainingAllowance"`
+	Cooldown           int64   `json:"cooldown"`
+	NextEligibleAt     int64   `json:"nextEligibleAt,omitempty"`
+	Amount             float64 `json:"amount"`
+}
+
+// Eligibility 根据领取记录、地址余额与配置计算领取资格摘要
+func (c *Client) Eligibility(ctx context.Context, net string, address string) (*Eligibility, error) {
+	lowerAddress := strings.ToLower(address)
+	eligibility := &Eligibility{RemainingToday: 1}
+	if next := c.NextEligibleAt(net, lowerAddress); !next.IsZero() {
+		eligibility.RemainingToday = 0
+		eligibility.Cooldown = CooldownSeconds(next)
+		eligibility.NextEligibleAt = next.Unix()
+	}
+
+	balance, err := c.axiomClient.BalanceAt(context.Background(), common.HexToAddress(address), nil)
+	if err != nil {
+		return nil, err
+	}
+	headroom := new(big.Int).Sub(floatToEtherBigInt(c.Config.Axiom.ClaimLimit), balance)
+	if headroom.Sign() <= 0 {
+		return eligibility, nil
+	}
+	eligibility.RemainingAllowance = etherBigIntToFloat(headroom)
+
+	amount, err := c.ruleAmount(net, address, c.Config.Axiom.Amount)
+	if err != nil {
+		return nil, err
+	}
+	amount = c.tieredAmount(net, lowerAddress, amount)
+	if amount, err = c.balanceTierAmount(amount); err != nil {
+		return nil, err
+	}
+	if amount, err = c.congestionAmount(amount); err != nil {
+		return nil, err
+	}
+	if amount, err = topUpAmount(ctx, c, address, amount); err != nil {
+		if err.Error() == global.EnoughTokenMsg {
+			return eligibility, nil
+		}
+		return nil, err
+	}
+	eligibility.Amount = amount
+	return eligibility, nil
+}
+
+// NextEligibleAt 上次领取满 24 小时的时间，当前已可领取时返回零值，测试地址与开发模式不受限制
+func (c *Client) NextEligibleAt(net string, address string) time.Time {
+	if c.isTester(address) || c.Config.DevMode {
+		return time.Time{}
+	}
+	data := c.LastClaim(net, strings.ToLower(address))
+	if data == nil {
+		return time.Time{}
+	}
+	next := time.Unix(data.SendTxTime, 0).Add(24 * time.Hour)
+	if !next.After(time.Now()) {
+		return time.Time{}
+	}
+	return next
+}
+
+// CooldownSeconds 距离 next 的秒数，向上取整，保证按该秒数重试时已可领取
+func CooldownSeconds(next time.Time) int64 {
+	return int64(math.Ceil(time.Until(next).Seconds()))
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestEligibilityOfNewAddress(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetBalance(testRecipient, 100)
+
+	eligibility, err := c.Eligibility(context.Background(), c.Config.Axiom.TestNetName, testRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Eligibility{RemainingToday: 1, RemainingAllowance: c.Config.Axiom.ClaimLimit - 100, Amount: c.Config.Axiom.Amount}
+	if *eligibility != want {
+		t.Fatalf("expect %+v, got %+v", want, *eligibility)
+	}
+}
+
+func TestEligibilityAfterClaim(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	eligibility, err := c.Eligibility(context.Background(), c.Config.Axiom.TestNetName, testRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := time.Now().Add(24 * time.Hour).Unix()
+	if eligibility.RemainingToday != 0 || eligibility.NextEligibleAt < next-5 || eligibility.NextEligibleAt > next {
+		t.Fatalf("claimed address should wait 24 hours, got %+v", eligibility)
+	}
+	if eligibility.Cooldown <= 24*3600-5 || eligibility.Cooldown > 24*3600 {
+		t.Fatalf("expect cooldown about 24 hours, got %d", eligibility.Cooldown)
+	}
+}
+
+// 余额已达到 claim_limit 时没有可领取的数量
+func TestEligibilityAboveClaimLimit(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetBalance(testRecipient, c.Config.Axiom.ClaimLimit)
+
+	eligibility, err := c.Eligibility(context.Background(), c.Config.Axiom.TestNetName, testRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eligibility.RemainingAllowance != 0 || eligibility.Amount != 0 {
+		t.Fatalf("expect no allowance, got %+v", eligibility)
+	}
+}
+
+func TestCooldownSecondsRoundsUp(t *testing.T) {
+	if got := CooldownSeconds(time.Now().Add(1500 * time.Millisecond)); got != 2 {
+		t.Fatalf("expect 2 seconds, got %d", got)
+	}
+}
+
+func TestNextEligibleAt(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.TesterAllowlist = []string{"0x2222222222222222222222222222222222222222"}
+	})
+	net := c.Config.Axiom.TestNetName
+	if next := c.NextEligibleAt(net, testRecipient); !next.IsZero() {
+		t.Fatalf("new address should be eligible now, got %v", next)
+	}
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if d := time.Until(c.NextEligibleAt(net, testRecipient)); d <= 24*time.Hour-5*time.Second || d > 24*time.Hour {
+		t.Fatalf("expect next eligible time about 24 hours later, got %v", d)
+	}
+	// 地址大小写不影响查询结果
+	if next := c.NextEligibleAt(net, "0x"+strings.ToUpper(testRecipient[2:])); next.IsZero() {
+		t.Fatal("checksum address should share the claim interval")
+	}
+
+	// 测试地址不受领取间隔限制
+	tester := "0x2222222222222222222222222222222222222222"
+	if _, code, err := claim(c, context.Background(), tester, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if next := c.NextEligibleAt(net, tester); !next.IsZero() {
+		t.Fatalf("tester should be eligible now, got %v", next)
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// 活动起止时间的配置格式，按 event.timezone 解析
+const eventTimeLayout = "2006-01-02 15:04"
+
+// EventWindow 限时活动的起止时间，未配置的一端为空
+type EventWindow struct {
+	Start  string `json:"start,omitempty"`
+	End    string `json:"end,omitempty"`
+	Active bool   `json:"active"`
+}
+
+// Event 返回 now 时刻的活动窗口，未配置活动时返回 nil
+func (c *Client) Event(now time.Time) *EventWindow {
+	if c.eventStart.IsZero() && c.eventEnd.IsZero() {
+		return nil
+	}
+	window := &EventWindow{Active: c.EventActive(now)}
+	if !c.eventStart.IsZero() {
+		window.Start = c.eventStart.Format(time.RFC3339)
+	}
+	if !c.eventEnd.IsZero() {
+		window.End = c.eventEnd.Format(time.RFC3339)
+	}
+	return window
+}
+
+// EventActive 判断 now 是否处于活动时间内，包含开始时间，不包含结束时间
+func (c *Client) EventActive(now time.Time) bool {
+	if !c.eventStart.IsZero() && now.Before(c.eventStart) {
+		return false
+	}
+	if !c.eventEnd.IsZero() && !now.Before(c.eventEnd) {
+		return false
+	}
+	return true
+}
+
+func (c *Client) initEvent() error {
+	cfg := c.Config.Event
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("load event timezone: %w", err)
+	}
+	parse := func(name string, value string) (time.Time, error) {
+		if value == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.ParseInLocation(eventTimeLayout, value, location)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid event %s %q: %w", name, value, err)
+		}
+		return t, nil
+	}
+	if c.eventStart, err = parse("start", cfg.Start); err != nil {
+		return err
+	}
+	if c.eventEnd, err = parse("end", cfg.End); err != nil {
+		return err
+	}
+	if !c.eventStart.IsZero() && !c.eventEnd.IsZero() && !c.eventStart.Before(c.eventEnd) {
+		return fmt.Errorf("event start %q must be before end %q", cfg.Start, cfg.End)
+	}
+	return nil
+}
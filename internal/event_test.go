@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newEventClient(t *testing.T, start string, end string) *Client {
+	t.Helper()
+	return newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Event = repo.Event{Timezone: "Asia/Shanghai", Start: start, End: end}
+	})
+}
+
+func TestEventActive(t *testing.T) {
+	c := newEventClient(t, "2026-01-01 08:00", "2026-01-02 08:00")
+	// 起止时间按 Asia/Shanghai 解析，即 UTC 0 点
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for at, active := range map[time.Time]bool{
+		start.Add(-time.Second):     false,
+		start:                       true,
+		start.Add(24*time.Hour - 1): true,
+		start.Add(24 * time.Hour):   false,
+	} {
+		if got := c.EventActive(at); got != active {
+			t.Errorf("%s: expect active=%v, got %v", at, active, got)
+		}
+	}
+
+	window := c.Event(start)
+	if window == nil || window.Start != "2026-01-01T08:00:00+08:00" || window.End != "2026-01-02T08:00:00+08:00" || !window.Active {
+		t.Fatalf("unexpected event window %+v", window)
+	}
+}
+
+// 只配置开始时间时没有结束限制
+func TestEventOpenEnded(t *testing.T) {
+	c := newEventClient(t, "2026-01-01 08:00", "")
+	if !c.EventActive(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("event without end should stay active")
+	}
+	if window := c.Event(time.Now()); window == nil || window.End != "" {
+		t.Fatalf("window should omit the end, got %+v", window)
+	}
+}
+
+func TestEventNotConfigured(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if c.Event(time.Now()) != nil || !c.EventActive(time.Now()) {
+		t.Fatal("claims should always be served without an event")
+	}
+}
+
+func TestInitEventRejectsInvalidConfig(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	for _, event := range []repo.Event{
+		{Timezone: "Mars/Olympus"},
+		{Timezone: "UTC", Start: "2026-01-01"},
+		{Timezone: "UTC", Start: "2026-01-02 00:00", End: "2026-01-01 00:00"},
+		{Timezone: "UTC", Start: "2026-01-01 00:00", End: "2026-01-01 00:00"},
+	} {
+		c.Config.Event = event
+		if err := c.initEvent(); err == nil {
+			t.Errorf("event %+v should be rejected", event)
+		}
+	}
+}
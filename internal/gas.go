@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// 实际 gas 与预估值偏差超过该比例时记录告警
+const gasDiscrepancyRatio = 0.2
+
+// recordGas 将回执中的实际 gas 记录到领取数据中，与预估值偏差较大时告警
+func (c *Client) recordGas(ctx context.Context, data *AddressData, estimate uint64, receipt *types.Receipt) {
+	data.GasEstimate = estimate
+	if receipt == nil {
+		return
+	}
+	data.GasUsed = receipt.GasUsed
+	data.BlockNumber = receiptBlock(receipt)
+	if receipt.EffectiveGasPrice != nil {
+		data.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+	if estimate == 0 {
+		return
+	}
+	diff := float64(receipt.GasUsed) - float64(estimate)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > float64(estimate)*gasDiscrepancyRatio {
+		c.requestLogger(ctx).Warnf("gas used %d of tx %s differs from estimate %d", receipt.GasUsed, data.TxHash, estimate)
+	}
+}
+
+// LastClaim 返回地址最近一次领取原生代币的记录，不存在时返回 nil
+func (c *Client) LastClaim(net string, address string) *AddressData {
+	value := c.ldb.Get(c.construAddressKey(net, global.NativeToken, address))
+	if value == nil {
+		return nil
+	}
+	data := &AddressData{}
+	if err := json.Unmarshal(value, data); err != nil {
+		c.logger.Errorf("unmarshal claim data of %s failed: %v", address, err)
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stuckTx 发送后未能及时确认的领取交易，hashes 记录原交易及每次加价替换后的交易哈希
+type stuckTx struct {
+	tx      *types.Transaction
+	hashes  []string
+	sentAt  time.Time
+	baseFee *big.Int
+	bumps   int
+}
+
+// trackStuckTx 记录未能及时确认的交易，由后台按加价策略检查，未开启 stuck_tx_threshold 时不记录
+func (c *Client) trackStuckTx(ctx context.Context, tx *types.Transaction) {
+	if c.Config.Axiom.StuckTxThreshold <= 0 {
+		return
+	}
+	header, err := c.axiomClient.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		c.requestLogger(ctx).Warnf("track stuck tx %s: %v", tx.Hash().Hex(), err)
+		return
+	}
+	c.stuckLock.Lock()
+	defer c.stuckLock.Unlock()
+	c.stuckTxs[tx.Nonce()] = &stuckTx{
+		tx:      tx,
+		hashes:  []string{tx.Hash().Hex()},
+		sentAt:  time.Now(),
+		baseFee: baseFeeOf(header),
+	}
+}
+
+// StartGasBumper 按 stuck_tx_check_interval 检查未确认的交易
+func (c *Client) StartGasBumper() {
+	interval := c.Config.Axiom.StuckTxCheckInterval.ToDuration()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkStuckTxs()
+			}
+		}
+	}()
+}
+
+// shouldBumpGas 交易等待超过 threshold 且当前 base fee 高于发送时，并且加价次数未达到 maxBumps 时才加价重发
+func shouldBumpGas(pendingFor time.Duration, threshold time.Duration, sentBaseFee *big.Int, baseFee *big.Int, bumps int, maxBumps int) (bool, string) {
+	if pendingFor < threshold {
+		return false, fmt.Sprintf("pending %s, below threshold %s", pendingFor.Truncate(time.Second), threshold)
+	}
+	if baseFee.Cmp(sentBaseFee) <= 0 {
+		return false, fmt.Sprintf("base fee %s has not risen since sent (%s)", baseFee, sentBaseFee)
+	}
+	if bumps >= maxBumps {
+		return false, fmt.Sprintf("bump cap %d reached", maxBumps)
+	}
+	return true, fmt.Sprintf("pending %s, base fee rose from %s to %s", pendingFor.Truncate(time.Second), sentBaseFee, baseFee)
+}
+
+// bumpFee 按 percent 提高费用，新的 fee cap 至少覆盖两倍当前 base fee 加上 tip
+func bumpFee(feeCap *big.Int, tipCap *big.Int, baseFee *big.Int, percent int) (*big.Int, *big.Int) {
+	scale := func(v *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(v, big.NewInt(int64(100+percent)))
+		return bumped.Div(bumped, big.NewInt(100))
+	}
+	newTip := scale(tipCap)
+	newCap := scale(feeCap)
+	if floor := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), newTip); newCap.Cmp(floor) < 0 {
+		newCap = floor
+	}
+	return newCap, newTip
+}
+
+// stuckCheck 检查时在 stuckLock 下复制的交易状态，bumpStuckTx 加价成功后更新 live
+type stuckCheck struct {
+	live     *stuckTx
+	snapshot stuckTx
+}
+
+func (c *Client) checkStuckTxs() {
+	c.stuckLock.Lock()
+	pending := make([]stuckCheck, 0, len(c.stuckTxs))
+	for _, stuck := range c.stuckTxs {
+		snapshot := *stuck
+		snapshot.hashes = append([]string(nil), stuck.hashes...)
+		pending = append(pending, stuckCheck{live: stuck, snapshot: snapshot})
+	}
+	c.stuckLock.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	header, err := c.axiomClient.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		c.logger.Warnf("check stuck txs: %v", err)
+		return
+	}
+	baseFee := baseFeeOf(header)
+	for _, check := range pending {
+		stuck := &check.snapshot
+		if c.stuckTxMined(stuck) {
+			c.stuckLock.Lock()
+			delete(c.stuckTxs, stuck.tx.Nonce())
+			c.stuckLock.Unlock()
+			continue
+		}
+		bump, reason := shouldBumpGas(time.Since(stuck.sentAt), c.Config.Axiom.StuckTxThreshold.ToDuration(), stuck.baseFee, baseFee, stuck.bumps, c.Config.Axiom.MaxGasBumps)
+		if !bump {
+			c.logger.Infof("keep tx %s (nonce %d): %s", stuck.tx.Hash().Hex(), stuck.tx.Nonce(), reason)
+			continue
+		}
+		if err := c.bumpStuckTx(check.live, stuck.tx, baseFee, reason); err != nil {
+			c.logger.Warnf("bump tx %s (nonce %d) failed: %v", stuck.tx.Hash().Hex(), stuck.tx.Nonce(), err)
+		}
+	}
+}
+
+// stuckTxMined 原交易或任意一次替换交易已有回执时结束跟踪，并更新原交易对应的领取回执
+func (c *Client) stuckTxMined(stuck *stuckTx) bool {
+	for _, hash := range stuck.hashes {
+		receipt, err := c.axiomClient.TransactionReceipt(context.Background(), common.HexToHash(hash))
+		if err == nil {
+			c.finishReceipt(stuck.hashes[0], receipt)
+			return true
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			c.logger.Warnf("query receipt of %s failed: %v", hash, err)
+		}
+	}
+	return false
+}
+
+// bumpStuckTx 加价替换 old，old 为检查时复制的当前交易
+func (c *Client) bumpStuckTx(stuck *stuckTx, old *types.Transaction, baseFee *big.Int, reason string) error {
+	feeCap, tipCap := bumpFee(old.GasFeeCap(), old.GasTipCap(), baseFee, c.Config.Axiom.GasBumpPercent)
+	c.axiomLock.Lock()
+	defer c.axiomLock.Unlock()
+	if c.axiomAuth == nil || txSender(old) != c.axiomAuth.From.Hex() {
+		return errors.New("funding key has changed since the tx was sent")
+	}
+	signed, err := types.SignNewTx(c.axiomPrivateKey, types.LatestSignerForChainID(old.ChainId()), &types.DynamicFeeTx{
+		ChainID:   old.ChainId(),
+		Nonce:     old.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       old.Gas(),
+		To:        old.To(),
+		Value:     old.Value(),
+		Data:      old.Data(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.axiomClient.SendTransaction(context.Background(), signed); err != nil {
+		return err
+	}
+	c.logger.Warnf("bumped tx %s (nonce %d) to %s, fee cap %s -> %s, tip %s -> %s: %s",
+		old.Hash().Hex(), old.Nonce(), signed.Hash().Hex(), old.GasFeeCap(), feeCap, old.GasTipCap(), tipCap, reason)
+
+	c.stuckLock.Lock()
+	defer c.stuckLock.Unlock()
+	stuck.tx = signed
+	stuck.hashes = append(stuck.hashes, signed.Hash().Hex())
+	stuck.baseFee = baseFee
+	stuck.bumps++
+	return nil
+}
+
+func baseFeeOf(header *types.Header) *big.Int {
+	if header.BaseFee == nil {
+		return new(big.Int)
+	}
+	return header.BaseFee
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestShouldBumpGas(t *testing.T) {
+	sent, risen := big.NewInt(100), big.NewInt(150)
+	cases := []struct {
+		name       string
+		pendingFor time.Duration
+		baseFee    *big.Int
+		bumps      int
+		bump       bool
+	}{
+		{"below threshold", time.Minute, risen, 0, false},
+		{"base fee not risen", 10 * time.Minute, sent, 0, false},
+		{"base fee dropped", 10 * time.Minute, big.NewInt(50), 0, false},
+		{"cap reached", 10 * time.Minute, risen, 3, false},
+		{"stuck", 10 * time.Minute, risen, 2, true},
+		{"at threshold", 5 * time.Minute, risen, 0, true},
+	}
+	for _, tc := range cases {
+		bump, reason := shouldBumpGas(tc.pendingFor, 5*time.Minute, sent, tc.baseFee, tc.bumps, 3)
+		if bump != tc.bump || reason == "" {
+			t.Errorf("%s: expect bump=%v, got %v (%s)", tc.name, tc.bump, bump, reason)
+		}
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	// 按比例加价已覆盖 2 倍 base fee 加 tip
+	feeCap, tip := bumpFee(big.NewInt(1000), big.NewInt(100), big.NewInt(100), 15)
+	if feeCap.Int64() != 1150 || tip.Int64() != 115 {
+		t.Fatalf("expect fee cap 1150 and tip 115, got %s %s", feeCap, tip)
+	}
+	// base fee 上涨较多时 fee cap 取 2 倍 base fee 加新 tip
+	feeCap, tip = bumpFee(big.NewInt(1000), big.NewInt(100), big.NewInt(1000), 15)
+	if feeCap.Int64() != 2115 || tip.Int64() != 115 {
+		t.Fatalf("expect fee cap 2115 and tip 115, got %s %s", feeCap, tip)
+	}
+}
+
+func TestCheckStuckTxsBumpsAndStopsWhenMined(t *testing.T) {
+	node := testutil.NewNode(t)
+	node.SetAutoReceipt(false)
+	node.SetBlock(1, time.Now(), big.NewInt(1e9))
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.StuckTxThreshold = repo.Duration(time.Minute)
+		cfg.Axiom.MaxGasBumps = 1
+	})
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	original := node.Sent()[0]
+
+	// 未达到等待时长时不加价
+	c.checkStuckTxs()
+	if sent := node.Sent(); len(sent) != 1 {
+		t.Fatalf("tx below the threshold should not be bumped, got %d txs", len(sent))
+	}
+
+	c.stuckLock.Lock()
+	c.stuckTxs[original.Nonce()].sentAt = time.Now().Add(-time.Hour)
+	c.stuckLock.Unlock()
+	node.SetBlock(2, time.Now(), big.NewInt(2e9))
+	c.checkStuckTxs()
+	sent := node.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("stuck tx should be bumped once, got %d txs", len(sent))
+	}
+	bumped := sent[1]
+	if bumped.Nonce() != original.Nonce() || bumped.GasFeeCap().Cmp(original.GasFeeCap()) <= 0 || bumped.GasTipCap().Cmp(original.GasTipCap()) <= 0 {
+		t.Fatalf("replacement should reuse the nonce with higher fees, got nonce %d cap %s tip %s", bumped.Nonce(), bumped.GasFeeCap(), bumped.GasTipCap())
+	}
+
+	// 达到加价次数上限后不再加价
+	node.SetBlock(3, time.Now(), big.NewInt(4e9))
+	c.checkStuckTxs()
+	if sent := node.Sent(); len(sent) != 2 {
+		t.Fatalf("bump cap should stop further bumps, got %d txs", len(sent))
+	}
+
+	// 替换交易上链后结束跟踪，并按原交易更新回执
+	node.Mine(bumped.Hash(), 1)
+	c.checkStuckTxs()
+	c.stuckLock.Lock()
+	tracked := len(c.stuckTxs)
+	c.stuckLock.Unlock()
+	if tracked != 0 {
+		t.Fatalf("mined tx should no longer be tracked, got %d", tracked)
+	}
+	if receipt, ok := c.GetReceipt(ReceiptID(txHash)); !ok || receipt.Status != ReceiptConfirmed {
+		t.Fatalf("receipt of the original tx should be confirmed, got %+v", receipt)
+	}
+}
+
+func TestTrackStuckTxDisabled(t *testing.T) {
+	node := testutil.NewNode(t)
+	node.SetAutoReceipt(false)
+	c := newTestClient(t, node, nil)
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if len(c.stuckTxs) != 0 {
+		t.Fatal("txs should not be tracked without stuck_tx_threshold")
+	}
+}
+
+func TestInitializeRejectsZeroStuckTxCheckInterval(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.StuckTxThreshold = repo.Duration(time.Minute)
+	cfg.Axiom.StuckTxCheckInterval = 0
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("zero stuck_tx_check_interval should be rejected")
+	}
+}
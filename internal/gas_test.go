@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestRecordGasFromReceipt(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	data := &AddressData{TxHash: "0x01"}
+
+	c.recordGas(context.Background(), data, 50000, &types.Receipt{GasUsed: 21000, EffectiveGasPrice: big.NewInt(1e9), BlockNumber: big.NewInt(7)})
+	if data.GasEstimate != 50000 || data.GasUsed != 21000 || data.EffectiveGasPrice != "1000000000" || data.BlockNumber != 7 {
+		t.Fatalf("unexpected gas record %+v", data)
+	}
+}
+
+// 没有回执时只记录预估值
+func TestRecordGasWithoutReceipt(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	data := &AddressData{TxHash: "0x01"}
+
+	c.recordGas(context.Background(), data, 50000, nil)
+	if data.GasEstimate != 50000 || data.GasUsed != 0 || data.EffectiveGasPrice != "" {
+		t.Fatalf("unexpected gas record %+v", data)
+	}
+}
+
+func TestClaimRecordsReceiptGas(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.GasLimitMultiplier = 1.2
+	})
+	net := c.Config.Axiom.TestNetName
+
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	data := c.LastClaim(net, testRecipient)
+	if data == nil || data.TxHash != txHash || data.GasUsed != 21000 || data.GasEstimate == 0 {
+		t.Fatalf("claim record should keep the receipt gas, got %+v", data)
+	}
+}
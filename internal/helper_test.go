@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/contract"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testRecipient = "0x1111111111111111111111111111111111111111"
+
+// testFaucetAddress 测试使用的水龙头合约地址
+var testFaucetAddress = "0x" + strings.Repeat("fa", 20)
+
+// newTestClient 连接测试节点并初始化客户端，存储使用临时目录；水龙头合约与资金账户预置充足余额
+func newTestClient(t *testing.T, node *testutil.Node, setup func(cfg *repo.Config)) *Client {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.AxiomKey = hex.EncodeToString(crypto.FromECDSA(key))
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.FaucetAddr = testFaucetAddress
+	if setup != nil {
+		setup(cfg)
+	}
+	node.SetBalance(cfg.Axiom.FaucetAddr, 1e6)
+	node.SetBalance(crypto.PubkeyToAddress(key.PublicKey).Hex(), 1000)
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// dripValue 解析领取交易调用 drip 的接收地址与数量（ether）
+func dripValue(t *testing.T, tx *types.Transaction) (string, float64) {
+	t.Helper()
+	contractAbi, err := abi.JSON(strings.NewReader(contract.TaurusFaucetABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := contractAbi.Methods["drip"].Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.ToLower(args[0].(common.Address).Hex()), etherBigIntToFloat(args[1].(*big.Int))
+}
+
+// claim 按 directClaim 的流程领取一次，结束后释放预锁
+func claim(c *Client, ctx context.Context, address string, amount float64) (string, int, error) {
+	txHash, code, err := c.SendTra(ctx, c.Config.Axiom.TestNetName, address, amount, 0, "", "")
+	if !errors.Is(err, ErrAddressLocked) {
+		DeleteTxData(c, strings.ToLower(address), global.NativeToken, c.Config.Axiom.TestNetName)
+	}
+	return txHash, code, err
+}
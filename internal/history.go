@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/axiomesh/faucet/persist"
+)
+
+// ClaimRecord 每次领取的历史记录，与只保留最近一次领取的 AddressData 不同，历史记录不会被覆盖
+type ClaimRecord struct {
+	Net     string `json:"net"`
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	AddressData
+}
+
+func (c *Client) putClaimRecord(net string, typ string, address string, data *AddressData) error {
+	record := &ClaimRecord{
+		Net:         net,
+		Address:     address,
+		Type:        typ,
+		AddressData: *data,
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+	recentTime := time.Now().UnixNano()
+	batch := c.ldb.NewBatch()
+	batch.Put(c.construHistoryKey(net, address, data.SendTxTime), value)
+	batch.Put(c.construRecentKey(net, address, recentTime), value)
+	// 未确认的领取同样记录索引，确认后据此更新记录状态
+	if c.Config.Axiom.DroppedTxTimeout > 0 || data.Status == ReceiptPending {
+		pending, err := json.Marshal(&pendingTx{
+			Net:        net,
+			Address:    address,
+			Type:       typ,
+			TxHash:     data.TxHash,
+			SendTxTime: data.SendTxTime,
+			RecentTime: recentTime,
+		})
+		if err != nil {
+			return fmt.Errorf("json marshal failed: %w", err)
+		}
+		batch.Put(c.construPendingKey(net, data.TxHash), pending)
+	}
+	batch.Commit()
+	return nil
+}
+
+// finishClaimRecord 交易上链后更新发出时写入的 pending 领取记录，交易失败时删除地址的领取记录，使地址可以重新领取
+func (c *Client) finishClaimRecord(receipt *Receipt) {
+	value := c.ldb.Get(c.construPendingKey(receipt.Net, receipt.TxHash))
+	if value == nil {
+		return
+	}
+	pending := &pendingTx{}
+	if err := json.Unmarshal(value, pending); err != nil {
+		c.logger.Errorf("unmarshal pending tx %s failed: %v", receipt.TxHash, err)
+		return
+	}
+	batch := c.ldb.NewBatch()
+	addressKey := c.construAddressKey(pending.Net, pending.Type, pending.Address)
+	data := &AddressData{}
+	if value := c.ldb.Get(addressKey); value != nil && json.Unmarshal(value, data) == nil && data.TxHash == pending.TxHash && data.Status == ReceiptPending {
+		if receipt.Status == ReceiptReverted {
+			batch.Delete(addressKey)
+		} else if value, err := json.Marshal(finishedClaim(data, receipt)); err == nil {
+			batch.Put(addressKey, value)
+		}
+	}
+	for _, key := range [][]byte{
+		c.construHistoryKey(pending.Net, pending.Address, pending.SendTxTime),
+		c.construRecentKey(pending.Net, pending.Address, pending.RecentTime),
+	} {
+		record := &ClaimRecord{}
+		if value := c.ldb.Get(key); value != nil && json.Unmarshal(value, record) == nil && record.TxHash == pending.TxHash {
+			record.AddressData = *finishedClaim(&record.AddressData, receipt)
+			if value, err := json.Marshal(record); err == nil {
+				batch.Put(key, value)
+			}
+		}
+	}
+	batch.Delete(c.construPendingKey(pending.Net, pending.TxHash))
+	batch.Commit()
+}
+
+func finishedClaim(data *AddressData, receipt *Receipt) *AddressData {
+	finished := *data
+	finished.Status = receipt.Status
+	finished.BlockNumber = receipt.BlockNumber
+	return &finished
+}
+
+// ClaimCount 返回地址在该网络成功领取的次数
+func (c *Client) ClaimCount(net string, address string) int {
+	count := 0
+	it := c.ldb.Prefix(c.construHistoryKey(net, address, 0))
+	for it.Next() {
+		count++
+	}
+	return count
+}
+
+// RecentClaims 按时间倒序返回最近的 limit 条领取记录
+func (c *Client) RecentClaims(net string, limit int) []*ClaimRecord {
+	records := make([]*ClaimRecord, 0, limit)
+	it := c.ldb.Prefix(c.construRecentKey(net, "", 0))
+	for len(records) < limit && it.Next() {
+		record := &ClaimRecord{}
+		if err := json.Unmarshal(it.Value(), record); err != nil {
+			c.logger.Errorf("unmarshal claim record %s failed: %v", it.Key(), err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// IterateClaims 按地址、时间顺序遍历领取历史，address 为空时遍历该网络全部记录，fn 返回 false 时停止遍历
+func (c *Client) IterateClaims(net string, address string, fn func(record *ClaimRecord) bool) {
+	it := c.ldb.Prefix(c.construHistoryKey(net, address, 0))
+	for it.Next() {
+		record := &ClaimRecord{}
+		if err := json.Unmarshal(it.Value(), record); err != nil {
+			c.logger.Errorf("unmarshal claim record %s failed: %v", it.Key(), err)
+			continue
+		}
+		if !fn(record) {
+			return
+		}
+	}
+}
+
+// ErrInvalidCursor 分页游标无法解析或不属于当前查询
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ClaimPage 按地址、时间顺序分页返回领取历史，cursor 为上一页返回的游标，为空时从头开始。
+// 游标是上一页最后一条记录的 key，分页期间写入的新记录不会导致重复或遗漏已返回的记录；没有更多记录时 next 为空
+func (c *Client) ClaimPage(net string, address string, cursor string, limit int) (records []*ClaimRecord, next string, err error) {
+	prefix := c.construHistoryKey(net, address, 0)
+	it := c.ldb.Prefix(prefix)
+	var ok bool
+	if cursor == "" {
+		ok = it.Next()
+	} else {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || !bytes.HasPrefix(after, prefix) {
+			return nil, "", ErrInvalidCursor
+		}
+		ok = it.Seek(after)
+		if ok && bytes.Equal(it.Key(), after) {
+			ok = it.Next()
+		}
+	}
+	records = make([]*ClaimRecord, 0, limit)
+	var last []byte
+	for ; ok; ok = it.Next() {
+		if len(records) == limit {
+			return records, base64.RawURLEncoding.EncodeToString(last), nil
+		}
+		record := &ClaimRecord{}
+		if err := json.Unmarshal(it.Value(), record); err != nil {
+			c.logger.Errorf("unmarshal claim record %s failed: %v", it.Key(), err)
+			continue
+		}
+		records = append(records, record)
+		last = append(last[:0], it.Key()...)
+	}
+	return records, "", nil
+}
+
+// construRecentKey 生成按时间倒序排列的领取记录 key，address 为空时生成用于遍历的前缀
+func (c *Client) construRecentKey(net string, address string, sendTime int64) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("recent-")
+	if address == "" {
+		return persist.CompositeKey(net, buffer)
+	}
+	buffer.WriteString(fmt.Sprintf("%020d", math.MaxInt64-sendTime))
+	buffer.WriteString("-")
+	buffer.WriteString(address)
+	return persist.CompositeKey(net, buffer)
+}
+
+// construHistoryKey 生成历史记录 key，sendTxTime 为 0 时生成用于遍历的前缀
+func (c *Client) construHistoryKey(net string, address string, sendTxTime int64) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("history-")
+	if address == "" {
+		return persist.CompositeKey(net, buffer)
+	}
+	buffer.WriteString(address)
+	buffer.WriteString("-")
+	if sendTxTime != 0 {
+		buffer.WriteString(fmt.Sprintf("%020d", sendTxTime))
+	}
+	return persist.CompositeKey(net, buffer)
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+// putHistory 写入发送时间从 from 开始的 n 条领取记录
+func putHistory(t *testing.T, c *Client, address string, from int64, n int64) {
+	t.Helper()
+	for i := from; i < from+n; i++ {
+		if err := c.putClaimRecord(c.Config.Axiom.TestNetName, global.NativeToken, address, &AddressData{SendTxTime: i, TxHash: "0x1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestClaimPage(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	net := c.Config.Axiom.TestNetName
+	putHistory(t, c, testRecipient, 1, 5)
+	putHistory(t, c, "0x2222222222222222222222222222222222222222", 1, 3)
+
+	var times []int64
+	var pages int
+	cursor := ""
+	for {
+		records, next, err := c.ClaimPage(net, testRecipient, cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, record := range records {
+			times = append(times, record.SendTxTime)
+		}
+		if next == "" {
+			break
+		}
+		// 分页期间写入的新记录排在后面，不影响已返回的记录
+		if pages == 1 {
+			putHistory(t, c, testRecipient, 6, 1)
+		}
+		cursor = next
+	}
+	if pages != 3 || len(times) != 6 {
+		t.Fatalf("expect 6 records in 3 pages, got %v in %d pages", times, pages)
+	}
+	for i, at := range times {
+		if at != int64(i+1) {
+			t.Fatalf("records should be in time order, got %v", times)
+		}
+	}
+}
+
+// 最后一页恰好取完时不返回游标
+func TestClaimPageExactLastPage(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	putHistory(t, c, testRecipient, 1, 2)
+	records, next, err := c.ClaimPage(c.Config.Axiom.TestNetName, testRecipient, "", 2)
+	if err != nil || len(records) != 2 || next != "" {
+		t.Fatalf("expect 2 records without cursor, got %d %q %v", len(records), next, err)
+	}
+}
+
+func TestClaimPageRejectsInvalidCursor(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	net := c.Config.Axiom.TestNetName
+	other := "0x2222222222222222222222222222222222222222"
+	putHistory(t, c, other, 1, 2)
+	_, otherCursor, err := c.ClaimPage(net, other, "", 1)
+	if err != nil || otherCursor == "" {
+		t.Fatalf("expect a cursor, got %q %v", otherCursor, err)
+	}
+
+	for _, cursor := range []string{"!!!", base64.RawURLEncoding.EncodeToString([]byte("history")), otherCursor} {
+		if _, _, err := c.ClaimPage(net, testRecipient, cursor, 1); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q should be rejected, got %v", cursor, err)
+		}
+	}
+}
+
+// 未确认的领取在发出时写入 pending 记录，确认前不能重复领取，上链后更新为最终状态
+func TestPendingClaimRecordFinished(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetAutoReceipt(false)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if data := c.LastClaim(net, testRecipient); data == nil || data.Status != ReceiptPending {
+		t.Fatalf("expect a pending claim record, got %+v", data)
+	}
+	if _, code, _ := claim(c, ctx, testRecipient, 1); code != global.ReqWithinDayCode {
+		t.Fatalf("pending claim should limit the address, got %d", code)
+	}
+
+	node.Mine(common.HexToHash(txHash), 1)
+	c.GetReceipt(ReceiptID(txHash))
+	data := c.LastClaim(net, testRecipient)
+	if data == nil || data.Status != ReceiptConfirmed || data.BlockNumber == 0 {
+		t.Fatalf("claim record should be confirmed, got %+v", data)
+	}
+	records := c.RecentClaims(net, 10)
+	if len(records) != 1 || records[0].Status != ReceiptConfirmed || records[0].BlockNumber != data.BlockNumber {
+		t.Fatalf("history should keep the final status, got %+v", records)
+	}
+	if c.ldb.Has(c.construPendingKey(net, txHash)) {
+		t.Fatal("pending index should be removed")
+	}
+}
+
+// 交易失败时删除领取记录，地址可以重新领取
+func TestRevertedPendingClaimAllowsRetry(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetAutoReceipt(false)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	node.Mine(common.HexToHash(txHash), 0)
+	if receipt, _ := c.GetReceipt(ReceiptID(txHash)); receipt.Status != ReceiptReverted {
+		t.Fatalf("expect a reverted receipt, got %+v", receipt)
+	}
+	if data := c.LastClaim(net, testRecipient); data != nil {
+		t.Fatalf("claim record of the reverted tx should be removed, got %+v", data)
+	}
+	if records := c.RecentClaims(net, 10); len(records) != 1 || records[0].Status != ReceiptReverted {
+		t.Fatalf("history should record the reverted tx, got %+v", records)
+	}
+	node.SetAutoReceipt(true)
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("address should be able to claim again, got %d %v", code, err)
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// erc721ABI 只包含持有校验用到的 ownerOf，balanceOf 与 ERC-20 共用
+const erc721ABI = `[
+	{"constant":true,"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}
+]`
+
+// checkHolding 活动配置了持有要求时，要求领取地址持有指定合约的代币或 NFT
+func (c *Client) checkHolding(ctx context.Context, source string, address string) (int, error) {
+	policy, ok := c.holdingPolicyOf(source)
+	if !ok {
+		return global.SUCCESS, nil
+	}
+	holder, err := c.isHolder(policy, common.HexToAddress(address))
+	if err != nil {
+		c.requestLogger(ctx).Errorf("check holding of %s on %s: %v", address, policy.Contract, err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	if !holder {
+		return global.NotHolderCode, fmt.Errorf(global.NotHolderMsg + policy.Contract)
+	}
+	return global.SUCCESS, nil
+}
+
+func (c *Client) isHolder(policy repo.HoldingPolicy, address common.Address) (bool, error) {
+	contractAddress := common.HexToAddress(policy.Contract)
+	if policy.TokenID != "" {
+		tokenId, ok := new(big.Int).SetString(policy.TokenID, 10)
+		if !ok {
+			return false, fmt.Errorf("invalid token id %s", policy.TokenID)
+		}
+		nftAbi, err := abi.JSON(strings.NewReader(erc721ABI))
+		if err != nil {
+			return false, err
+		}
+		values, err := c.callView(nftAbi, contractAddress, "ownerOf", tokenId)
+		if err != nil {
+			return false, err
+		}
+		owner, ok := values[0].(common.Address)
+		if !ok {
+			return false, fmt.Errorf("unexpected ownerOf output: %v", values[0])
+		}
+		return owner == address, nil
+	}
+
+	minBalance := big.NewInt(1)
+	if policy.MinBalance != "" {
+		if _, ok := minBalance.SetString(policy.MinBalance, 10); !ok {
+			return false, fmt.Errorf("invalid min balance %s", policy.MinBalance)
+		}
+	}
+	values, err := c.callView(c.erc20Abi, contractAddress, "balanceOf", address)
+	if err != nil {
+		return false, err
+	}
+	balance, ok := values[0].(*big.Int)
+	if !ok {
+		return false, fmt.Errorf("unexpected balanceOf output: %v", values[0])
+	}
+	return balance.Cmp(minBalance) >= 0, nil
+}
+
+// callView 调用合约的只读方法并解析返回值
+func (c *Client) callView(contractAbi abi.ABI, contractAddress common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := contractAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.axiomClient.CallContract(context.Background(), ethereum.CallMsg{To: &contractAddress, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return contractAbi.Unpack(method, output)
+}
+
+func (c *Client) holdingPolicyOf(source string) (repo.HoldingPolicy, bool) {
+	if source == "" {
+		return repo.HoldingPolicy{}, false
+	}
+	for _, policy := range c.Config.Campaign.Holdings {
+		if policy.Source == source {
+			return policy, true
+		}
+	}
+	return repo.HoldingPolicy{}, false
+}
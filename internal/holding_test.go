@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testNFT = "0x5555555555555555555555555555555555555555"
+
+// handleHolding 测试节点对 balanceOf 按地址返回 balances 中的余额，对 ownerOf 返回 owner
+func handleHolding(t *testing.T, node *testutil.Node, balances map[string]int64, owner string) {
+	t.Helper()
+	tokenAbi, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nftAbi, err := abi.JSON(strings.NewReader(erc721ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balanceOf, ownerOf := tokenAbi.Methods["balanceOf"], nftAbi.Methods["ownerOf"]
+	node.Handle("eth_call", func(params []json.RawMessage) (any, error) {
+		var call struct {
+			Input hexutil.Bytes `json:"input"`
+			Data  hexutil.Bytes `json:"data"`
+		}
+		if err := json.Unmarshal(params[0], &call); err != nil {
+			return nil, err
+		}
+		input := call.Input
+		if len(input) == 0 {
+			input = call.Data
+		}
+		switch {
+		case bytes.HasPrefix(input, balanceOf.ID):
+			args, err := balanceOf.Inputs.Unpack(input[4:])
+			if err != nil {
+				return nil, err
+			}
+			holder := strings.ToLower(args[0].(common.Address).Hex())
+			output, err := balanceOf.Outputs.Pack(big.NewInt(balances[holder]))
+			return hexutil.Bytes(output), err
+		case bytes.HasPrefix(input, ownerOf.ID):
+			output, err := ownerOf.Outputs.Pack(common.HexToAddress(owner))
+			return hexutil.Bytes(output), err
+		}
+		return hexutil.Bytes{}, nil
+	})
+}
+
+func TestHoldingRequiresMinBalance(t *testing.T) {
+	node := testutil.NewNode(t)
+	other := "0x2222222222222222222222222222222222222222"
+	handleHolding(t, node, map[string]int64{testRecipient: 100, other: 99}, "")
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Campaign.Holdings = []repo.HoldingPolicy{{Source: "holders", Contract: testToken, MinBalance: "100"}}
+	})
+	ctx := context.Background()
+
+	if code, err := c.checkHolding(ctx, "holders", testRecipient); err != nil {
+		t.Fatalf("holder should pass, got %d %v", code, err)
+	}
+	if code, _ := c.checkHolding(ctx, "holders", other); code != global.NotHolderCode {
+		t.Fatalf("expect %d below the min balance, got %d", global.NotHolderCode, code)
+	}
+	// 未配置要求的活动不校验
+	if code, err := c.checkHolding(ctx, "other", other); err != nil {
+		t.Fatalf("campaign without policy should pass, got %d %v", code, err)
+	}
+}
+
+func TestHoldingDefaultMinBalance(t *testing.T) {
+	node := testutil.NewNode(t)
+	handleHolding(t, node, map[string]int64{testRecipient: 1}, "")
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Campaign.Holdings = []repo.HoldingPolicy{{Source: "holders", Contract: testToken}}
+	})
+
+	if code, err := c.checkHolding(context.Background(), "holders", testRecipient); err != nil {
+		t.Fatalf("any balance should pass by default, got %d %v", code, err)
+	}
+	if code, _ := c.checkHolding(context.Background(), "holders", "0x2222222222222222222222222222222222222222"); code != global.NotHolderCode {
+		t.Fatalf("expect %d without balance, got %d", global.NotHolderCode, code)
+	}
+}
+
+func TestHoldingChecksNFTOwner(t *testing.T) {
+	node := testutil.NewNode(t)
+	handleHolding(t, node, nil, testRecipient)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Campaign.Holdings = []repo.HoldingPolicy{{Source: "nft", Contract: testNFT, TokenID: "7"}}
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	if _, code, err := c.SendTra(ctx, net, testRecipient, 1, 0, "", "nft"); err != nil {
+		t.Fatalf("nft owner claim failed: %d %v", code, err)
+	}
+	other := "0x2222222222222222222222222222222222222222"
+	if _, code, _ := c.SendTra(ctx, net, other, 1, 0, "", "nft"); code != global.NotHolderCode {
+		t.Fatalf("expect %d for a non-owner, got %d", global.NotHolderCode, code)
+	}
+}
+
+func TestHoldingRejectsInvalidPolicy(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Campaign.Holdings = []repo.HoldingPolicy{{Source: "nft", Contract: testNFT, TokenID: "not a number"}}
+	})
+
+	if code, _ := c.checkHolding(context.Background(), "nft", testRecipient); code != global.BlockChainCode {
+		t.Fatalf("expect %d for an invalid policy, got %d", global.BlockChainCode, code)
+	}
+}
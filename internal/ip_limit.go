@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/utils"
+	"github.com/axiomesh/faucet/persist"
+)
+
+// checkIPAddressLimit 限制单个 IP 累计领取过的不同地址数量，已记录过的地址可以继续领取。
+// 预占时即记录地址，失败的领取同样计入，避免通过反复尝试新地址绕过限制
+func (c *Client) checkIPAddressLimit(ctx context.Context, net string, address string) error {
+	limit := c.Config.Axiom.MaxAddressesPerIP
+	if limit <= 0 || c.Config.DevMode {
+		return nil
+	}
+	ip, ok := c.clientIPBucket(ctx)
+	if !ok {
+		return nil
+	}
+
+	c.preLockCheck.Lock()
+	defer c.preLockCheck.Unlock()
+	addressKey := c.construIPAddressKey(net, ip, address)
+	if c.ldb.Has(addressKey) {
+		return nil
+	}
+	countKey := c.construIPCountKey(net, ip)
+	count := 0
+	if value := c.ldb.Get(countKey); value != nil {
+		var err error
+		if count, err = strconv.Atoi(string(value)); err != nil {
+			return fmt.Errorf("parse address count of %s: %w", ip, err)
+		}
+	}
+	if count >= limit {
+		c.requestLogger(ctx).Warnf("ip %s has claimed for %d addresses, reject %s", ip, count, address)
+		return fmt.Errorf(global.IPAddressLimitMsg)
+	}
+
+	batch := c.ldb.NewBatch()
+	batch.Put(addressKey, []byte("1"))
+	batch.Put(countKey, []byte(strconv.Itoa(count+1)))
+	batch.Commit()
+	return nil
+}
+
+// clientIPBucket ctx 中请求的客户端 IP 按配置前缀归并后的网段，用于 IP 限制的存储 key，
+// 没有请求信息（如管理后台内部调用）时返回 false
+func (c *Client) clientIPBucket(ctx context.Context) (string, bool) {
+	ip := RequestFrom(ctx).IP
+	if ip == "" {
+		return "", false
+	}
+	return c.ipBucket(ip), true
+}
+
+func (c *Client) ipBucket(ip string) string {
+	return utils.IPBucket(ip, c.Config.Network.IPv4Prefix, c.Config.Network.IPv6Prefix)
+}
+
+func (c *Client) construIPAddressKey(net string, ip string, address string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("ipaddr-")
+	buffer.WriteString(ip)
+	buffer.WriteString("-")
+	buffer.WriteString(address)
+	return persist.CompositeKey(net, buffer)
+}
+
+func (c *Client) construIPCountKey(net string, ip string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("ipcount-")
+	buffer.WriteString(ip)
+	return persist.CompositeKey(net, buffer)
+}
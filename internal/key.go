@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// knownTestKeys 开发工具内置的公开测试私钥（hardhat/anvil、web3 文档示例等），任何人都能使用
+var knownTestKeys = map[string]struct{}{
+	"ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80": {},
+	"59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d": {},
+	"5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a": {},
+	"7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6": {},
+	"47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a": {},
+	"b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291": {},
+	"4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318": {},
+}
+
+// 私钥数值过小说明是手工填写的占位值
+var minSafeKey = new(big.Int).Lsh(big.NewInt(1), 64)
+
+// checkFundingKey 拒绝为空或众所周知的测试私钥，allowInsecure 仅用于本地开发
+func checkFundingKey(private string, allowInsecure bool) error {
+	private = strings.TrimPrefix(strings.ToLower(private), "0x")
+	if private == "" {
+		return errors.New("funding key is empty")
+	}
+	if allowInsecure {
+		return nil
+	}
+	if _, ok := knownTestKeys[private]; ok {
+		return errors.New("funding key is a well-known test key, set axiom.allow_insecure_key to use it for local dev")
+	}
+	keyBytes, err := hex.DecodeString(private)
+	if err != nil {
+		return fmt.Errorf("Error decoding private key hex: %w", err)
+	}
+	if new(big.Int).SetBytes(keyBytes).Cmp(minSafeKey) < 0 {
+		return errors.New("funding key looks like a placeholder, set axiom.allow_insecure_key to use it for local dev")
+	}
+	return nil
+}
+
+// parseFundingKey 校验并解析十六进制私钥
+func parseFundingKey(private string, allowInsecure bool) (*ecdsa.PrivateKey, error) {
+	private = strings.TrimPrefix(strings.TrimSpace(private), "0x")
+	if err := checkFundingKey(private, allowInsecure); err != nil {
+		return nil, err
+	}
+	privateKeyBytes, err := hex.DecodeString(private)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding private key hex: %w", err)
+	}
+	privateKey, err := crypto.ToECDSA(privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("Error converting to ECDSA private key: %w", err)
+	}
+	return privateKey, nil
+}
+
+// loadFundingKey 读取并解析资金账户私钥，配置了 axiom_key 时不再读取私钥文件
+func loadFundingKey(cfg *repo.Config, configPath string) (*ecdsa.PrivateKey, error) {
+	private := cfg.Axiom.AxiomKey
+	if private == "" {
+		raw, err := os.ReadFile(filepath.Join(configPath, cfg.Axiom.AxiomKeyPath))
+		if err != nil {
+			return nil, err
+		}
+		private = string(raw)
+	}
+	return parseFundingKey(private, cfg.Axiom.AllowInsecureKey)
+}
+
+// FundingReady 资金账户私钥是否可用
+func (c *Client) FundingReady() bool {
+	return atomic.LoadInt32(&c.fundingReady) == 1
+}
+
+// FundingAddress 当前用于发送领取交易的资金账户地址，私钥不可用时为空
+func (c *Client) FundingAddress() string {
+	address, _ := c.fundingAddress.Load().(string)
+	return address
+}
+
+// RotateFundingKey 运行时替换资金账户私钥。持有 axiomLock 切换，
+// 正在发送的交易仍使用旧私钥完成，之后的领取从新账户的 pending nonce 开始发送
+func (c *Client) RotateFundingKey(private string) (common.Address, int, error) {
+	privateKey, err := parseFundingKey(private, c.Config.Axiom.AllowInsecureKey)
+	if err != nil {
+		return common.Address{}, global.InvalidKeyCode, fmt.Errorf(global.InvalidKeyMsg + err.Error())
+	}
+	auth := bind.NewKeyedTransactor(privateKey)
+
+	c.axiomLock.Lock()
+	defer c.axiomLock.Unlock()
+	nonce, err := c.axiomClient.PendingNonceAt(context.Background(), auth.From)
+	if err != nil {
+		c.logger.Errorf("query pending nonce of %s: %v", auth.From, err)
+		return common.Address{}, global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	var previous common.Address
+	if c.axiomAuth != nil {
+		previous = c.axiomAuth.From
+	}
+	c.axiomPrivateKey = privateKey
+	c.axiomAuth = auth
+	// 新账户从自己的 pending nonce 开始，旧账户的 nonce 缺口不再影响领取
+	c.nextNonce = 0
+	atomic.StoreInt32(&c.nonceGap, 0)
+	atomic.StoreInt32(&c.fundingReady, 1)
+	c.fundingAddress.Store(auth.From.Hex())
+	c.logger.Infof("funding address rotated from %s to %s, pending nonce: %d", previous.Hex(), auth.From.Hex(), nonce)
+	return auth.From, global.SUCCESS, nil
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestCheckFundingKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := hex.EncodeToString(crypto.FromECDSA(key))
+	for _, tc := range []struct {
+		name          string
+		key           string
+		allowInsecure bool
+		valid         bool
+	}{
+		{"random", random, false, true},
+		{"random with prefix", "0x" + random, false, true},
+		{"empty", "", false, false},
+		{"empty insecure", "", true, false},
+		{"hardhat", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", false, false},
+		{"hardhat upper case", "AC0974BEC39A17E36BA4A6B4D238FF944BACB478CBED5EFCAE784D7BF4F2FF80", false, false},
+		{"hardhat insecure", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", true, true},
+		{"placeholder", "0000000000000000000000000000000000000000000000000000000000000001", false, false},
+		{"placeholder insecure", "0000000000000000000000000000000000000000000000000000000000000001", true, true},
+		{"not hex", "zz", false, false},
+	} {
+		if err := checkFundingKey(tc.key, tc.allowInsecure); (err == nil) != tc.valid {
+			t.Errorf("%s: expect valid=%v, got %v", tc.name, tc.valid, err)
+		}
+	}
+}
+
+func TestInitializeRefusesWellKnownKeyInStrictMode(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.AxiomKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	cfg.Axiom.StrictKey = true
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("well-known funding key should be refused")
+	}
+}
+
+func TestRotateFundingKey(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newAddress := crypto.PubkeyToAddress(key.PublicKey)
+	node.SetNonce(newAddress.Hex(), 5)
+
+	address, code, err := c.RotateFundingKey("0x" + hex.EncodeToString(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatalf("rotate failed: %d %v", code, err)
+	}
+	if address != newAddress || c.FundingAddress() != newAddress.Hex() {
+		t.Fatalf("expect funding address %s, got %s", newAddress, c.FundingAddress())
+	}
+	// 之后的领取由新账户从其 pending nonce 开始发送
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	sent := node.Sent()
+	if len(sent) != 1 || txSender(sent[0]) != newAddress.Hex() || sent[0].Nonce() != 5 {
+		t.Fatalf("claim should be sent by the new key from nonce 5, got %d txs", len(sent))
+	}
+}
+
+func TestRotateFundingKeyRejectsInvalidKey(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	previous := c.FundingAddress()
+
+	for _, key := range []string{"", "zz", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"} {
+		if _, code, _ := c.RotateFundingKey(key); code != global.InvalidKeyCode {
+			t.Fatalf("%q: expect %d, got %d", key, global.InvalidKeyCode, code)
+		}
+	}
+	if c.FundingAddress() != previous {
+		t.Fatal("funding address should be kept after a rejected rotation")
+	}
+}
+
+// 私钥无效时只停用领取，轮换为有效私钥后恢复
+func TestInvalidFundingKeyDisablesClaims(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.AxiomKey = "not a key"
+	})
+	if c.FundingReady() {
+		t.Fatal("invalid key should not be ready")
+	}
+	if _, code, _ := claim(c, context.Background(), testRecipient, 1); code != global.NetDisabledCode {
+		t.Fatalf("expect %d, got %d", global.NetDisabledCode, code)
+	}
+	if err := c.SelfTest(); err == nil {
+		t.Fatal("self-test should fail without a funding key")
+	}
+	if len(node.Sent()) != 0 {
+		t.Fatal("no tx should be sent without a funding key")
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.SetBalance(crypto.PubkeyToAddress(key.PublicKey).Hex(), 1000)
+	if _, code, err := c.RotateFundingKey(hex.EncodeToString(crypto.FromECDSA(key))); err != nil {
+		t.Fatalf("rotate failed: %d %v", code, err)
+	}
+	if !c.FundingReady() {
+		t.Fatal("rotated key should enable claims")
+	}
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim after rotation failed: %d %v", code, err)
+	}
+}
+
+func TestInitializeRefusesInvalidKeyInStrictMode(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.AxiomKey = "not a key"
+	cfg.Axiom.StrictKey = true
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("invalid funding key should be refused in strict mode")
+	}
+}
+
+// 领取记录保存实际发送交易的资金账户，轮换后状态返回新地址
+func TestClaimRecordsFundingAddress(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	previous := c.FundingAddress()
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, code, err := c.RotateFundingKey(hex.EncodeToString(crypto.FromECDSA(key))); err != nil {
+		t.Fatalf("rotate failed: %d %v", code, err)
+	}
+	if data := c.LastClaim(c.Config.Axiom.TestNetName, testRecipient); data == nil || data.From != previous {
+		t.Fatalf("claim should record sender %s, got %+v", previous, data)
+	}
+	status, err := c.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey).Hex(); status.FundingAddress != want {
+		t.Fatalf("status should report the rotated address %s, got %s", want, status.FundingAddress)
+	}
+}
@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// chainLag 节点最新区块时间与当前时间的差值
+func chainLag(header *types.Header) time.Duration {
+	lag := time.Since(time.Unix(int64(header.Time), 0))
+	if lag < 0 {
+		return 0
+	}
+	return lag
+}
+
+// checkChainLag 节点落后超过 MaxChainLag 时拒绝领取，避免基于过期状态发送交易
+func (c *Client) checkChainLag() (int, error) {
+	maxLag := c.Config.Axiom.MaxChainLag.ToDuration()
+	if maxLag <= 0 {
+		return global.SUCCESS, nil
+	}
+	header, err := c.axiomClient.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		c.logger.Error(err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	if lag := chainLag(header); lag > maxLag {
+		c.logger.Warnf("node of %s lags %s behind, latest block: %d", c.Config.Axiom.TestNetName, lag, header.Number)
+		return global.NodeSyncingCode, fmt.Errorf(global.NodeSyncingMsg)
+	}
+	return global.SUCCESS, nil
+}
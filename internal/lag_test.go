@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestClaimRefusedWhileNodeLags(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.MaxChainLag = repo.Duration(time.Minute)
+	})
+	ctx := context.Background()
+
+	node.SetBlock(100, time.Now().Add(-10*time.Minute), big.NewInt(1e9))
+	if _, code, err := claim(c, ctx, testRecipient, 1); code != global.NodeSyncingCode {
+		t.Fatalf("claim should be refused with %d while the node lags, got %d %v", global.NodeSyncingCode, code, err)
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent, got %d", len(sent))
+	}
+
+	node.SetBlock(160, time.Now(), big.NewInt(1e9))
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("claim should pass once the node catches up, got %d %v", code, err)
+	}
+}
+
+func TestChainLagCheckDisabled(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.MaxChainLag = 0
+	})
+	node.SetBlock(100, time.Now().Add(-time.Hour), big.NewInt(1e9))
+
+	if code, err := c.checkChainLag(); err != nil {
+		t.Fatalf("lag check should be skipped when disabled, got %d %v", code, err)
+	}
+}
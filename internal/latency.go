@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyHistogram 按上界（秒）分桶累计的耗时分布，counts[i] 为不超过 bounds[i] 的次数，与 Prometheus histogram 一致
+type latencyHistogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newLatencyHistogram(bounds []float64) *latencyHistogram {
+	sorted := make([]float64, len(bounds))
+	copy(sorted, bounds)
+	sort.Float64s(sorted)
+	return &latencyHistogram{bounds: sorted, counts: make([]uint64, len(sorted))}
+}
+
+func (h *latencyHistogram) observe(seconds float64) {
+	for i, bound := range h.bounds {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// LatencyHistogram 耗时分布的快照
+type LatencyHistogram struct {
+	Bounds []float64
+	Counts []uint64
+	Sum    float64
+	Count  uint64
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	return LatencyHistogram{
+		Bounds: append([]float64(nil), h.bounds...),
+		Counts: append([]uint64(nil), h.counts...),
+		Sum:    h.sum,
+		Count:  h.count,
+	}
+}
+
+// ClaimLatency 领取耗时统计：Submit 为收到领取到交易发出的耗时，Confirm 为交易发出到查到成功回执的耗时；
+// SLOTotal 为已发出的领取数，SLOGood 为在 claim_slo 内确认的领取数，Unconfirmed 为未能及时查到成功回执的领取数
+type ClaimLatency struct {
+	Submit      LatencyHistogram
+	Confirm     LatencyHistogram
+	SLO         time.Duration
+	SLOTotal    uint64
+	SLOGood     uint64
+	Unconfirmed uint64
+}
+
+type claimLatency struct {
+	lock        sync.Mutex
+	slo         time.Duration
+	submit      *latencyHistogram
+	confirm     *latencyHistogram
+	sloTotal    uint64
+	sloGood     uint64
+	unconfirmed uint64
+}
+
+func newClaimLatency(bounds []float64, slo time.Duration) *claimLatency {
+	return &claimLatency{slo: slo, submit: newLatencyHistogram(bounds), confirm: newLatencyHistogram(bounds)}
+}
+
+func (l *claimLatency) observeSubmit(d time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.submit.observe(d.Seconds())
+}
+
+// observeConfirm 记录一笔已发出领取的确认结果，未确认的领取计入 SLO 总数但不计入达标数
+func (l *claimLatency) observeConfirm(d time.Duration, confirmed bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.sloTotal++
+	if !confirmed {
+		l.unconfirmed++
+		return
+	}
+	l.confirm.observe(d.Seconds())
+	if d <= l.slo {
+		l.sloGood++
+	}
+}
+
+// ClaimLatency 返回领取耗时统计的快照
+func (c *Client) ClaimLatency() *ClaimLatency {
+	l := c.latency
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return &ClaimLatency{
+		Submit:      l.submit.snapshot(),
+		Confirm:     l.confirm.snapshot(),
+		SLO:         l.slo,
+		SLOTotal:    l.sloTotal,
+		SLOGood:     l.sloGood,
+		Unconfirmed: l.unconfirmed,
+	}
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+// 分桶按上界累计，与配置的顺序无关
+func TestLatencyHistogram(t *testing.T) {
+	h := newLatencyHistogram([]float64{5, 1, 2})
+	for _, seconds := range []float64{0.5, 1.5, 3, 10} {
+		h.observe(seconds)
+	}
+	got := h.snapshot()
+	want := LatencyHistogram{Bounds: []float64{1, 2, 5}, Counts: []uint64{1, 2, 3}, Sum: 15, Count: 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expect %+v, got %+v", want, got)
+	}
+}
+
+func TestClaimLatencySLO(t *testing.T) {
+	c := &Client{latency: newClaimLatency([]float64{1, 5}, 2*time.Second)}
+	c.latency.observeConfirm(time.Second, true)
+	c.latency.observeConfirm(3*time.Second, true)
+	c.latency.observeConfirm(time.Second, false)
+
+	latency := c.ClaimLatency()
+	if latency.SLOTotal != 3 || latency.SLOGood != 1 || latency.Unconfirmed != 1 {
+		t.Fatalf("expect 1 of 3 claims within the slo and 1 unconfirmed, got %+v", latency)
+	}
+	// 未确认的领取不计入确认耗时
+	if latency.Confirm.Count != 2 {
+		t.Fatalf("expect 2 confirmed claims in the histogram, got %d", latency.Confirm.Count)
+	}
+}
+
+func TestClaimObservesLatency(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	latency := c.ClaimLatency()
+	if latency.Submit.Count != 1 || latency.Confirm.Count != 1 || latency.SLOTotal != 1 || latency.SLOGood != 1 {
+		t.Fatalf("expect one claim observed within the slo, got %+v", latency)
+	}
+}
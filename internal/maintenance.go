@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maintenanceWindow 每日维护时间段，单位为当天的分钟数，end 小于 start 表示跨越零点；weekday 为空表示每天
+type maintenanceWindow struct {
+	weekday *time.Weekday
+	start   int
+	end     int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseMaintenanceWindows 解析 "02:00-03:00" 或 "Sat 22:00-02:00" 格式的维护时间段
+func parseMaintenanceWindows(windows []string) ([]maintenanceWindow, error) {
+	result := make([]maintenanceWindow, 0, len(windows))
+	for _, raw := range windows {
+		window := maintenanceWindow{}
+		fields := strings.Fields(raw)
+		if len(fields) == 2 {
+			weekday, ok := weekdays[strings.ToLower(fields[0])]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday of maintenance window %q", raw)
+			}
+			window.weekday = &weekday
+			fields = fields[1:]
+		}
+		if len(fields) != 1 {
+			return nil, fmt.Errorf("invalid maintenance window %q", raw)
+		}
+		bounds := strings.Split(fields[0], "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid maintenance window %q", raw)
+		}
+		start, err := time.Parse("15:04", bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of maintenance window %q: %w", raw, err)
+		}
+		end, err := time.Parse("15:04", bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of maintenance window %q: %w", raw, err)
+		}
+		window.start = start.Hour()*60 + start.Minute()
+		window.end = end.Hour()*60 + end.Minute()
+		if window.start == window.end {
+			return nil, fmt.Errorf("empty maintenance window %q", raw)
+		}
+		result = append(result, window)
+	}
+	return result, nil
+}
+
+func (w *maintenanceWindow) onDay(weekday time.Weekday) bool {
+	return w.weekday == nil || *w.weekday == weekday
+}
+
+// contains 判断 t 是否处于维护时间段内，并返回维护结束时间
+func (w *maintenanceWindow) contains(t time.Time) (bool, time.Time) {
+	minute := t.Hour()*60 + t.Minute()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	endOf := func(d time.Time) time.Time {
+		return d.Add(time.Duration(w.end) * time.Minute)
+	}
+	if w.start < w.end {
+		if w.onDay(t.Weekday()) && minute >= w.start && minute < w.end {
+			return true, endOf(day)
+		}
+		return false, time.Time{}
+	}
+	if w.onDay(t.Weekday()) && minute >= w.start {
+		return true, endOf(day.AddDate(0, 0, 1))
+	}
+	if w.onDay((t.Weekday()+6)%7) && minute < w.end {
+		return true, endOf(day)
+	}
+	return false, time.Time{}
+}
+
+// InMaintenance 判断 now 是否处于配置的维护时间段，返回维护结束时间；
+// 首尾相接或重叠的时间段视为一次连续维护，返回最后一段的结束时间
+func (c *Client) InMaintenance(now time.Time) (bool, time.Time) {
+	end, ok := c.maintenanceEnd(now.In(c.maintenanceLocation))
+	if !ok {
+		return false, time.Time{}
+	}
+	// 每周的维护时间段不会超过 7 * len(windows) 段，避免全天维护时无限循环
+	for i := 0; i < 7*len(c.maintenanceWindows); i++ {
+		next, ok := c.maintenanceEnd(end)
+		if !ok {
+			break
+		}
+		end = next
+	}
+	return true, end
+}
+
+// maintenanceEnd 返回包含 t 的维护时间段中最晚的结束时间
+func (c *Client) maintenanceEnd(t time.Time) (time.Time, bool) {
+	var end time.Time
+	for _, window := range c.maintenanceWindows {
+		if ok, windowEnd := window.contains(t); ok && windowEnd.After(end) {
+			end = windowEnd
+		}
+	}
+	return end, !end.IsZero()
+}
+
+func (c *Client) initMaintenance() error {
+	location, err := time.LoadLocation(c.Config.Maintenance.Timezone)
+	if err != nil {
+		return fmt.Errorf("load maintenance timezone: %w", err)
+	}
+	windows, err := parseMaintenanceWindows(c.Config.Maintenance.Windows)
+	if err != nil {
+		return err
+	}
+	c.maintenanceLocation = location
+	c.maintenanceWindows = windows
+	return nil
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func newMaintenanceClient(t *testing.T, windows ...string) *Client {
+	t.Helper()
+	parsed, err := parseMaintenanceWindows(windows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{maintenanceLocation: time.UTC, maintenanceWindows: parsed}
+}
+
+// 2026-10-17 为周六
+func maintenanceTime(day int, hour int, minute int) time.Time {
+	return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseMaintenanceWindowsRejectsInvalid(t *testing.T) {
+	for _, raw := range []string{"02:00", "02:00-25:00", "Someday 02:00-03:00", "Sat 02:00 03:00", "02:00-02:00"} {
+		if _, err := parseMaintenanceWindows([]string{raw}); err == nil {
+			t.Fatalf("window %q should be rejected", raw)
+		}
+	}
+}
+
+func TestInMaintenanceDailyWindow(t *testing.T) {
+	c := newMaintenanceClient(t, "02:00-03:00")
+
+	paused, until := c.InMaintenance(maintenanceTime(15, 2, 30))
+	if !paused || !until.Equal(maintenanceTime(15, 3, 0)) {
+		t.Fatalf("expect paused until 03:00, got %v %v", paused, until)
+	}
+	if paused, _ := c.InMaintenance(maintenanceTime(15, 3, 0)); paused {
+		t.Fatal("window end should not be in maintenance")
+	}
+}
+
+func TestInMaintenanceOvernightWeeklyWindow(t *testing.T) {
+	c := newMaintenanceClient(t, "Sat 22:00-02:00")
+
+	paused, until := c.InMaintenance(maintenanceTime(17, 23, 0))
+	if !paused || !until.Equal(maintenanceTime(18, 2, 0)) {
+		t.Fatalf("expect paused until Sunday 02:00, got %v %v", paused, until)
+	}
+	if paused, until := c.InMaintenance(maintenanceTime(18, 1, 0)); !paused || !until.Equal(maintenanceTime(18, 2, 0)) {
+		t.Fatalf("expect Sunday 01:00 paused until 02:00, got %v %v", paused, until)
+	}
+	if paused, _ := c.InMaintenance(maintenanceTime(19, 1, 0)); paused {
+		t.Fatal("Monday 01:00 should not be in maintenance")
+	}
+}
+
+// 首尾相接的时间段视为一次维护，结束时间为最后一段的结束
+func TestInMaintenanceContiguousWindows(t *testing.T) {
+	c := newMaintenanceClient(t, "22:00-23:00", "23:00-01:00", "00:30-02:00")
+
+	paused, until := c.InMaintenance(maintenanceTime(15, 22, 30))
+	if !paused || !until.Equal(maintenanceTime(16, 2, 0)) {
+		t.Fatalf("expect paused until 02:00 next day, got %v %v", paused, until)
+	}
+}
+
+func TestInMaintenanceAllDayTerminates(t *testing.T) {
+	c := newMaintenanceClient(t, "00:00-12:00", "12:00-00:00")
+
+	if paused, until := c.InMaintenance(maintenanceTime(15, 8, 0)); !paused || !until.After(maintenanceTime(15, 8, 0)) {
+		t.Fatalf("expect all-day maintenance to be paused with a future end, got %v %v", paused, until)
+	}
+}
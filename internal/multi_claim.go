@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/audit"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// TokenClaimResult 多币种领取中单个代币的结果，原生代币的 token 为 native
+type TokenClaimResult struct {
+	Token  string `json:"token"`
+	TxHash string `json:"txHash,omitempty"`
+	Code   int    `json:"code"`
+	Msg    string `json:"msg"`
+}
+
+// MultiClaim 依次发放原生代币以及配置的全部 ERC-20 代币，每个(地址, 代币)单独计算每日限制，
+// 准入校验失败时全部代币都不发放，之后某个代币失败不影响其他代币
+func (c *Client) MultiClaim(ctx context.Context, net string, address string, amount float64, referralBonus float64, source string) []*TokenClaimResult {
+	lowerAddress := strings.ToLower(address)
+	results := make([]*TokenClaimResult, 0, len(c.Config.Axiom.Tokens)+1)
+
+	if code, err := c.checkClaimGates(ctx, net, address); err != nil {
+		results = append(results, newTokenClaimResult(global.NativeToken, "", code, err))
+		for _, token := range c.Config.Axiom.Tokens {
+			results = append(results, newTokenClaimResult(token.Name, "", code, err))
+		}
+		return results
+	}
+
+	var txHash string
+	code, err := c.reserveNativeClaim(ctx, net, address)
+	if err == nil {
+		txHash, code, err = c.processClaim(ctx, net, address, amount, referralBonus, "", source, false)
+	}
+	if !errors.Is(err, ErrAddressLocked) {
+		DeleteTxData(c, lowerAddress, global.NativeToken, net)
+	}
+	results = append(results, newTokenClaimResult(global.NativeToken, txHash, code, err))
+
+	for _, token := range c.Config.Axiom.Tokens {
+		txHash, code, err := c.claimToken(ctx, net, address, token, source)
+		results = append(results, newTokenClaimResult(token.Name, txHash, code, err))
+	}
+	return results
+}
+
+func (c *Client) claimToken(ctx context.Context, net string, address string, token repo.Token, source string) (string, int, error) {
+	lowerAddress := strings.ToLower(address)
+	typ := strings.ToLower(token.Address)
+	if err := c.checkLimit(ctx, net, typ, lowerAddress, c.ldb); err != nil {
+		if errors.Is(err, ErrAddressLocked) {
+			return "", global.AddrPreLockErrCode, err
+		}
+		return "", global.ReqWithinDayCode, err
+	}
+	defer DeleteTxData(c, lowerAddress, typ, net)
+	if code, err := c.checkClaimQuota(ctx, net, lowerAddress); err != nil {
+		return "", code, err
+	}
+	if code, err := c.checkRisk(ctx, net, lowerAddress); err != nil {
+		return "", code, err
+	}
+
+	value, err := c.tokenAmount(token.Address, token.Amount)
+	if err != nil {
+		return "", global.CommonErrCode, err
+	}
+	tx, err := sendTxToken(ctx, c, address, token.Address, value)
+	if err != nil {
+		c.recordError(err)
+		if err.Error() == global.RecoveringMsg {
+			return "", global.RecoveringCode, err
+		}
+		return "", global.CommonErrCode, fmt.Errorf("%s-%s %s", "Axiomledger", c.Config.Axiom.TestNetName, "Network Error，Please Try Again Later！")
+	}
+	txHash := tx.Hash().Hex()
+	if receipt, ok := checkTxSuccess(c, txHash); ok {
+		data := &AddressData{
+			SendTxTime:  time.Now().Unix(),
+			TxHash:      txHash,
+			Amount:      token.Amount,
+			Source:      source,
+			Nonce:       tx.Nonce(),
+			Status:      ReceiptConfirmed,
+			BlockNumber: receiptBlock(receipt),
+		}
+		c.storeClaimData(ctx, net, typ, lowerAddress, data)
+		c.Audit(ctx, &audit.Event{Type: audit.EventClaim, Net: net, Address: lowerAddress, Token: token.Name, TxHash: txHash, Amount: token.Amount})
+	} else {
+		if c.putUnconfirmedReceipt(ctx, net, typ, lowerAddress, tx, token.Amount) == ReceiptPending {
+			c.storeClaimData(ctx, net, typ, lowerAddress, &AddressData{
+				SendTxTime: time.Now().Unix(),
+				TxHash:     txHash,
+				Amount:     token.Amount,
+				Source:     source,
+				Nonce:      tx.Nonce(),
+				Status:     ReceiptPending,
+			})
+		}
+		c.trackStuckTx(ctx, tx)
+	}
+	return txHash, global.SUCCESS, nil
+}
+
+func newTokenClaimResult(token string, txHash string, code int, err error) *TokenClaimResult {
+	result := &TokenClaimResult{
+		Token:  token,
+		TxHash: txHash,
+		Code:   code,
+		Msg:    global.SUCCESSMsg,
+	}
+	if err != nil {
+		result.Msg = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newMultiClaimClient(t *testing.T, node *testutil.Node) *Client {
+	t.Helper()
+	handleDecimals(t, node, map[string]uint8{testToken: 6})
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.Tokens = []repo.Token{{Name: "USDT", Address: testToken, Amount: 5}}
+	})
+}
+
+func TestMultiClaimSendsNativeAndTokens(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newMultiClaimClient(t, node)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	results := c.MultiClaim(ctx, net, testRecipient, 1, 0, "")
+	if len(results) != 2 || results[0].Token != global.NativeToken || results[1].Token != "USDT" {
+		t.Fatalf("expect results of native and USDT, got %+v", results)
+	}
+	for _, result := range results {
+		if result.Code != global.SUCCESS || result.TxHash == "" {
+			t.Fatalf("%s claim failed: %d %s", result.Token, result.Code, result.Msg)
+		}
+	}
+	sent := node.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("expect 2 txs, got %d", len(sent))
+	}
+	if to := strings.ToLower(sent[1].To().Hex()); to != testToken {
+		t.Fatalf("token tx should call the token contract, got %s", to)
+	}
+
+	// 每个代币单独计算每日限制
+	for _, result := range c.MultiClaim(ctx, net, testRecipient, 1, 0, "") {
+		if result.Code != global.ReqWithinDayCode {
+			t.Fatalf("%s: second claim should be limited, got %d %s", result.Token, result.Code, result.Msg)
+		}
+	}
+	if sent := node.Sent(); len(sent) != 2 {
+		t.Fatalf("limited claims should not send txs, got %d", len(sent))
+	}
+}
+
+// 准入校验失败时全部代币都不发放
+func TestMultiClaimGateFailureSkipsAllTokens(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newMultiClaimClient(t, node)
+	net := c.Config.Axiom.TestNetName
+	if _, err := c.SetAddressNote(net, testRecipient, "", FlagBlocked); err != nil {
+		t.Fatal(err)
+	}
+
+	results := c.MultiClaim(context.Background(), net, testRecipient, 1, 0, "")
+	if len(results) != 2 {
+		t.Fatalf("expect a result for each token, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Code != global.AddrBlockedCode {
+			t.Fatalf("%s: expect %d, got %d", result.Token, global.AddrBlockedCode, result.Code)
+		}
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent, got %d", len(sent))
+	}
+}
+
+func TestMultiClaimRejectsFundingAddress(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newMultiClaimClient(t, node)
+
+	for _, result := range c.MultiClaim(context.Background(), c.Config.Axiom.TestNetName, c.FundingAddress(), 1, 0, "") {
+		if result.Code != global.SelfAddressCode {
+			t.Fatalf("%s: expect %d, got %d", result.Token, global.SelfAddressCode, result.Code)
+		}
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent, got %d", len(sent))
+	}
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// checkNonceGap 比较节点返回的 pending nonce 与本地记录的下一个 nonce，调用方需持有 axiomLock。
+// pending nonce 小于本地记录说明已发出的交易从交易池中消失，之后的交易会排在缺口之后无法上链：
+// reuse 策略直接从 pending nonce 继续发送以填补缺口；refuse 策略拒绝领取，直到缺口被填补
+func (c *Client) checkNonceGap(pending uint64) error {
+	if c.nextNonce == 0 || pending >= c.nextNonce {
+		if atomic.CompareAndSwapInt32(&c.nonceGap, 1, 0) {
+			c.logger.Infof("nonce gap of %s resolved, pending nonce: %d", c.Config.Axiom.TestNetName, pending)
+		}
+		return nil
+	}
+	if c.Config.Axiom.NonceGapPolicy == repo.NonceGapPolicyRefuse {
+		if atomic.CompareAndSwapInt32(&c.nonceGap, 0, 1) {
+			c.logger.Warnf("nonce gap of %s detected, pending nonce %d behind expected %d, refusing claims until it resolves", c.Config.Axiom.TestNetName, pending, c.nextNonce)
+		}
+		return fmt.Errorf(global.RecoveringMsg)
+	}
+	c.logger.Warnf("nonce gap of %s detected, pending nonce %d behind expected %d, reusing pending nonce", c.Config.Axiom.TestNetName, pending, c.nextNonce)
+	c.nextNonce = pending
+	return nil
+}
+
+// Recovering 检测到 nonce 缺口且按 refuse 策略拒绝领取时为 true
+func (c *Client) Recovering() bool {
+	return atomic.LoadInt32(&c.nonceGap) == 1
+}
+
+// checkRecovering 拒绝领取期间重新查询 pending nonce，缺口被填补（如加价重发的交易重新进入交易池）后恢复领取
+func (c *Client) checkRecovering() (int, error) {
+	if !c.Recovering() {
+		return global.SUCCESS, nil
+	}
+	c.axiomLock.Lock()
+	defer c.axiomLock.Unlock()
+	if c.axiomAuth == nil {
+		return global.NetDisabledCode, fmt.Errorf(global.NetDisabledMsg)
+	}
+	pending, err := c.axiomClient.PendingNonceAt(context.Background(), c.axiomAuth.From)
+	if err != nil {
+		c.logger.Error(err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	if err := c.checkNonceGap(pending); err != nil {
+		return global.RecoveringCode, err
+	}
+	return global.SUCCESS, nil
+}
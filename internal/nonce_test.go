@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const otherRecipient = "0x2222222222222222222222222222222222222222"
+
+// newNonceGapClient 领取一次后把节点的 pending nonce 回退到 0，模拟已发出的交易从交易池中消失
+func newNonceGapClient(t *testing.T, node *testutil.Node, policy string) *Client {
+	t.Helper()
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.NonceGapPolicy = policy
+	})
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	node.SetNonce(c.FundingAddress(), 0)
+	return c
+}
+
+func TestNonceGapReuse(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newNonceGapClient(t, node, repo.NonceGapPolicyReuse)
+
+	if _, code, err := claim(c, context.Background(), otherRecipient, 1); err != nil {
+		t.Fatalf("claim should fill the gap: %d %v", code, err)
+	}
+	sent := node.Sent()
+	if len(sent) != 2 || sent[1].Nonce() != 0 {
+		t.Fatalf("claim should reuse nonce 0, got %d txs", len(sent))
+	}
+	if c.Recovering() {
+		t.Fatal("reuse policy should not refuse claims")
+	}
+}
+
+func TestNonceGapRefuseUntilResolved(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newNonceGapClient(t, node, repo.NonceGapPolicyRefuse)
+
+	if _, code, _ := claim(c, context.Background(), otherRecipient, 1); code != global.RecoveringCode {
+		t.Fatalf("expect %d with a nonce gap, got %d", global.RecoveringCode, code)
+	}
+	if !c.Recovering() || len(node.Sent()) != 1 {
+		t.Fatal("claims should be refused while recovering")
+	}
+	if code, _ := c.PreCheck(context.Background(), c.Config.Axiom.TestNetName, otherRecipient); code != global.RecoveringCode {
+		t.Fatalf("preCheck should report %d while recovering, got %d", global.RecoveringCode, code)
+	}
+
+	// 缺口被填补后恢复领取
+	node.SetNonce(c.FundingAddress(), 1)
+	if _, code, err := claim(c, context.Background(), otherRecipient, 1); err != nil {
+		t.Fatalf("claim should resume after the gap resolves: %d %v", code, err)
+	}
+	if c.Recovering() {
+		t.Fatal("recovering should be cleared")
+	}
+}
+
+func TestInitializeRejectsUnknownNonceGapPolicy(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Axiom.NonceGapPolicy = "skip"
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("unknown nonce gap policy should be rejected")
+	}
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/axiomesh/faucet/persist"
+)
+
+// FlagBlocked 标记为 blocked 的地址拒绝领取
+const FlagBlocked = "blocked"
+
+// AddressNote 管理员对地址的备注与标记
+type AddressNote struct {
+	Address    string `json:"address"`
+	Note       string `json:"note,omitempty"`
+	Flag       string `json:"flag,omitempty"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+func (c *Client) SetAddressNote(net string, address string, note string, flag string) (*AddressNote, error) {
+	addressNote := &AddressNote{
+		Address:    address,
+		Note:       note,
+		Flag:       flag,
+		UpdateTime: time.Now().Unix(),
+	}
+	value, err := json.Marshal(addressNote)
+	if err != nil {
+		return nil, fmt.Errorf("json marshal failed: %w", err)
+	}
+	c.ldb.Put(c.construNoteKey(net, address), value)
+	return addressNote, nil
+}
+
+// GetAddressNote 返回地址的备注，不存在时返回 nil
+func (c *Client) GetAddressNote(net string, address string) *AddressNote {
+	value := c.ldb.Get(c.construNoteKey(net, address))
+	if value == nil {
+		return nil
+	}
+	addressNote := &AddressNote{}
+	if err := json.Unmarshal(value, addressNote); err != nil {
+		c.logger.Errorf("unmarshal note of %s failed: %v", address, err)
+		return nil
+	}
+	return addressNote
+}
+
+func (c *Client) DeleteAddressNote(net string, address string) {
+	c.ldb.Delete(c.construNoteKey(net, address))
+}
+
+// FlaggedCount 统计带有标记的地址数量
+func (c *Client) FlaggedCount(net string) int {
+	count := 0
+	it := c.ldb.Prefix(c.construNoteKey(net, ""))
+	for it.Next() {
+		addressNote := &AddressNote{}
+		if err := json.Unmarshal(it.Value(), addressNote); err == nil && addressNote.Flag != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func (c *Client) isBlocked(net string, address string) bool {
+	addressNote := c.GetAddressNote(net, address)
+	return addressNote != nil && addressNote.Flag == FlagBlocked
+}
+
+// construNoteKey 生成地址备注 key，address 为空时生成用于遍历的前缀
+func (c *Client) construNoteKey(net string, address string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("note-")
+	buffer.WriteString(address)
+	return persist.CompositeKey(net, buffer)
+}
@@ -0,0 +1,15 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// outboundTimeout 请求外部服务的超时时间，服务未单独配置时使用 network.outbound_timeout
+func (c *Client) outboundTimeout(timeout repo.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout.ToDuration()
+	}
+	return c.Config.Network.OutboundTimeout.ToDuration()
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestOutboundTimeout(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Network.OutboundTimeout = repo.Duration(3 * time.Second)
+		cfg.Email.Host = "smtp.example.com"
+		cfg.Email.Timeout = 0
+	})
+	if got := c.outboundTimeout(0); got != 3*time.Second {
+		t.Fatalf("expect the default timeout, got %s", got)
+	}
+	if got := c.outboundTimeout(repo.Duration(time.Second)); got != time.Second {
+		t.Fatalf("service timeout should take precedence, got %s", got)
+	}
+	if m := c.mailer.(*smtpMailer); m.cfg.Timeout.ToDuration() != 3*time.Second {
+		t.Fatalf("mailer should use the default timeout, got %s", m.cfg.Timeout.ToDuration())
+	}
+}
+
+// 推文验证服务无响应时按默认超时结束请求
+func TestTweetReqCheckTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	scrapper := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		scrapper.Close()
+	})
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Scrapper.ScrapperAddr = scrapper.URL
+		cfg.Scrapper.Retries = 0
+		cfg.Scrapper.Timeout = 0
+		cfg.Network.OutboundTimeout = repo.Duration(100 * time.Millisecond)
+	})
+
+	start := time.Now()
+	if code, _ := c.TweetReqCheck(context.Background(), testTweetURL, testRecipient); code != global.VerifierDownCode {
+		t.Fatalf("expect %d after the timeout, got %d", global.VerifierDownCode, code)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("request should time out quickly, took %s", elapsed)
+	}
+}
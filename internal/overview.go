@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Overview 运维面板数据，汇总余额、领取计数、队列以及最近一次错误
+type Overview struct {
+	Status   *Status      `json:"status"`
+	Total    ClaimCounter `json:"total"`
+	Today    ClaimCounter `json:"today"`
+	Queued   int          `json:"queued"`
+	InFlight int64        `json:"inFlight"`
+	// Flagged 带有管理员标记的地址数量
+	Flagged int `json:"flagged"`
+	// LastError 最近一次发送交易失败的原因
+	LastError     string `json:"lastError,omitempty"`
+	LastErrorTime int64  `json:"lastErrorTime,omitempty"`
+}
+
+func (c *Client) Overview() (*Overview, error) {
+	status, err := c.Status()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.Stats(c.Config.Axiom.TestNetName)
+	if err != nil {
+		return nil, err
+	}
+	overview := &Overview{
+		Status:   status,
+		Total:    stats.Total,
+		Today:    stats.Today,
+		Queued:   len(c.queue),
+		InFlight: atomic.LoadInt64(&c.inFlight),
+		Flagged:  c.FlaggedCount(c.Config.Axiom.TestNetName),
+	}
+	c.lastErrLock.Lock()
+	if c.lastErr != nil {
+		overview.LastError = c.lastErr.Error()
+		overview.LastErrorTime = c.lastErrTime.Unix()
+	}
+	c.lastErrLock.Unlock()
+	return overview, nil
+}
+
+func (c *Client) recordError(err error) {
+	c.lastErrLock.Lock()
+	defer c.lastErrLock.Unlock()
+	c.lastErr = err
+	c.lastErrTime = time.Now()
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// 余额不足时在加预锁之前拒绝，不占用地址预锁与领取次数
+func TestPreflightBalanceRejectsBeforeLock(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.PreflightBalanceCheck = true
+		cfg.Axiom.Amount = 10
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	node.SetBalance(testFaucetAddress, 5)
+
+	if code, _ := c.ReserveClaim(ctx, net, testRecipient); code != global.ReserveErrCode {
+		t.Fatalf("expect %d, got %d", global.ReserveErrCode, code)
+	}
+	if c.ldb.Has(c.construPreLockAddressKey(net, global.NativeToken, testRecipient)) {
+		t.Fatal("address should not be locked after a failed preflight")
+	}
+
+	node.SetBalance(testFaucetAddress, 100)
+	if _, code, err := claim(c, ctx, testRecipient, 10); err != nil {
+		t.Fatalf("claim should pass once the faucet is funded, got %d %v", code, err)
+	}
+}
+
+// 按最小的单次发放量预检
+func TestPreflightBalanceUsesSmallestAmount(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.PreflightBalanceCheck = true
+		cfg.Axiom.Amount = 10
+		cfg.Axiom.TweetAmount = 2
+	})
+	node.SetBalance(testFaucetAddress, 5)
+
+	if err := c.preflightBalance(context.Background()); err != nil {
+		t.Fatalf("balance covers the tweet amount, got %v", err)
+	}
+}
+
+func TestPreflightBalanceDisabled(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.PreflightBalanceCheck = false
+	})
+	node.SetBalance(testFaucetAddress, 0)
+
+	if err := c.preflightBalance(context.Background()); err != nil {
+		t.Fatalf("preflight should be skipped when disabled, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// checkActionProof 活动配置了链上行为要求时，要求领取地址在时间窗口内触发过指定合约的指定事件
+func (c *Client) checkActionProof(ctx context.Context, source string, address string) (int, error) {
+	proof, ok := c.actionProofOf(source)
+	if !ok {
+		return global.SUCCESS, nil
+	}
+	if proof.AddressTopic < 1 || proof.AddressTopic > 3 {
+		c.requestLogger(ctx).Errorf("invalid address topic %d of campaign %s", proof.AddressTopic, source)
+		return global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+
+	// window 为 0 时从创世区块开始查询
+	fromBlock := big.NewInt(0)
+	if window := proof.Window.ToDuration(); window > 0 {
+		number, err := c.blockNumberBefore(window)
+		if err != nil {
+			c.requestLogger(ctx).Error(err)
+			return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+		}
+		fromBlock = number
+	}
+	// 事件中地址所在的 topic 位置由配置指定，其余 topic 不限制
+	topics := make([][]common.Hash, proof.AddressTopic+1)
+	topics[0] = []common.Hash{crypto.Keccak256Hash([]byte(proof.Event))}
+	topics[proof.AddressTopic] = []common.Hash{common.BytesToHash(common.HexToAddress(address).Bytes())}
+	logs, err := c.axiomClient.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		Addresses: []common.Address{common.HexToAddress(proof.Contract)},
+		Topics:    topics,
+	})
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	if len(logs) == 0 {
+		return global.ActionProofErrCode, fmt.Errorf(global.ActionProofErrMsg + proof.Event)
+	}
+	return global.SUCCESS, nil
+}
+
+func (c *Client) actionProofOf(source string) (repo.ActionProof, bool) {
+	if source == "" {
+		return repo.ActionProof{}, false
+	}
+	for _, proof := range c.Config.Campaign.Proofs {
+		if proof.Source == source {
+			return proof, true
+		}
+	}
+	return repo.ActionProof{}, false
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const (
+	testQuestContract = "0x4444444444444444444444444444444444444444"
+	testQuestEvent    = "Transfer(address,address,uint256)"
+)
+
+type logFilter struct {
+	FromBlock string           `json:"fromBlock"`
+	Address   []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+// handleLogs 模拟 eth_getLogs，只有 emitters 中的地址在第 topic 个位置上有事件
+func handleLogs(t *testing.T, node *testutil.Node, topic int, emitters ...string) chan logFilter {
+	t.Helper()
+	filters := make(chan logFilter, 10)
+	node.Handle("eth_getLogs", func(params []json.RawMessage) (any, error) {
+		var filter logFilter
+		if err := json.Unmarshal(params[0], &filter); err != nil {
+			return nil, err
+		}
+		filters <- filter
+		logs := []*types.Log{}
+		for _, emitter := range emitters {
+			hash := common.BytesToHash(common.HexToAddress(emitter).Bytes())
+			if len(filter.Topics) > topic && len(filter.Topics[topic]) == 1 && filter.Topics[topic][0] == hash {
+				logs = append(logs, &types.Log{Address: common.HexToAddress(testQuestContract), Topics: []common.Hash{crypto.Keccak256Hash([]byte(testQuestEvent)), {}, hash}})
+			}
+		}
+		return logs, nil
+	})
+	return filters
+}
+
+func newProofClient(t *testing.T, node *testutil.Node, window time.Duration) *Client {
+	t.Helper()
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Campaign.Proofs = []repo.ActionProof{{
+			Source:       "quest",
+			Contract:     testQuestContract,
+			Event:        testQuestEvent,
+			AddressTopic: 2,
+			Window:       repo.Duration(window),
+		}}
+	})
+}
+
+func TestActionProofRequiresEvent(t *testing.T) {
+	node := testutil.NewNode(t)
+	filters := handleLogs(t, node, 2, testRecipient)
+	c := newProofClient(t, node, 0)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	if _, code, err := c.SendTra(ctx, net, testRecipient, 1, 0, "", "quest"); err != nil {
+		t.Fatalf("claim with the event failed: %d %v", code, err)
+	}
+	filter := <-filters
+	if len(filter.Address) != 1 || filter.Address[0] != common.HexToAddress(testQuestContract) {
+		t.Fatalf("logs should be filtered by the campaign contract, got %v", filter.Address)
+	}
+	if filter.FromBlock != "0x0" || len(filter.Topics) != 3 || filter.Topics[0][0] != crypto.Keccak256Hash([]byte(testQuestEvent)) {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+
+	other := "0x2222222222222222222222222222222222222222"
+	_, code, err := c.SendTra(ctx, net, other, 1, 0, "", "quest")
+	if code != global.ActionProofErrCode || !strings.Contains(err.Error(), testQuestEvent) {
+		t.Fatalf("claim without the event should be refused with %d, got %d %v", global.ActionProofErrCode, code, err)
+	}
+	DeleteTxData(c, other, global.NativeToken, net)
+	// 未配置要求的活动不查询事件
+	if _, code, err := claim(c, ctx, other, 1); err != nil {
+		t.Fatalf("claim without campaign failed: %d %v", code, err)
+	}
+	if len(filters) != 1 {
+		t.Fatalf("expect no more log queries, got %d", len(filters))
+	}
+}
+
+func TestActionProofWindowLimitsFromBlock(t *testing.T) {
+	node := testutil.NewNode(t)
+	filters := handleLogs(t, node, 2, testRecipient)
+	node.SetBlock(10000, time.Now(), big.NewInt(1e9))
+	c := newProofClient(t, node, time.Hour)
+
+	if code, err := c.checkActionProof(context.Background(), "quest", testRecipient); err != nil {
+		t.Fatalf("check failed: %d %v", code, err)
+	}
+	if filter := <-filters; filter.FromBlock == "0x0" {
+		t.Fatal("window should limit the queried range")
+	}
+}
+
+func TestActionProofRejectsInvalidTopic(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Campaign.Proofs = []repo.ActionProof{{Source: "quest", Contract: testQuestContract, Event: testQuestEvent, AddressTopic: 4}}
+	})
+
+	if code, _ := c.checkActionProof(context.Background(), "quest", testRecipient); code != global.CommonErrCode {
+		t.Fatalf("expect %d for an invalid address topic, got %d", global.CommonErrCode, code)
+	}
+}
@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/persist"
+)
+
+const (
+	TicketQueued     = "queued"
+	TicketProcessing = "processing"
+	TicketSuccess    = "success"
+	TicketFailed     = "failed"
+)
+
+// Ticket 异步领取的排队凭证
+type Ticket struct {
+	ID      string  `json:"id"`
+	Net     string  `json:"net"`
+	Address string  `json:"address"`
+	Amount  float64 `json:"amount"`
+	Source  string  `json:"source,omitempty"`
+	// ReferralBonus 推荐码奖励，在发放策略调整 Amount 之后叠加
+	ReferralBonus float64 `json:"referralBonus,omitempty"`
+	// Referral 领取使用的推荐码，领取结束后按结果记录归因或归还使用次数
+	Referral   string `json:"referral,omitempty"`
+	Status     string `json:"status"`
+	TxHash     string `json:"txHash,omitempty"`
+	Nonce      uint64 `json:"nonce,omitempty"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg,omitempty"`
+	CreateTime int64  `json:"createTime"`
+	UpdateTime int64  `json:"updateTime"`
+	// Request 发起领取的请求信息，worker 处理时用于日志与审计，不对外返回
+	Request *Request `json:"request,omitempty"`
+}
+
+// EnqueueClaim 预占领取限制后将领取加入队列，返回排队凭证
+func (c *Client) EnqueueClaim(ctx context.Context, net string, address string, amount float64, referralBonus float64, source string, referral string) (*Ticket, int, error) {
+	if atomic.LoadInt32(&c.queueClosed) == 1 {
+		return nil, global.ShuttingDownCode, fmt.Errorf(global.ShuttingDownMsg)
+	}
+	if code, err := c.ReserveClaim(ctx, net, address); err != nil {
+		if !errors.Is(err, ErrAddressLocked) {
+			DeleteTxData(c, strings.ToLower(address), global.NativeToken, net)
+		}
+		return nil, code, err
+	}
+	// 入队前延长预锁，避免排队期间锁过期
+	c.extendPreLock(net, global.NativeToken, strings.ToLower(address), queuedPreLockTTL)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		DeleteTxData(c, strings.ToLower(address), global.NativeToken, net)
+		return nil, global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+	now := time.Now().Unix()
+	ticket := &Ticket{
+		ID:            hex.EncodeToString(id),
+		Net:           net,
+		Address:       address,
+		Amount:        amount,
+		ReferralBonus: referralBonus,
+		Source:        source,
+		Referral:      referral,
+		Status:        TicketQueued,
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+	if req := RequestFrom(ctx); req != (Request{}) {
+		ticket.Request = &req
+	}
+
+	c.ticketLock.Lock()
+	c.pruneTickets(now)
+	c.tickets[ticket.ID] = ticket
+	c.putTicket(ticket)
+	c.ticketLock.Unlock()
+
+	select {
+	case c.queue <- ticket:
+	default:
+		c.ticketLock.Lock()
+		delete(c.tickets, ticket.ID)
+		c.ldb.Delete(c.construTicketKey(ticket.ID))
+		c.ticketLock.Unlock()
+		DeleteTxData(c, strings.ToLower(address), global.NativeToken, net)
+		return nil, global.QueueFullCode, fmt.Errorf(global.QueueFullMsg)
+	}
+	return ticket.copy(), global.SUCCESS, nil
+}
+
+// GetTicket 查询排队凭证的当前状态
+func (c *Client) GetTicket(id string) (*Ticket, bool) {
+	c.ticketLock.RLock()
+	defer c.ticketLock.RUnlock()
+	ticket, ok := c.tickets[id]
+	if !ok {
+		return nil, false
+	}
+	return ticket.copy(), true
+}
+
+// StartQueue 启动后台 worker 按顺序处理排队的领取，交易 nonce 由 sendTxAxm 串行分配
+func (c *Client) StartQueue() {
+	c.restoreTickets()
+	c.queueStop = make(chan struct{})
+	c.queueDone = make(chan struct{})
+	go func() {
+		defer close(c.queueDone)
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-c.queueStop:
+				return
+			case ticket := <-c.queue:
+				c.processTicket(ticket)
+			}
+		}
+	}()
+}
+
+// StopQueue 停止接收新的排队领取，在 grace 时间内继续处理队列中的领取，之后等待正在处理的领取完成后停止 worker。
+// 未处理的凭证已持久化为排队状态，重启后由 restoreTickets 恢复；正在处理的领取不会被打断，避免重复发送
+func (c *Client) StopQueue(grace time.Duration) {
+	if c.queueStop == nil {
+		return
+	}
+	atomic.StoreInt32(&c.queueClosed, 1)
+	deadline := time.Now().Add(grace)
+	for (len(c.queue) > 0 || atomic.LoadInt32(&c.queueBusy) == 1) && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	close(c.queueStop)
+	<-c.queueDone
+	if remaining := len(c.queue); remaining > 0 {
+		c.logger.Warnf("queue stopped with %d ticket(s) left, they will be resumed after restart", remaining)
+		return
+	}
+	c.logger.Infoln("queue drained")
+}
+
+func (c *Client) processTicket(ticket *Ticket) {
+	atomic.StoreInt32(&c.queueBusy, 1)
+	defer atomic.StoreInt32(&c.queueBusy, 0)
+	c.updateTicket(ticket, TicketProcessing, "", global.SUCCESS, "")
+	ctx := context.WithValue(c.ctx, ticketKey{}, ticket)
+	if ticket.Request != nil {
+		ctx = WithRequest(ctx, *ticket.Request)
+	}
+	txHash, code, err := c.processClaim(ctx, ticket.Net, ticket.Address, ticket.Amount, ticket.ReferralBonus, "", ticket.Source, false)
+	DeleteTxData(c, strings.ToLower(ticket.Address), global.NativeToken, ticket.Net)
+	if err != nil {
+		c.updateTicket(ticket, TicketFailed, "", code, err.Error())
+		return
+	}
+	c.updateTicket(ticket, TicketSuccess, txHash, code, global.SUCCESSMsg)
+}
+
+type ticketKey struct{}
+
+// markTicketSending 在交易广播前把交易哈希和 nonce 写入 ctx 中的排队凭证，重启后据此判断交易是否已发出
+func (c *Client) markTicketSending(ctx context.Context, tx *types.Transaction) {
+	ticket, ok := ctx.Value(ticketKey{}).(*Ticket)
+	if !ok {
+		return
+	}
+	c.ticketLock.Lock()
+	defer c.ticketLock.Unlock()
+	ticket.TxHash = tx.Hash().Hex()
+	ticket.Nonce = tx.Nonce()
+	ticket.UpdateTime = time.Now().Unix()
+	c.putTicket(ticket)
+}
+
+func (c *Client) updateTicket(ticket *Ticket, status string, txHash string, code int, msg string) {
+	c.ticketLock.Lock()
+	defer c.ticketLock.Unlock()
+	ticket.Status = status
+	ticket.TxHash = txHash
+	if txHash == "" {
+		ticket.Nonce = 0
+	}
+	ticket.Code = code
+	ticket.Msg = msg
+	ticket.UpdateTime = time.Now().Unix()
+	c.putTicket(ticket)
+	c.settleTicketReferral(ticket)
+}
+
+// settleTicketReferral 凭证结束时按结果记录推荐码归因或归还使用次数，未结束的凭证忽略
+func (c *Client) settleTicketReferral(ticket *Ticket) {
+	if ticket.Referral == "" {
+		return
+	}
+	switch ticket.Status {
+	case TicketSuccess:
+		c.RecordReferral(ticket.Net, ticket.Referral, ticket.Address, ticket.TxHash)
+	case TicketFailed:
+		c.ReleaseReferral(ticket.Net, ticket.Referral)
+	}
+}
+
+// restoreTickets 重启后从存储中恢复凭证：排队中的重新入队；处理中的凭证如果记录了交易哈希且链上能查到该交易，
+// 说明交易已发送，补写领取记录后标记成功，避免重复发放；查不到交易时重新入队，查询失败时无法判断是否已发送，标记失败
+func (c *Client) restoreTickets() {
+	c.ticketLock.Lock()
+	defer c.ticketLock.Unlock()
+	it := c.ldb.Prefix(c.construTicketKey(""))
+	for it.Next() {
+		ticket := &Ticket{}
+		if err := json.Unmarshal(it.Value(), ticket); err != nil {
+			c.logger.Errorf("unmarshal ticket %s failed: %v", it.Key(), err)
+			continue
+		}
+		c.tickets[ticket.ID] = ticket
+		switch ticket.Status {
+		case TicketProcessing:
+			if ticket.TxHash != "" {
+				sent, err := c.txBroadcast(ticket.TxHash)
+				if err != nil {
+					c.logger.Errorf("query tx %s of ticket %s failed: %v", ticket.TxHash, ticket.ID, err)
+					c.finishRestoredTicket(ticket, TicketFailed, global.CommonErrCode, global.CommonErrMsg)
+					continue
+				}
+				if sent {
+					c.logger.Infof("ticket %s was sent before restart: %s, nonce: %d", ticket.ID, ticket.TxHash, ticket.Nonce)
+					c.recordRestoredClaim(ticket)
+					c.finishRestoredTicket(ticket, TicketSuccess, global.SUCCESS, global.SUCCESSMsg)
+					continue
+				}
+				ticket.TxHash, ticket.Nonce = "", 0
+			}
+			ticket.Status = TicketQueued
+		case TicketQueued:
+		default:
+			continue
+		}
+		select {
+		case c.queue <- ticket:
+			c.logger.Infof("resume ticket %s of %s", ticket.ID, ticket.Address)
+		default:
+			c.finishRestoredTicket(ticket, TicketFailed, global.QueueFullCode, global.QueueFullMsg)
+		}
+	}
+}
+
+// recordRestoredClaim 重启前交易已发出、但领取记录尚未写入时补写 pending 状态的领取记录，使地址仍受领取间隔限制，
+// 查到回执后由 finishClaimRecord 更新；地址记录已是该交易时不重复写入，避免统计重复累计
+func (c *Client) recordRestoredClaim(ticket *Ticket) {
+	address := strings.ToLower(ticket.Address)
+	data := &AddressData{}
+	if value := c.ldb.Get(c.construAddressKey(ticket.Net, global.NativeToken, address)); value != nil && json.Unmarshal(value, data) == nil && data.TxHash == ticket.TxHash {
+		return
+	}
+	// 凭证在交易发出时更新，更新时间即发送时间
+	sendTxTime := ticket.UpdateTime
+	if sendTxTime == 0 {
+		sendTxTime = time.Now().Unix()
+	}
+	c.storeClaimData(c.ctx, ticket.Net, global.NativeToken, address, &AddressData{
+		SendTxTime: sendTxTime,
+		TxHash:     ticket.TxHash,
+		Amount:     ticket.Amount + ticket.ReferralBonus,
+		Source:     ticket.Source,
+		Nonce:      ticket.Nonce,
+		Status:     ReceiptPending,
+	})
+}
+
+// finishRestoredTicket 结束重启前正在处理的凭证并释放地址预锁，调用方需持有 ticketLock
+func (c *Client) finishRestoredTicket(ticket *Ticket, status string, code int, msg string) {
+	ticket.Status, ticket.Code, ticket.Msg = status, code, msg
+	ticket.UpdateTime = time.Now().Unix()
+	c.putTicket(ticket)
+	c.settleTicketReferral(ticket)
+	DeleteTxData(c, strings.ToLower(ticket.Address), global.NativeToken, ticket.Net)
+}
+
+// txBroadcast 查询交易是否已被节点接收（在交易池中或已上链）
+func (c *Client) txBroadcast(txHash string) (bool, error) {
+	c.axiomLock.Lock()
+	client := c.axiomClient
+	c.axiomLock.Unlock()
+	_, _, err := client.TransactionByHash(c.ctx, common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// putTicket 持久化凭证，调用方需持有 ticketLock
+func (c *Client) putTicket(ticket *Ticket) {
+	value, err := json.Marshal(ticket)
+	if err != nil {
+		c.logger.Errorf("json marshal ticket %s failed: %v", ticket.ID, err)
+		return
+	}
+	c.ldb.Put(c.construTicketKey(ticket.ID), value)
+}
+
+// construTicketKey 生成凭证 key，id 为空时生成用于遍历的前缀
+func (c *Client) construTicketKey(id string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("ticket-")
+	buffer.WriteString(id)
+	return persist.CompositeKey(c.Config.Axiom.TestNetName, buffer)
+}
+
+// pruneTickets 清理已结束且超过保留时间的凭证，调用方需持有 ticketLock
+func (c *Client) pruneTickets(now int64) {
+	ttl := int64(c.Config.Queue.TicketTTL.ToDuration().Seconds())
+	for id, ticket := range c.tickets {
+		finished := ticket.Status == TicketSuccess || ticket.Status == TicketFailed
+		if finished && now-ticket.UpdateTime > ttl {
+			delete(c.tickets, id)
+			c.ldb.Delete(c.construTicketKey(id))
+		}
+	}
+}
+
+func (t *Ticket) copy() *Ticket {
+	ticket := *t
+	ticket.Request = nil
+	return &ticket
+}
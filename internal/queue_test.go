@@ -0,0 +1,277 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// waitTicket 轮询凭证直到结束
+func waitTicket(t *testing.T, c *Client, id string) *Ticket {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		ticket, ok := c.GetTicket(id)
+		if !ok {
+			t.Fatalf("ticket %s not found", id)
+		}
+		if ticket.Status == TicketSuccess || ticket.Status == TicketFailed {
+			return ticket
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("ticket %s not finished in time", id)
+	return nil
+}
+
+func TestEnqueueClaimIsProcessedByWorker(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+	})
+	c.StartQueue()
+	t.Cleanup(func() { c.StopQueue(time.Second) })
+
+	ticket, code, err := c.EnqueueClaim(context.Background(), c.Config.Axiom.TestNetName, testRecipient, 1, 0, "campaign", "")
+	if err != nil {
+		t.Fatalf("enqueue failed: %d %v", code, err)
+	}
+	if ticket.Status != TicketQueued {
+		t.Fatalf("new ticket should be queued, got %s", ticket.Status)
+	}
+
+	done := waitTicket(t, c, ticket.ID)
+	if done.Status != TicketSuccess || done.TxHash == "" {
+		t.Fatalf("ticket should succeed with a tx hash, got %+v", done)
+	}
+	sent := node.Sent()
+	if len(sent) != 1 || sent[0].Hash().Hex() != done.TxHash {
+		t.Fatalf("expect the ticket tx to be sent once, got %d tx(s)", len(sent))
+	}
+	if to, amount := dripValue(t, sent[0]); to != testRecipient || amount != 1 {
+		t.Fatalf("expect drip of 1 to %s, got %v to %s", testRecipient, amount, to)
+	}
+}
+
+// 排队期间同一地址持有预锁，不能再次排队
+func TestEnqueueClaimLocksAddressWhileQueued(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	if _, code, err := c.EnqueueClaim(ctx, net, testRecipient, 1, 0, "", ""); err != nil {
+		t.Fatalf("enqueue failed: %d %v", code, err)
+	}
+	if _, _, err := c.EnqueueClaim(ctx, net, testRecipient, 1, 0, "", ""); err == nil {
+		t.Fatal("queued address should not be enqueued twice")
+	}
+}
+
+func TestEnqueueClaimRejectsWhenQueueFull(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+		cfg.Queue.Size = 1
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	other := "0x2222222222222222222222222222222222222222"
+
+	if _, code, err := c.EnqueueClaim(ctx, net, testRecipient, 1, 0, "", ""); err != nil {
+		t.Fatalf("enqueue failed: %d %v", code, err)
+	}
+	if _, code, _ := c.EnqueueClaim(ctx, net, other, 1, 0, "", ""); code != global.QueueFullCode {
+		t.Fatalf("expect %d when the queue is full, got %d", global.QueueFullCode, code)
+	}
+	// 入队失败应释放预锁，队列空出后可以再次排队
+	<-c.queue
+	if _, code, err := c.EnqueueClaim(ctx, net, other, 1, 0, "", ""); err != nil {
+		t.Fatalf("rejected address should be able to enqueue later: %d %v", code, err)
+	}
+}
+
+func TestGetTicketUnknownID(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if _, ok := c.GetTicket("missing"); ok {
+		t.Fatal("unknown ticket should not be found")
+	}
+}
+
+// storeTicket 模拟重启前持久化的凭证
+func storeTicket(c *Client, ticket *Ticket) {
+	c.ticketLock.Lock()
+	defer c.ticketLock.Unlock()
+	c.putTicket(ticket)
+}
+
+func newRestoreClient(t *testing.T, node *testutil.Node) *Client {
+	t.Helper()
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+	})
+}
+
+func TestRestoreQueuedTicket(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newRestoreClient(t, node)
+	storeTicket(c, &Ticket{ID: "queued", Net: c.Config.Axiom.TestNetName, Address: testRecipient, Amount: 1, Status: TicketQueued})
+
+	c.StartQueue()
+	t.Cleanup(func() { c.StopQueue(time.Second) })
+	if done := waitTicket(t, c, "queued"); done.Status != TicketSuccess {
+		t.Fatalf("restored ticket should be processed, got %+v", done)
+	}
+	if sent := node.Sent(); len(sent) != 1 {
+		t.Fatalf("expect 1 tx, got %d", len(sent))
+	}
+}
+
+// 重启前已广播的交易不再重复发送
+func TestRestoreProcessingTicketAlreadySent(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newRestoreClient(t, node)
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	storeTicket(c, &Ticket{ID: "sent", Net: c.Config.Axiom.TestNetName, Address: testRecipient, Amount: 1, Status: TicketProcessing, TxHash: txHash})
+
+	c.StartQueue()
+	t.Cleanup(func() { c.StopQueue(time.Second) })
+	if done := waitTicket(t, c, "sent"); done.Status != TicketSuccess || done.TxHash != txHash {
+		t.Fatalf("ticket should finish with the broadcast tx, got %+v", done)
+	}
+	if sent := node.Sent(); len(sent) != 1 {
+		t.Fatalf("tx should not be sent again, got %d txs", len(sent))
+	}
+	// 已写入的领取记录不重复写入
+	if count := c.ClaimCount(c.Config.Axiom.TestNetName, testRecipient); count != 1 {
+		t.Fatalf("expect 1 history record, got %d", count)
+	}
+}
+
+// 交易已发出但重启前未写入领取记录时补写记录，地址仍受领取间隔限制
+func TestRestoreProcessingTicketRecordsClaim(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newRestoreClient(t, node)
+	net := c.Config.Axiom.TestNetName
+	// 由另一个实例发出交易，模拟广播后、写入领取记录前进程退出
+	txHash, code, err := claim(newTestClient(t, node, nil), context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	sentAt := time.Now().Unix()
+	storeTicket(c, &Ticket{ID: "sent", Net: net, Address: testRecipient, Amount: 1, Status: TicketProcessing, TxHash: txHash, UpdateTime: sentAt})
+
+	c.StartQueue()
+	t.Cleanup(func() { c.StopQueue(time.Second) })
+	if done := waitTicket(t, c, "sent"); done.Status != TicketSuccess {
+		t.Fatalf("ticket should finish with the broadcast tx, got %+v", done)
+	}
+	data := c.LastClaim(net, testRecipient)
+	if data == nil || data.TxHash != txHash || data.Status != ReceiptPending || data.SendTxTime != sentAt {
+		t.Fatalf("expect a pending claim record of the broadcast tx, got %+v", data)
+	}
+	if count := c.ClaimCount(net, testRecipient); count != 1 {
+		t.Fatalf("expect 1 history record, got %d", count)
+	}
+	if _, code, _ := claim(c, context.Background(), testRecipient, 1); code != global.ReqWithinDayCode {
+		t.Fatalf("restored claim should still limit the address, got %d", code)
+	}
+	if sent := node.Sent(); len(sent) != 1 {
+		t.Fatalf("tx should not be sent again, got %d txs", len(sent))
+	}
+
+	// 查到回执后更新为最终状态
+	if receipt, _ := c.GetReceipt(ReceiptID(txHash)); receipt == nil || receipt.Status != ReceiptConfirmed {
+		t.Fatalf("expect the receipt to be confirmed, got %+v", receipt)
+	}
+	if data := c.LastClaim(net, testRecipient); data.Status != ReceiptConfirmed {
+		t.Fatalf("claim record should be confirmed, got %+v", data)
+	}
+}
+
+// 节点查不到记录的交易时重新入队处理
+func TestRestoreProcessingTicketNotBroadcast(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newRestoreClient(t, node)
+	lost := "0x" + strings.Repeat("ab", 32)
+	storeTicket(c, &Ticket{ID: "lost", Net: c.Config.Axiom.TestNetName, Address: testRecipient, Amount: 1, Status: TicketProcessing, TxHash: lost, Nonce: 3})
+
+	c.StartQueue()
+	t.Cleanup(func() { c.StopQueue(time.Second) })
+	done := waitTicket(t, c, "lost")
+	if done.Status != TicketSuccess || done.TxHash == lost {
+		t.Fatalf("ticket should be resent, got %+v", done)
+	}
+	if sent := node.Sent(); len(sent) != 1 || sent[0].Hash().Hex() != done.TxHash {
+		t.Fatalf("expect the ticket to be resent once, got %d txs", len(sent))
+	}
+}
+
+// 停止时在宽限期内排空队列，之后拒绝新的排队领取
+func TestStopQueueDrainsTickets(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	addresses := []string{testRecipient, "0x2222222222222222222222222222222222222222"}
+	c.StartQueue()
+	var ids []string
+	for _, address := range addresses {
+		ticket, code, err := c.EnqueueClaim(ctx, net, address, 1, 0, "", "")
+		if err != nil {
+			t.Fatalf("enqueue failed: %d %v", code, err)
+		}
+		ids = append(ids, ticket.ID)
+	}
+
+	c.StopQueue(5 * time.Second)
+	for _, id := range ids {
+		if ticket, _ := c.GetTicket(id); ticket.Status != TicketSuccess {
+			t.Fatalf("ticket %s should be drained before stop, got %s", id, ticket.Status)
+		}
+	}
+	if sent := node.Sent(); len(sent) != len(addresses) {
+		t.Fatalf("expect %d txs, got %d", len(addresses), len(sent))
+	}
+
+	_, code, err := c.EnqueueClaim(ctx, net, "0x3333333333333333333333333333333333333333", 1, 0, "", "")
+	if err == nil || code != global.ShuttingDownCode {
+		t.Fatalf("expect %d after stop, got %d %v", global.ShuttingDownCode, code, err)
+	}
+}
+
+// 宽限期结束后剩余凭证保持排队状态，重启后恢复
+func TestStopQueueLeavesTicketsAfterGrace(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+	})
+	ticket, code, err := c.EnqueueClaim(context.Background(), c.Config.Axiom.TestNetName, testRecipient, 1, 0, "", "")
+	if err != nil {
+		t.Fatalf("enqueue failed: %d %v", code, err)
+	}
+	// worker 已停止时不会再处理队列
+	c.queueStop = make(chan struct{})
+	c.queueDone = make(chan struct{})
+	close(c.queueDone)
+
+	c.StopQueue(0)
+	if stored, _ := c.GetTicket(ticket.ID); stored.Status != TicketQueued {
+		t.Fatalf("undrained ticket should stay queued, got %s", stored.Status)
+	}
+}
+
+// 未启动队列时停止不阻塞
+func TestStopQueueWithoutStart(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	c.StopQueue(time.Second)
+}
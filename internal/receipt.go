@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// Mailer 发送邮件，默认使用配置的 SMTP 服务
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// ClaimReceipt 领取成功后发送给领取人的回执
+type ClaimReceipt struct {
+	Net         string
+	Address     string
+	Amount      float64
+	TxHash      string
+	ExplorerURL string
+}
+
+// SetMailer 替换回执邮件的发送方式
+func (c *Client) SetMailer(mailer Mailer) {
+	c.mailer = mailer
+}
+
+// MailReceipt 异步发送领取回执，未配置邮件服务时不发送，发送失败只记录日志，不影响领取结果
+func (c *Client) MailReceipt(to string, receipt *ClaimReceipt) {
+	mailer := c.mailer
+	if mailer == nil || to == "" {
+		return
+	}
+	go func() {
+		if err := mailer.Send(to, c.Config.Email.Subject, receiptBody(receipt)); err != nil {
+			c.logger.Warnf("mail receipt of %s failed: %v", receipt.TxHash, err)
+		}
+	}()
+}
+
+func receiptBody(receipt *ClaimReceipt) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your faucet claim has been sent.\r\n\r\n")
+	fmt.Fprintf(&b, "Network: %s\r\n", receipt.Net)
+	fmt.Fprintf(&b, "Address: %s\r\n", receipt.Address)
+	fmt.Fprintf(&b, "Amount: %s\r\n", strconv.FormatFloat(receipt.Amount, 'f', -1, 64))
+	fmt.Fprintf(&b, "Transaction: %s\r\n", receipt.TxHash)
+	if receipt.ExplorerURL != "" {
+		fmt.Fprintf(&b, "Explorer: %s\r\n", receipt.ExplorerURL)
+	}
+	return b.String()
+}
+
+// smtpMailer 通过 SMTP 发送纯文本邮件，服务端支持时使用 STARTTLS，配置了用户名时进行认证
+type smtpMailer struct {
+	cfg repo.Email
+}
+
+func (m *smtpMailer) Send(to string, subject string, body string) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	conn, err := net.DialTimeout("tcp", addr, m.cfg.Timeout.ToDuration())
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(m.cfg.Timeout.ToDuration())); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s", m.cfg.From, to, subject, body)
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
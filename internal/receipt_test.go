@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestReceiptBody(t *testing.T) {
+	body := receiptBody(&ClaimReceipt{Net: "testnet", Address: testRecipient, Amount: 1.5, TxHash: "0xabc", ExplorerURL: "https://scan.example/tx/0xabc"})
+	for _, want := range []string{"Network: testnet", "Address: " + testRecipient, "Amount: 1.5\r\n", "Transaction: 0xabc", "Explorer: https://scan.example/tx/0xabc"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body should contain %q, got %q", want, body)
+		}
+	}
+	if body := receiptBody(&ClaimReceipt{TxHash: "0xabc"}); strings.Contains(body, "Explorer") {
+		t.Fatalf("explorer line should be omitted without a link, got %q", body)
+	}
+}
+
+// newSMTPServer 启动只支持明文的 SMTP 服务，收到的邮件内容写入返回的 channel
+func newSMTPServer(t *testing.T) (string, int, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil || dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				messages <- data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, messages
+}
+
+func TestSMTPMailerSends(t *testing.T) {
+	host, port, messages := newSMTPServer(t)
+	mailer := &smtpMailer{cfg: repo.Email{Host: host, Port: port, From: "faucet@example.com", Timeout: repo.Duration(time.Second)}}
+
+	if err := mailer.Send("user@example.com", "Faucet claim receipt", "Transaction: 0xabc"); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-messages
+	for _, want := range []string{"From: faucet@example.com", "To: user@example.com", "Subject: Faucet claim receipt", "Transaction: 0xabc"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("message should contain %q, got %q", want, msg)
+		}
+	}
+}
+
+func TestSMTPMailerReportsUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer := &smtpMailer{cfg: repo.Email{Host: "127.0.0.1", Port: port, Timeout: repo.Duration(time.Second)}}
+	if err := mailer.Send("user@example.com", "subject", "body"); err == nil {
+		t.Fatal("send to an unreachable server should fail")
+	}
+}
+
+func TestMailerConfiguredByHost(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if c.mailer != nil {
+		t.Fatal("mailer should not be configured without a host")
+	}
+	c = newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Email.Host = "smtp.example.com"
+	})
+	if m, ok := c.mailer.(*smtpMailer); !ok || m.cfg.Port != 587 {
+		t.Fatalf("smtp mailer should be configured, got %T", c.mailer)
+	}
+}
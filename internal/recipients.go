@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/persist"
+)
+
+// checkDailyRecipients 每个网络每天最多向 max_daily_recipients 个不同地址发放，当天已发放过的地址不受影响。
+// 只在发放成功后记录，同时处理中的领取可能使当天的地址数略超上限
+func (c *Client) checkDailyRecipients(ctx context.Context, net string, address string) error {
+	limit := c.Config.Axiom.MaxDailyRecipients
+	if limit <= 0 || c.Config.DevMode {
+		return nil
+	}
+	day := time.Now().Format("2006-01-02")
+	c.preLockCheck.Lock()
+	defer c.preLockCheck.Unlock()
+	if c.ldb.Has(c.construRecipientKey(net, day, address)) {
+		return nil
+	}
+	count, err := c.dailyRecipientCount(net, day)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		c.requestLogger(ctx).Warnf("%s has funded %d addresses today, reject %s", net, count, address)
+		return fmt.Errorf(global.DailyRecipientsMsg)
+	}
+	return nil
+}
+
+// markDailyRecipient 发放成功后记录当天的领取地址
+func (c *Client) markDailyRecipient(ctx context.Context, net string, address string) {
+	if c.Config.Axiom.MaxDailyRecipients <= 0 {
+		return
+	}
+	day := time.Now().Format("2006-01-02")
+	c.preLockCheck.Lock()
+	defer c.preLockCheck.Unlock()
+	recipientKey := c.construRecipientKey(net, day, address)
+	if c.ldb.Has(recipientKey) {
+		return
+	}
+	count, err := c.dailyRecipientCount(net, day)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return
+	}
+	batch := c.ldb.NewBatch()
+	batch.Put(recipientKey, []byte("1"))
+	batch.Put(c.construRecipientCountKey(net, day), []byte(strconv.Itoa(count+1)))
+	batch.Commit()
+}
+
+func (c *Client) dailyRecipientCount(net string, day string) (int, error) {
+	value := c.ldb.Get(c.construRecipientCountKey(net, day))
+	if value == nil {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, fmt.Errorf("parse recipient count of %s: %w", day, err)
+	}
+	return count, nil
+}
+
+func (c *Client) construRecipientKey(net string, day string, address string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("recipient-")
+	buffer.WriteString(day)
+	buffer.WriteString("-")
+	buffer.WriteString(address)
+	return persist.CompositeKey(net, buffer)
+}
+
+func (c *Client) construRecipientCountKey(net string, day string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("recipientcount-")
+	buffer.WriteString(day)
+	return persist.CompositeKey(net, buffer)
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestClaimRejectsBeyondDailyRecipients(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.MaxDailyRecipients = 1
+	})
+	ctx := context.Background()
+
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("first recipient should be funded: %d %v", code, err)
+	}
+	other := "0x2222222222222222222222222222222222222222"
+	if _, code, _ := claim(c, ctx, other, 1); code != global.DailyRecipientsCode {
+		t.Fatalf("expect %d beyond the daily limit, got %d", global.DailyRecipientsCode, code)
+	}
+	if sent := node.Sent(); len(sent) != 1 {
+		t.Fatalf("rejected claim should not send a tx, got %d txs", len(sent))
+	}
+	// 当天已发放过的地址不受上限影响
+	if err := c.checkDailyRecipients(ctx, c.Config.Axiom.TestNetName, testRecipient); err != nil {
+		t.Fatalf("funded recipient should pass the check: %v", err)
+	}
+}
+
+// 同一地址多次记录只计一次
+func TestMarkDailyRecipientCountsDistinctAddresses(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.MaxDailyRecipients = 10
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	c.markDailyRecipient(ctx, net, testRecipient)
+	c.markDailyRecipient(ctx, net, testRecipient)
+	c.markDailyRecipient(ctx, net, "0x2222222222222222222222222222222222222222")
+
+	count, err := c.dailyRecipientCount(net, time.Now().Format("2006-01-02"))
+	if err != nil || count != 2 {
+		t.Fatalf("expect 2 distinct recipients, got %d %v", count, err)
+	}
+}
+
+func TestDailyRecipientsUnlimited(t *testing.T) {
+	for name, setup := range map[string]func(cfg *repo.Config){
+		"no limit": func(cfg *repo.Config) {},
+		"dev mode": func(cfg *repo.Config) {
+			cfg.Axiom.MaxDailyRecipients = 1
+			cfg.DevMode = true
+		},
+	} {
+		c := newTestClient(t, testutil.NewNode(t), setup)
+		ctx := context.Background()
+		net := c.Config.Axiom.TestNetName
+		c.markDailyRecipient(ctx, net, testRecipient)
+		if err := c.checkDailyRecipients(ctx, net, "0x2222222222222222222222222222222222222222"); err != nil {
+			t.Fatalf("%s: recipients should not be limited: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/persist"
+)
+
+// ReferralCode 推荐码及其使用情况，MaxUses 为 0 时不限制使用次数
+type ReferralCode struct {
+	Code       string  `json:"code"`
+	MaxUses    int     `json:"maxUses"`
+	Bonus      float64 `json:"bonus,omitempty"`
+	Uses       int     `json:"uses"`
+	CreateTime int64   `json:"createTime"`
+}
+
+// ReferralUse 推荐码的一次成功领取，用于归因统计
+type ReferralUse struct {
+	Code      string `json:"code"`
+	Address   string `json:"address"`
+	TxHash    string `json:"txHash"`
+	ClaimTime int64  `json:"claimTime"`
+}
+
+// initReferralCodes 将配置中的推荐码写入存储，已存在的推荐码保留使用次数，只更新次数上限和奖励
+func (c *Client) initReferralCodes() error {
+	if !c.Config.Referral.Enable {
+		return nil
+	}
+	net := c.Config.Axiom.TestNetName
+	for _, code := range c.Config.Referral.Codes {
+		if code.Code == "" {
+			return fmt.Errorf("referral code is empty")
+		}
+		if code.Bonus < 0 || code.Bonus > c.Config.Referral.MaxBonus {
+			return fmt.Errorf("bonus %v of referral code %s is out of range [0, %v]", code.Bonus, code.Code, c.Config.Referral.MaxBonus)
+		}
+		referral := c.GetReferralCode(net, code.Code)
+		if referral == nil {
+			referral = &ReferralCode{Code: code.Code, CreateTime: time.Now().Unix()}
+		}
+		referral.MaxUses = code.MaxUses
+		referral.Bonus = code.Bonus
+		if err := c.putReferralCode(net, referral); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateReferralCode 管理员生成推荐码，code 为空时随机生成
+func (c *Client) CreateReferralCode(net string, code string, maxUses int, bonus float64) (*ReferralCode, error) {
+	if code == "" {
+		id := make([]byte, 6)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		code = hex.EncodeToString(id)
+	}
+	c.referralLock.Lock()
+	defer c.referralLock.Unlock()
+	if c.GetReferralCode(net, code) != nil {
+		return nil, fmt.Errorf("referral code %s already exists", code)
+	}
+	referral := &ReferralCode{Code: code, MaxUses: maxUses, Bonus: bonus, CreateTime: time.Now().Unix()}
+	if err := c.putReferralCode(net, referral); err != nil {
+		return nil, err
+	}
+	return referral, nil
+}
+
+// GetReferralCode 返回推荐码，不存在时返回 nil
+func (c *Client) GetReferralCode(net string, code string) *ReferralCode {
+	value := c.ldb.Get(c.construReferralKey(net, code))
+	if value == nil {
+		return nil
+	}
+	referral := &ReferralCode{}
+	if err := json.Unmarshal(value, referral); err != nil {
+		c.logger.Errorf("unmarshal referral code %s failed: %v", code, err)
+		return nil
+	}
+	return referral
+}
+
+// ReserveReferral 校验推荐码并占用一次使用次数，返回推荐码的额外奖励，不超过 max_bonus；领取失败时需通过 ReleaseReferral 归还
+func (c *Client) ReserveReferral(net string, code string) (float64, int, error) {
+	if code == "" {
+		return 0, global.ReferralErrCode, fmt.Errorf(global.ReferralErrMsg)
+	}
+	c.referralLock.Lock()
+	defer c.referralLock.Unlock()
+	referral := c.GetReferralCode(net, code)
+	if referral == nil {
+		return 0, global.ReferralErrCode, fmt.Errorf(global.ReferralErrMsg + code)
+	}
+	if referral.MaxUses > 0 && referral.Uses >= referral.MaxUses {
+		return 0, global.ReferralExhaustedCode, fmt.Errorf(global.ReferralExhaustedMsg)
+	}
+	referral.Uses++
+	if err := c.putReferralCode(net, referral); err != nil {
+		c.logger.Error(err)
+		return 0, global.CommonErrCode, fmt.Errorf(global.CommonErrMsg)
+	}
+	return math.Min(referral.Bonus, c.Config.Referral.MaxBonus), global.SUCCESS, nil
+}
+
+// ReleaseReferral 领取失败时归还占用的使用次数
+func (c *Client) ReleaseReferral(net string, code string) {
+	c.referralLock.Lock()
+	defer c.referralLock.Unlock()
+	referral := c.GetReferralCode(net, code)
+	if referral == nil || referral.Uses == 0 {
+		return
+	}
+	referral.Uses--
+	if err := c.putReferralCode(net, referral); err != nil {
+		c.logger.Error(err)
+	}
+}
+
+// RecordReferral 记录推荐码带来的成功领取
+func (c *Client) RecordReferral(net string, code string, address string, txHash string) {
+	address = strings.ToLower(address)
+	value, err := json.Marshal(&ReferralUse{Code: code, Address: address, TxHash: txHash, ClaimTime: time.Now().Unix()})
+	if err != nil {
+		c.logger.Errorf("json marshal failed: %v", err)
+		return
+	}
+	c.ldb.Put(c.construReferralUseKey(net, code, address), value)
+}
+
+// ReferralUses 返回推荐码带来的全部成功领取
+func (c *Client) ReferralUses(net string, code string) []*ReferralUse {
+	uses := make([]*ReferralUse, 0)
+	it := c.ldb.Prefix(c.construReferralUseKey(net, code, ""))
+	for it.Next() {
+		use := &ReferralUse{}
+		if err := json.Unmarshal(it.Value(), use); err != nil {
+			c.logger.Errorf("unmarshal referral use failed: %v", err)
+			continue
+		}
+		uses = append(uses, use)
+	}
+	return uses
+}
+
+func (c *Client) putReferralCode(net string, referral *ReferralCode) error {
+	value, err := json.Marshal(referral)
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+	c.ldb.Put(c.construReferralKey(net, referral.Code), value)
+	return nil
+}
+
+func (c *Client) construReferralKey(net string, code string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("referral-")
+	buffer.WriteString(code)
+	return persist.CompositeKey(net, buffer)
+}
+
+func (c *Client) construReferralUseKey(net string, code string, address string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("referraluse-")
+	buffer.WriteString(code)
+	buffer.WriteString("-")
+	buffer.WriteString(address)
+	return persist.CompositeKey(net, buffer)
+}
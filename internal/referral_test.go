@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func newReferralClient(t *testing.T, codes ...repo.ReferralCode) *Client {
+	t.Helper()
+	return newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Referral = repo.Referral{Enable: true, MaxBonus: 10, Codes: codes}
+	})
+}
+
+func TestReserveReferralLimitsUses(t *testing.T) {
+	c := newReferralClient(t, repo.ReferralCode{Code: "friends", MaxUses: 2, Bonus: 3})
+	net := c.Config.Axiom.TestNetName
+
+	for i := 0; i < 2; i++ {
+		bonus, code, err := c.ReserveReferral(net, "friends")
+		if err != nil || bonus != 3 {
+			t.Fatalf("reserve %d: expect bonus 3, got %v %d %v", i, bonus, code, err)
+		}
+	}
+	if _, code, _ := c.ReserveReferral(net, "friends"); code != global.ReferralExhaustedCode {
+		t.Fatalf("expect %d once uses are exhausted, got %d", global.ReferralExhaustedCode, code)
+	}
+	// 领取失败归还使用次数后可以再次使用
+	c.ReleaseReferral(net, "friends")
+	if _, code, err := c.ReserveReferral(net, "friends"); err != nil {
+		t.Fatalf("released use should be available again, got %d %v", code, err)
+	}
+	if referral := c.GetReferralCode(net, "friends"); referral.Uses != 2 {
+		t.Fatalf("expect 2 uses, got %d", referral.Uses)
+	}
+}
+
+func TestReserveReferralRejectsUnknownCode(t *testing.T) {
+	c := newReferralClient(t)
+	net := c.Config.Axiom.TestNetName
+
+	for _, code := range []string{"", "missing"} {
+		if _, errCode, _ := c.ReserveReferral(net, code); errCode != global.ReferralErrCode {
+			t.Fatalf("%q: expect %d, got %d", code, global.ReferralErrCode, errCode)
+		}
+	}
+}
+
+func TestReleaseReferralKeepsUsesNonNegative(t *testing.T) {
+	c := newReferralClient(t, repo.ReferralCode{Code: "friends"})
+	net := c.Config.Axiom.TestNetName
+
+	c.ReleaseReferral(net, "friends")
+	c.ReleaseReferral(net, "missing")
+	if referral := c.GetReferralCode(net, "friends"); referral.Uses != 0 {
+		t.Fatalf("uses should not go below 0, got %d", referral.Uses)
+	}
+}
+
+// 已存储推荐码的奖励超过当前 max_bonus 时按 max_bonus 发放
+func TestReserveReferralCapsBonus(t *testing.T) {
+	c := newReferralClient(t)
+	net := c.Config.Axiom.TestNetName
+	if _, err := c.CreateReferralCode(net, "vip", 0, 50); err != nil {
+		t.Fatal(err)
+	}
+
+	if bonus, _, err := c.ReserveReferral(net, "vip"); err != nil || bonus != 10 {
+		t.Fatalf("expect bonus capped at 10, got %v %v", bonus, err)
+	}
+}
+
+// 重启时保留已使用次数，只更新配置中的上限和奖励
+func TestInitReferralCodesKeepsUses(t *testing.T) {
+	c := newReferralClient(t, repo.ReferralCode{Code: "friends", MaxUses: 1, Bonus: 1})
+	net := c.Config.Axiom.TestNetName
+	if _, _, err := c.ReserveReferral(net, "friends"); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Config.Referral.Codes = []repo.ReferralCode{{Code: "friends", MaxUses: 5, Bonus: 2}}
+	if err := c.initReferralCodes(); err != nil {
+		t.Fatal(err)
+	}
+	referral := c.GetReferralCode(net, "friends")
+	if referral.Uses != 1 || referral.MaxUses != 5 || referral.Bonus != 2 {
+		t.Fatalf("unexpected referral %+v", referral)
+	}
+
+	c.Config.Referral.Codes = []repo.ReferralCode{{Code: "greedy", Bonus: 11}}
+	if err := c.initReferralCodes(); err == nil {
+		t.Fatal("bonus above max_bonus should be rejected")
+	}
+}
+
+func TestCreateReferralCode(t *testing.T) {
+	c := newReferralClient(t)
+	net := c.Config.Axiom.TestNetName
+
+	referral, err := c.CreateReferralCode(net, "", 3, 1)
+	if err != nil || len(referral.Code) != 12 {
+		t.Fatalf("expect a random 12 char code, got %+v %v", referral, err)
+	}
+	if _, err := c.CreateReferralCode(net, referral.Code, 1, 0); err == nil {
+		t.Fatal("duplicate code should be rejected")
+	}
+}
+
+func TestSettleTicketReferral(t *testing.T) {
+	c := newReferralClient(t, repo.ReferralCode{Code: "friends"})
+	net := c.Config.Axiom.TestNetName
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.ReserveReferral(net, "friends"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.settleTicketReferral(&Ticket{Net: net, Address: testRecipient, Referral: "friends", Status: TicketSuccess, TxHash: "0x01"})
+	c.settleTicketReferral(&Ticket{Net: net, Address: testRecipient, Referral: "friends", Status: TicketFailed})
+	c.settleTicketReferral(&Ticket{Net: net, Address: testRecipient, Referral: "friends", Status: TicketQueued})
+	if referral := c.GetReferralCode(net, "friends"); referral.Uses != 1 {
+		t.Fatalf("failed ticket should release its use, got %d uses", referral.Uses)
+	}
+	uses := c.ReferralUses(net, "friends")
+	if len(uses) != 1 || uses[0].Address != testRecipient || uses[0].TxHash != "0x01" {
+		t.Fatalf("successful ticket should be attributed, got %+v", uses)
+	}
+}
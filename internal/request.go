@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"context"
+)
+
+// Request 发起领取的请求信息，由接口层放入 context 传给领取流程，排队领取时随凭证保存
+type Request struct {
+	ID string `json:"id,omitempty"`
+	IP string `json:"ip,omitempty"`
+}
+
+type requestKey struct{}
+
+// WithRequest 返回带有请求信息的 context
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// RequestFrom 取出 context 中的请求信息，后台任务等没有请求的场景返回零值
+func RequestFrom(ctx context.Context) Request {
+	req, _ := ctx.Value(requestKey{}).(Request)
+	return req
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestRequestFromContext(t *testing.T) {
+	if req := RequestFrom(context.Background()); req != (Request{}) {
+		t.Fatalf("context without request should give zero value, got %+v", req)
+	}
+	want := Request{ID: "trace-123", IP: "1.2.3.4"}
+	if req := RequestFrom(WithRequest(context.Background(), want)); req != want {
+		t.Fatalf("expect %+v, got %+v", want, req)
+	}
+}
+
+func TestRequestLoggerTagsRequestID(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+
+	entry, ok := c.requestLogger(WithRequest(context.Background(), Request{ID: "trace-123"})).(*logrus.Entry)
+	if !ok || entry.Data["request_id"] != "trace-123" {
+		t.Fatalf("logger should carry the request id, got %v", entry)
+	}
+	if c.requestLogger(context.Background()) != c.logger {
+		t.Fatal("logger without request should not be tagged")
+	}
+}
+
+// 排队领取保存请求信息供 worker 使用，查询凭证时不返回
+func TestEnqueueClaimKeepsRequest(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Queue.Enable = true
+	})
+	req := Request{ID: "trace-123", IP: "1.2.3.4"}
+	ticket, code, err := c.EnqueueClaim(WithRequest(context.Background(), req), c.Config.Axiom.TestNetName, testRecipient, 1, 0, "", "")
+	if err != nil {
+		t.Fatalf("enqueue failed: %d %v", code, err)
+	}
+	if queued := <-c.queue; queued.Request == nil || *queued.Request != req {
+		t.Fatalf("queued ticket should keep the request, got %+v", queued.Request)
+	}
+	if stored, _ := c.GetTicket(ticket.ID); stored.Request != nil {
+		t.Fatalf("ticket lookup should not expose the request, got %+v", stored.Request)
+	}
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// riskSignals 参与风险评分的各项信号
+type riskSignals struct {
+	NewAccount  bool
+	HasBalance  bool
+	Flagged     bool
+	IPAddresses int
+	PriorClaims int
+}
+
+// riskScore 按权重累加命中的信号，返回总分和命中的信号明细
+func riskScore(signals riskSignals, risk repo.Risk) (float64, []string) {
+	var (
+		score   float64
+		factors []string
+	)
+	add := func(name string, weight float64) {
+		if weight == 0 {
+			return
+		}
+		score += weight
+		factors = append(factors, fmt.Sprintf("%s=%g", name, weight))
+	}
+	if signals.NewAccount {
+		add("new_account", risk.NewAccount)
+	}
+	if signals.HasBalance {
+		add("has_balance", risk.HasBalance)
+	}
+	if signals.Flagged {
+		add("flagged", risk.Flagged)
+	}
+	if signals.IPAddresses > 0 {
+		add("ip_addresses", risk.PerIPAddress*float64(signals.IPAddresses))
+	}
+	if signals.PriorClaims > 0 {
+		add("prior_claims", risk.PerPriorClaim*float64(signals.PriorClaims))
+	}
+	return score, factors
+}
+
+// checkRisk 开启风险评分时采集信号并计算总分，超过阈值时拒绝领取
+func (c *Client) checkRisk(ctx context.Context, net string, address string) (int, error) {
+	risk := c.Config.Risk
+	if !risk.Enable {
+		return global.SUCCESS, nil
+	}
+	signals, err := c.collectRiskSignals(ctx, net, address)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	score, factors := riskScore(signals, risk)
+	if score > risk.Threshold {
+		c.requestLogger(ctx).Warnf("reject %s with risk score %g (threshold %g): %s", address, score, risk.Threshold, strings.Join(factors, ", "))
+		return global.RiskRejectedCode, fmt.Errorf(global.RiskRejectedMsg)
+	}
+	if score > 0 {
+		c.requestLogger(ctx).Infof("risk score of %s: %g (threshold %g): %s", address, score, risk.Threshold, strings.Join(factors, ", "))
+	}
+	return global.SUCCESS, nil
+}
+
+// collectRiskSignals 只采集权重不为 0 的信号，减少不必要的 rpc 与存储查询
+func (c *Client) collectRiskSignals(ctx context.Context, net string, address string) (riskSignals, error) {
+	risk := c.Config.Risk
+	signals := riskSignals{}
+	account := common.HexToAddress(address)
+	if risk.NewAccount != 0 {
+		nonce, err := c.axiomClient.NonceAt(context.Background(), account, nil)
+		if err != nil {
+			return signals, err
+		}
+		signals.NewAccount = nonce == 0
+	}
+	if risk.HasBalance != 0 {
+		balance, err := c.axiomClient.BalanceAt(context.Background(), account, nil)
+		if err != nil {
+			return signals, err
+		}
+		signals.HasBalance = balance.Sign() > 0
+	}
+	if risk.Flagged != 0 {
+		note := c.GetAddressNote(net, address)
+		signals.Flagged = note != nil && note.Flag != ""
+	}
+	if ip, ok := c.clientIPBucket(ctx); ok && risk.PerIPAddress != 0 {
+		if value := c.ldb.Get(c.construIPCountKey(net, ip)); value != nil {
+			count, err := strconv.Atoi(string(value))
+			if err != nil {
+				return signals, fmt.Errorf("parse address count of %s: %w", ip, err)
+			}
+			// 不计入当前地址自身
+			if c.ldb.Has(c.construIPAddressKey(net, ip, address)) {
+				count--
+			}
+			signals.IPAddresses = count
+		}
+	}
+	if risk.PerPriorClaim != 0 {
+		signals.PriorClaims = c.ClaimCount(net, address)
+	}
+	return signals, nil
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestRiskScore(t *testing.T) {
+	risk := repo.Risk{NewAccount: 3, HasBalance: 2, Flagged: 5, PerIPAddress: 1.5, PerPriorClaim: 0}
+	score, factors := riskScore(riskSignals{NewAccount: true, Flagged: true, IPAddresses: 2, PriorClaims: 4}, risk)
+	if score != 11 {
+		t.Fatalf("expect 11, got %v", score)
+	}
+	// 权重为 0 的信号不计入明细
+	want := []string{"new_account=3", "flagged=5", "ip_addresses=3"}
+	if len(factors) != len(want) {
+		t.Fatalf("expect factors %v, got %v", want, factors)
+	}
+	for i := range want {
+		if factors[i] != want[i] {
+			t.Fatalf("expect factors %v, got %v", want, factors)
+		}
+	}
+	if score, factors := riskScore(riskSignals{}, risk); score != 0 || len(factors) != 0 {
+		t.Fatalf("no signal should score 0, got %v %v", score, factors)
+	}
+}
+
+func newRiskClient(t *testing.T, node *testutil.Node) *Client {
+	t.Helper()
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Risk = repo.Risk{Enable: true, Threshold: 10, NewAccount: 5, HasBalance: 6, Flagged: 5}
+	})
+}
+
+func TestCheckRiskRejectsAboveThreshold(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newRiskClient(t, node)
+	ctx := context.Background()
+	node.SetBalance(testRecipient, 1)
+
+	if _, code, _ := claim(c, ctx, testRecipient, 1); code != global.RiskRejectedCode {
+		t.Fatalf("new account with balance should be rejected with %d, got %d", global.RiskRejectedCode, code)
+	}
+	// 发送过交易后只剩余额一项，低于阈值
+	node.SetNonce(testRecipient, 1)
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("claim below the threshold failed: %d %v", code, err)
+	}
+}
+
+func TestCheckRiskCountsFlaggedAddress(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newRiskClient(t, node)
+	net := c.Config.Axiom.TestNetName
+	node.SetNonce(testRecipient, 1)
+	node.SetBalance(testRecipient, 1)
+
+	if code, err := c.checkRisk(context.Background(), net, testRecipient); err != nil {
+		t.Fatalf("score 6 should pass, got %d %v", code, err)
+	}
+	if _, err := c.SetAddressNote(net, testRecipient, "suspicious", "watch"); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := c.checkRisk(context.Background(), net, testRecipient); code != global.RiskRejectedCode {
+		t.Fatalf("flagged address should be rejected with %d, got %d", global.RiskRejectedCode, code)
+	}
+}
+
+func TestCheckRiskDisabled(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Risk = repo.Risk{Enable: false, Threshold: 0, NewAccount: 5}
+	})
+
+	if code, err := c.checkRisk(context.Background(), c.Config.Axiom.TestNetName, testRecipient); err != nil {
+		t.Fatalf("risk check should be skipped when disabled, got %d %v", code, err)
+	}
+}
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/pkg/audit"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// SanctionChecker 查询地址是否在制裁/风险名单中，address 为小写
+type SanctionChecker interface {
+	IsSanctioned(ctx context.Context, address string) (bool, error)
+}
+
+// listSanctionChecker 本地名单文件，每行一个地址，# 开头的行为注释
+type listSanctionChecker struct {
+	addresses map[string]struct{}
+}
+
+func newListSanctionChecker(listPath string) (*listSanctionChecker, error) {
+	file, err := os.Open(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("open sanction list: %w", err)
+	}
+	defer file.Close()
+	checker := &listSanctionChecker{addresses: make(map[string]struct{})}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		checker.addresses[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read sanction list: %w", err)
+	}
+	return checker, nil
+}
+
+func (l *listSanctionChecker) IsSanctioned(_ context.Context, address string) (bool, error) {
+	_, ok := l.addresses[address]
+	return ok, nil
+}
+
+// apiSanctionChecker 远程风险接口，url 中的 {address} 替换为待查询地址，
+// 响应 JSON 中 result_field 字段为 true 或非空数组时视为命中
+type apiSanctionChecker struct {
+	config repo.Sanction
+}
+
+func (a *apiSanctionChecker) IsSanctioned(ctx context.Context, address string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout.ToDuration())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(a.config.URL, "{address}", address), nil)
+	if err != nil {
+		return false, err
+	}
+	if a.config.APIKey != "" {
+		req.Header.Set("X-API-Key", a.config.APIKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false, err
+	}
+	result := make(map[string]any)
+	if err := json.Unmarshal(body, &result); err != nil {
+		return false, fmt.Errorf("unmarshal sanction response: %w", err)
+	}
+	switch value := result[a.config.ResultField].(type) {
+	case bool:
+		return value, nil
+	case []any:
+		return len(value) > 0, nil
+	default:
+		return false, nil
+	}
+}
+
+type sanctionResult struct {
+	sanctioned bool
+	expireAt   time.Time
+}
+
+// initSanctionChecker 按配置创建名单检查器，配置了 list_path 时使用本地名单，否则使用远程接口
+func (c *Client) initSanctionChecker(configPath string) error {
+	sanction := c.Config.Sanction
+	if !sanction.Enable {
+		return nil
+	}
+	c.sanctionCache = make(map[string]*sanctionResult)
+	if sanction.ListPath != "" {
+		checker, err := newListSanctionChecker(filepath.Join(configPath, sanction.ListPath))
+		if err != nil {
+			return err
+		}
+		c.sanctionChecker = checker
+		c.logger.Infof("loaded %d address(es) from sanction list", len(checker.addresses))
+		return nil
+	}
+	if sanction.URL == "" {
+		return fmt.Errorf("sanction check is enabled but neither list_path nor url is configured")
+	}
+	sanction.Timeout = repo.Duration(c.outboundTimeout(sanction.Timeout))
+	c.sanctionChecker = &apiSanctionChecker{config: sanction}
+	return nil
+}
+
+// SetSanctionChecker 替换名单检查器并清空缓存
+func (c *Client) SetSanctionChecker(checker SanctionChecker) {
+	c.sanctionLock.Lock()
+	defer c.sanctionLock.Unlock()
+	c.sanctionChecker = checker
+	c.sanctionCache = make(map[string]*sanctionResult)
+}
+
+// checkSanction 命中名单的地址拒绝领取，结果缓存 cache_ttl；查询失败时按 fail_open 决定是否放行
+func (c *Client) checkSanction(ctx context.Context, net string, address string) (int, error) {
+	c.sanctionLock.Lock()
+	checker := c.sanctionChecker
+	cached, ok := c.sanctionCache[address]
+	c.sanctionLock.Unlock()
+	if checker == nil {
+		return global.SUCCESS, nil
+	}
+
+	var sanctioned bool
+	if ok && time.Now().Before(cached.expireAt) {
+		sanctioned = cached.sanctioned
+	} else {
+		var err error
+		sanctioned, err = checker.IsSanctioned(c.ctx, address)
+		if err != nil {
+			c.requestLogger(ctx).Errorf("sanction check of %s failed: %v", address, err)
+			if c.Config.Sanction.FailOpen {
+				return global.SUCCESS, nil
+			}
+			return global.ScreeningErrCode, fmt.Errorf(global.ScreeningErrMsg)
+		}
+		c.sanctionLock.Lock()
+		c.sanctionCache[address] = &sanctionResult{sanctioned: sanctioned, expireAt: time.Now().Add(c.Config.Sanction.CacheTTL.ToDuration())}
+		c.sanctionLock.Unlock()
+	}
+	if sanctioned {
+		c.requestLogger(ctx).Warnf("sanctioned address %s rejected", address)
+		c.Audit(ctx, &audit.Event{Type: audit.EventClaimBlocked, Net: net, Address: address, Detail: "sanctioned"})
+		return global.SanctionedCode, fmt.Errorf(global.SanctionedMsg)
+	}
+	return global.SUCCESS, nil
+}
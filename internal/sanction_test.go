@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// countingChecker 记录查询次数，err 不为空时查询失败
+type countingChecker struct {
+	sanctioned map[string]bool
+	err        error
+	calls      int
+}
+
+func (s *countingChecker) IsSanctioned(_ context.Context, address string) (bool, error) {
+	s.calls++
+	return s.sanctioned[address], s.err
+}
+
+func newSanctionClient(t *testing.T, setup func(cfg *repo.Sanction)) *Client {
+	t.Helper()
+	return newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Sanction.CacheTTL = repo.Duration(time.Minute)
+		if setup != nil {
+			setup(&cfg.Sanction)
+		}
+	})
+}
+
+func TestClaimRejectsSanctionedAddress(t *testing.T) {
+	c := newSanctionClient(t, nil)
+	checker := &countingChecker{sanctioned: map[string]bool{testRecipient: true}}
+	c.SetSanctionChecker(checker)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, code, _ := claim(c, ctx, testRecipient, 1); code != global.SanctionedCode {
+			t.Fatalf("expect %d, got %d", global.SanctionedCode, code)
+		}
+	}
+	if checker.calls != 1 {
+		t.Fatalf("result should be cached, got %d queries", checker.calls)
+	}
+	if _, code, err := claim(c, ctx, "0x2222222222222222222222222222222222222222", 1); err != nil {
+		t.Fatalf("clean address should pass, got %d %v", code, err)
+	}
+}
+
+func TestCheckSanctionFailure(t *testing.T) {
+	for _, failOpen := range []bool{false, true} {
+		c := newSanctionClient(t, func(cfg *repo.Sanction) {
+			cfg.FailOpen = failOpen
+		})
+		c.SetSanctionChecker(&countingChecker{err: errors.New("timeout")})
+
+		want := global.ScreeningErrCode
+		if failOpen {
+			want = global.SUCCESS
+		}
+		if code, _ := c.checkSanction(context.Background(), c.Config.Axiom.TestNetName, testRecipient); code != want {
+			t.Fatalf("fail_open=%v: expect %d, got %d", failOpen, want, code)
+		}
+	}
+}
+
+func TestListSanctionChecker(t *testing.T) {
+	listPath := filepath.Join(t.TempDir(), "sanctions.txt")
+	content := "# ofac\n\n  0xABCDEFabcdefABCDEFabcdefABCDEFabcdefABCD  \n"
+	if err := os.WriteFile(listPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checker, err := newListSanctionChecker(listPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checker.addresses) != 1 {
+		t.Fatalf("comments and blank lines should be skipped, got %v", checker.addresses)
+	}
+	if ok, _ := checker.IsSanctioned(context.Background(), "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"); !ok {
+		t.Fatal("listed address should match case-insensitively")
+	}
+	if _, err := newListSanctionChecker(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Fatal("missing list should be reported")
+	}
+}
+
+func TestAPISanctionChecker(t *testing.T) {
+	responses := map[string]string{
+		testRecipient: `{"sanctioned": true}`,
+		"0x2222222222222222222222222222222222222222": `{"sanctioned": ["ofac"]}`,
+		"0x3333333333333333333333333333333333333333": `{"sanctioned": []}`,
+		"0x4444444444444444444444444444444444444444": `{"other": true}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, responses[r.URL.Query().Get("address")])
+	}))
+	t.Cleanup(server.Close)
+	checker := &apiSanctionChecker{config: repo.Sanction{
+		URL:         server.URL + "?address={address}",
+		APIKey:      "api-key",
+		ResultField: "sanctioned",
+		Timeout:     repo.Duration(time.Second),
+	}}
+
+	for address, want := range map[string]bool{
+		testRecipient: true,
+		"0x2222222222222222222222222222222222222222": true,
+		"0x3333333333333333333333333333333333333333": false,
+		"0x4444444444444444444444444444444444444444": false,
+	} {
+		got, err := checker.IsSanctioned(context.Background(), address)
+		if err != nil || got != want {
+			t.Fatalf("%s: expect %v, got %v %v", address, want, got, err)
+		}
+	}
+
+	checker.config.APIKey = "wrong"
+	if _, err := checker.IsSanctioned(context.Background(), testRecipient); err == nil {
+		t.Fatal("non-200 status should be reported")
+	}
+}
+
+func TestInitSanctionCheckerRequiresSource(t *testing.T) {
+	c := newSanctionClient(t, nil)
+	c.Config.Sanction.Enable = true
+
+	if err := c.initSanctionChecker(t.TempDir()); err == nil {
+		t.Fatal("enabled check without list_path or url should be rejected")
+	}
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/internal/contract"
+)
+
+// SelfTest 启动时模拟一次领取（不发送交易），提前暴露 rpc、余额以及合约权限等配置问题，链 id 已在连接时校验
+func (c *Client) SelfTest() error {
+	c.axiomLock.Lock()
+	auth := c.axiomAuth
+	c.axiomLock.Unlock()
+	if _, err := c.checkChain(); err != nil {
+		return fmt.Errorf("self-test: claims on %s are disabled by chain id mismatch", c.Config.Axiom.TestNetName)
+	}
+	if !c.FundingReady() || auth == nil {
+		return fmt.Errorf("self-test: funding key of %s not loaded", c.Config.Axiom.TestNetName)
+	}
+	ctx := context.Background()
+	chainId, err := c.axiomClient.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("self-test: query chain id from %s: %w", c.Config.Axiom.AxiomAddr, err)
+	}
+
+	// 发放的代币来自水龙头合约，gas 由发送账户支付
+	contractAddress := common.HexToAddress(c.Config.Axiom.FaucetAddr)
+	faucetBalance, err := c.axiomClient.BalanceAt(ctx, contractAddress, nil)
+	if err != nil {
+		return fmt.Errorf("self-test: query faucet contract balance: %w", err)
+	}
+	amount := floatToEtherBigInt(c.Config.Axiom.Amount)
+	if c.Config.Axiom.TweetAmount > c.Config.Axiom.Amount {
+		amount = floatToEtherBigInt(c.Config.Axiom.TweetAmount)
+	}
+	if faucetBalance.Cmp(amount) < 0 {
+		return fmt.Errorf("self-test: faucet contract %s balance %s is less than claim amount %s", contractAddress, faucetBalance, amount)
+	}
+	senderBalance, err := c.axiomClient.BalanceAt(ctx, auth.From, nil)
+	if err != nil {
+		return fmt.Errorf("self-test: query sender balance: %w", err)
+	}
+	if senderBalance.Sign() == 0 {
+		return fmt.Errorf("self-test: sender %s has no balance to pay gas", auth.From)
+	}
+
+	// 向随机生成的新地址模拟 drip，校验发送账户是否为合约 owner
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return err
+	}
+	contractAbi, err := abi.JSON(strings.NewReader(contract.TaurusFaucetABI))
+	if err != nil {
+		return err
+	}
+	input, err := contractAbi.Pack("drip", crypto.PubkeyToAddress(key.PublicKey), amount)
+	if err != nil {
+		return err
+	}
+	if _, err := c.axiomClient.CallContract(ctx, ethereum.CallMsg{
+		From:  auth.From,
+		To:    &contractAddress,
+		Data:  input,
+		Value: big.NewInt(0),
+	}, nil); err != nil {
+		return fmt.Errorf("self-test: simulate drip from %s: %w", auth.From, err)
+	}
+
+	c.logger.Infof("self-test passed, chain id: %s, sender: %s, faucet balance: %s", chainId, auth.From, faucetBalance)
+	return nil
+}
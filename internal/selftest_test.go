@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestSelfTestPassesWithoutSending(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+
+	if err := c.SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+	if calls := node.Calls("eth_call"); calls != 1 {
+		t.Fatalf("expect one simulated drip, got %d", calls)
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("self-test must not send txs, got %d", len(sent))
+	}
+}
+
+func TestSelfTestFailures(t *testing.T) {
+	for name, tc := range map[string]struct {
+		prepare func(node *testutil.Node, c *Client)
+		want    string
+	}{
+		"empty faucet": {
+			prepare: func(node *testutil.Node, c *Client) { node.SetBalance(c.Config.Axiom.FaucetAddr, 0) },
+			want:    "balance",
+		},
+		"sender without gas": {
+			prepare: func(node *testutil.Node, c *Client) { node.SetBalance(c.FundingAddress(), 0) },
+			want:    "no balance to pay gas",
+		},
+		"drip reverts": {
+			prepare: func(node *testutil.Node, c *Client) {
+				node.Handle("eth_call", func([]json.RawMessage) (any, error) {
+					return nil, &testutil.RPCError{Code: 3, Message: "execution reverted: Ownable: caller is not the owner"}
+				})
+			},
+			want: "simulate drip",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			node := testutil.NewNode(t)
+			c := newTestClient(t, node, nil)
+			tc.prepare(node, c)
+
+			err := c.SelfTest()
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expect error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
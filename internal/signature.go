@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// ClaimMessage 签名领取时钱包签名的消息，包含链 id 防止跨链重放
+func ClaimMessage(net string, address string, chainId uint64, timestamp int64) string {
+	return fmt.Sprintf("Axiom faucet claim\nnet: %s\naddress: %s\nchainId: %d\ntimestamp: %d", net, strings.ToLower(address), chainId, timestamp)
+}
+
+// ChainID 返回当前网络的链 id，未配置时从节点查询并缓存
+func (c *Client) ChainID() (*big.Int, error) {
+	if c.Config.Axiom.ChainID != 0 {
+		return new(big.Int).SetUint64(c.Config.Axiom.ChainID), nil
+	}
+	c.chainIDOnce.Do(func() {
+		c.chainID, c.chainIDErr = c.axiomClient.ChainID(context.Background())
+	})
+	return c.chainID, c.chainIDErr
+}
+
+// VerifyClaimSignature 校验签名领取的链 id、时间窗口，并从 personal_sign 签名中恢复地址
+func (c *Client) VerifyClaimSignature(net string, address string, chainId uint64, timestamp int64, signature string) (int, error) {
+	expectChainId, err := c.ChainID()
+	if err != nil {
+		c.logger.Error(err)
+		return global.BlockChainCode, fmt.Errorf(global.BlockChainMsg)
+	}
+	if chainId != expectChainId.Uint64() {
+		return global.ChainMismatchCode, fmt.Errorf(global.ChainMismatchMsg + expectChainId.String())
+	}
+
+	window := int64(c.Config.SignatureClaim.Window.ToDuration().Seconds())
+	now := time.Now().Unix()
+	if timestamp < now-window || timestamp > now+window {
+		return global.SignatureErrCode, fmt.Errorf(global.SignatureErrMsg)
+	}
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return global.SignatureErrCode, fmt.Errorf(global.SignatureErrMsg)
+	}
+	// 钱包签名的 v 为 27/28
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(ClaimMessage(net, address, chainId, timestamp))), sig)
+	if err != nil {
+		return global.SignatureErrCode, fmt.Errorf(global.SignatureErrMsg)
+	}
+	if crypto.PubkeyToAddress(*pub) != common.HexToAddress(address) {
+		return global.SignatureErrCode, fmt.Errorf(global.SignatureErrMsg)
+	}
+	return global.SUCCESS, nil
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+// signClaim 按钱包 personal_sign 的格式签名领取消息，v 为 27/28
+func signClaim(t *testing.T, key *ecdsa.PrivateKey, net string, chainId uint64, timestamp int64) string {
+	t.Helper()
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(ClaimMessage(net, address, chainId, timestamp))), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
+
+func TestVerifyClaimSignature(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	net := c.Config.Axiom.TestNetName
+	chainId := node.ChainID()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	now := time.Now().Unix()
+	expired := now - int64(c.Config.SignatureClaim.Window.ToDuration().Seconds()) - 60
+
+	for _, test := range []struct {
+		name      string
+		chainId   uint64
+		timestamp int64
+		signature string
+		code      int
+	}{
+		{"valid", chainId, now, signClaim(t, key, net, chainId, now), global.SUCCESS},
+		{"other chain", chainId + 1, now, signClaim(t, key, net, chainId+1, now), global.ChainMismatchCode},
+		{"expired", chainId, expired, signClaim(t, key, net, chainId, expired), global.SignatureErrCode},
+		{"other signer", chainId, now, signClaim(t, other, net, chainId, now), global.SignatureErrCode},
+		{"malformed", chainId, now, "0x1234", global.SignatureErrCode},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			code, _ := c.VerifyClaimSignature(net, address, test.chainId, test.timestamp, test.signature)
+			if code != test.code {
+				t.Fatalf("expect %d, got %d", test.code, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/axiomesh/faucet/persist"
+)
+
+// ClaimCounter 领取计数，按网络累计以及按天累计
+type ClaimCounter struct {
+	Claims          uint64            `json:"claims"`
+	UniqueAddresses uint64            `json:"uniqueAddresses"`
+	Disbursed       float64           `json:"disbursed"`
+	Sources         map[string]uint64 `json:"sources,omitempty"`
+}
+
+type Stats struct {
+	Net   string       `json:"net"`
+	Total ClaimCounter `json:"total"`
+	Today ClaimCounter `json:"today"`
+}
+
+type statsCache struct {
+	stats    *Stats
+	expireAt time.Time
+}
+
+// Stats 返回指定网络的汇总数据，结果按配置的 TTL 缓存
+func (c *Client) Stats(net string) (*Stats, error) {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	if cache, ok := c.statsCache[net]; ok && time.Now().Before(cache.expireAt) {
+		return cache.stats, nil
+	}
+
+	stats := &Stats{Net: net}
+	if err := c.getCounter(c.construStatsKey(net, "total"), &stats.Total); err != nil {
+		return nil, err
+	}
+	if err := c.getCounter(c.construStatsKey(net, time.Now().Format("2006-01-02")), &stats.Today); err != nil {
+		return nil, err
+	}
+	c.statsCache[net] = &statsCache{
+		stats:    stats,
+		expireAt: time.Now().Add(c.Config.Network.StatsCacheTTL.ToDuration()),
+	}
+	return stats, nil
+}
+
+// incrStats 在领取记录写入后更新计数，firstClaim 表示该地址首次被领取，当天的不同地址数按当天是否已领取过单独判断；
+// source 为领取来源标记
+func (c *Client) incrStats(net string, address string, amount float64, firstClaim bool, source string) error {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+	day := time.Now().Format("2006-01-02")
+	dayAddressKey := c.construStatsAddressKey(net, day, address)
+	firstToday := !c.ldb.Has(dayAddressKey)
+	for _, period := range []struct {
+		key   []byte
+		first bool
+	}{
+		{c.construStatsKey(net, "total"), firstClaim},
+		{c.construStatsKey(net, day), firstToday},
+	} {
+		key := period.key
+		counter := ClaimCounter{}
+		if err := c.getCounter(key, &counter); err != nil {
+			return err
+		}
+		counter.Claims++
+		counter.Disbursed += amount
+		if period.first {
+			counter.UniqueAddresses++
+		}
+		if source != "" {
+			if counter.Sources == nil {
+				counter.Sources = make(map[string]uint64)
+			}
+			counter.Sources[source]++
+		}
+		value, err := json.Marshal(counter)
+		if err != nil {
+			return err
+		}
+		c.ldb.Put(key, value)
+	}
+	if firstToday {
+		c.ldb.Put(dayAddressKey, []byte("1"))
+	}
+	delete(c.statsCache, net)
+	return nil
+}
+
+func (c *Client) getCounter(key []byte, counter *ClaimCounter) error {
+	value := c.ldb.Get(key)
+	if value == nil {
+		return nil
+	}
+	return json.Unmarshal(value, counter)
+}
+
+// construStatsAddressKey 记录地址当天已计入不同地址数
+func (c *Client) construStatsAddressKey(net string, day string, address string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("statsaddr-")
+	buffer.WriteString(day)
+	buffer.WriteString("-")
+	buffer.WriteString(address)
+	return persist.CompositeKey(net, buffer)
+}
+
+func (c *Client) construStatsKey(net string, period string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("stats-")
+	buffer.WriteString(period)
+	return persist.CompositeKey(net, buffer)
+}
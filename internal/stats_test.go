@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestIncrStatsCountsUniqueAddresses(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	net := c.Config.Axiom.TestNetName
+	other := "0x2222222222222222222222222222222222222222"
+
+	for _, claim := range []struct {
+		address    string
+		firstClaim bool
+		source     string
+	}{
+		{testRecipient, true, "campaign"},
+		{testRecipient, false, ""},
+		{other, true, "campaign"},
+	} {
+		if err := c.incrStats(net, claim.address, 10, claim.firstClaim, claim.source); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := c.Stats(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, counter := range map[string]ClaimCounter{"total": stats.Total, "today": stats.Today} {
+		if counter.Claims != 3 || counter.UniqueAddresses != 2 || counter.Disbursed != 30 {
+			t.Fatalf("%s: expect 3 claims from 2 addresses disbursing 30, got %+v", name, counter)
+		}
+		if counter.Sources["campaign"] != 2 {
+			t.Fatalf("%s: expect 2 claims from campaign, got %v", name, counter.Sources)
+		}
+	}
+}
+
+// 历史上领取过的地址当天再次领取时，仍计入当天的不同地址数
+func TestIncrStatsCountsReturningAddressToday(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	net := c.Config.Axiom.TestNetName
+
+	if err := c.incrStats(net, testRecipient, 10, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := c.Stats(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Today.UniqueAddresses != 1 {
+		t.Fatalf("returning address should count once today, got %d", stats.Today.UniqueAddresses)
+	}
+	if stats.Total.UniqueAddresses != 0 {
+		t.Fatalf("returning address should not count again in total, got %d", stats.Total.UniqueAddresses)
+	}
+}
+
+func TestStatsIsCachedUntilNextClaim(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	net := c.Config.Axiom.TestNetName
+
+	before, err := c.Stats(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.incrStats(net, testRecipient, 10, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	after, err := c.Stats(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Total.Claims != 0 || after.Total.Claims != 1 {
+		t.Fatalf("a claim should invalidate the cached stats, got %d then %d", before.Total.Claims, after.Total.Claims)
+	}
+}
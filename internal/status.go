@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+type Status struct {
+	Net         string `json:"net"`
+	ChainID     uint64 `json:"chainId"`
+	BlockNumber uint64 `json:"blockNumber"`
+	// ChainLag 节点最新区块时间落后当前时间的秒数
+	ChainLag      int64   `json:"chainLag"`
+	FaucetBalance float64 `json:"faucetBalance"`
+	// AvailableBalance 扣除 gas 保留额度（开启 reserve_in_flight 时还扣除在途领取）后可发放的余额
+	AvailableBalance float64 `json:"availableBalance"`
+	// ReservedBalance 已发出、尚未确认的领取数量之和
+	ReservedBalance float64 `json:"reservedBalance"`
+	// LowBalance 最近一段时间内发送交易时出现过资金不足
+	LowBalance  bool  `json:"lowBalance"`
+	Paused      bool  `json:"paused"`
+	PausedUntil int64 `json:"pausedUntil,omitempty"`
+	// BalanceTier 按当前余额生效的发放档位，未配置 balance_tiers 时为空
+	BalanceTier *repo.BalanceTier `json:"balanceTier,omitempty"`
+	// BaseFee 节点最新区块的 base fee（gwei），CongestionTier 为按 base fee 生效的拥堵档位，未配置或低于所有档位时为空
+	BaseFee        float64              `json:"baseFee"`
+	CongestionTier *repo.CongestionTier `json:"congestionTier,omitempty"`
+	// FundingAddress 发送领取交易的资金账户地址
+	FundingAddress string `json:"fundingAddress,omitempty"`
+	// Event 限时活动的起止时间，未配置活动时为空
+	Event *EventWindow `json:"event,omitempty"`
+}
+
+// Status 查询水龙头当前运行状态
+func (c *Client) Status() (*Status, error) {
+	ctx := context.Background()
+	chainId, err := c.axiomClient.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	header, err := c.axiomClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := c.faucetBalance(false)
+	if err != nil {
+		return nil, err
+	}
+	reserved := c.reservedFunds()
+	available := new(big.Int).Set(balance)
+	if c.Config.Axiom.ReserveInFlight {
+		available.Sub(available, reserved)
+	}
+	status := &Status{
+		Net:              c.Config.Axiom.TestNetName,
+		ChainID:          chainId.Uint64(),
+		BlockNumber:      header.Number.Uint64(),
+		ChainLag:         int64(chainLag(header).Seconds()),
+		FaucetBalance:    etherBigIntToFloat(balance),
+		AvailableBalance: etherBigIntToFloat(available),
+		ReservedBalance:  etherBigIntToFloat(reserved),
+		LowBalance:       c.lowBalance(),
+		FundingAddress:   c.FundingAddress(),
+		BalanceTier:      balanceTier(c.Config.Axiom.BalanceTiers, etherBigIntToFloat(balance)),
+		BaseFee:          baseFeeGwei(header),
+		CongestionTier:   congestionTier(c.Config.Axiom.CongestionTiers, baseFeeGwei(header)),
+		Event:            c.Event(time.Now()),
+	}
+	if paused, until := c.InMaintenance(time.Now()); paused {
+		status.Paused = true
+		status.PausedUntil = until.Unix()
+	}
+	return status, nil
+}
+
+// 资金不足信号的持续时间
+const lowBalanceSignalTTL = 5 * time.Minute
+
+// isInsufficientFunds 节点返回 insufficient funds for gas * price + value 时说明发送账户余额不足
+func isInsufficientFunds(err error) bool {
+	return strings.Contains(err.Error(), "insufficient funds")
+}
+
+func (c *Client) markLowBalance() {
+	atomic.StoreInt64(&c.lowBalanceAt, time.Now().Unix())
+}
+
+func (c *Client) lowBalance() bool {
+	at := atomic.LoadInt64(&c.lowBalanceAt)
+	return at != 0 && time.Since(time.Unix(at, 0)) < lowBalanceSignalTTL
+}
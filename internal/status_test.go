@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+// handleOutOfFunds 让节点以资金不足拒绝所有交易
+func handleOutOfFunds(node *testutil.Node) {
+	node.Handle("eth_sendRawTransaction", func([]json.RawMessage) (any, error) {
+		return nil, &testutil.RPCError{Code: -32000, Message: "insufficient funds for gas * price + value"}
+	})
+}
+
+func TestClaimReportsOutOfFunds(t *testing.T) {
+	node := testutil.NewNode(t)
+	handleOutOfFunds(node)
+	c := newTestClient(t, node, nil)
+
+	if c.lowBalance() {
+		t.Fatal("low balance should not be signalled before any failure")
+	}
+	if _, code, _ := claim(c, context.Background(), testRecipient, 1); code != global.OutOfFundsCode {
+		t.Fatalf("expect %d, got %d", global.OutOfFundsCode, code)
+	}
+	status, err := c.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.LowBalance {
+		t.Fatal("status should signal low balance after an out-of-funds failure")
+	}
+}
@@ -0,0 +1,428 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultChainID 测试节点默认的链 id
+const DefaultChainID = 1356
+
+// Handler 处理一个 json-rpc 方法，返回值按 json 编码作为 result
+type Handler func(params []json.RawMessage) (any, error)
+
+// RPCError 由 Handler 返回时作为 json-rpc 错误响应
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// HTTPError 由 Handler 返回时以该状态码响应，模拟 rpc 服务商限流等 http 层错误
+type HTTPError struct {
+	Status int
+}
+
+func (e *HTTPError) Error() string {
+	return http.StatusText(e.Status)
+}
+
+// Node 测试用的以太坊 json-rpc 节点，默认实现领取流程用到的接口：
+// 记录余额与 nonce，接收签名交易并按 AutoReceipt 生成成功回执
+type Node struct {
+	*httptest.Server
+
+	lock        sync.Mutex
+	chainID     uint64
+	balances    map[common.Address]*big.Int
+	nonces      map[common.Address]uint64
+	txs         map[common.Hash]*types.Transaction
+	receipts    map[common.Hash]*types.Receipt
+	handlers    map[string]Handler
+	calls       map[string]int
+	sent        []*types.Transaction
+	blockNumber uint64
+	blockTime   time.Time
+	baseFee     *big.Int
+	autoReceipt bool
+}
+
+// NewNode 启动测试节点，测试结束时关闭
+func NewNode(t testing.TB) *Node {
+	n := &Node{
+		chainID:     DefaultChainID,
+		balances:    make(map[common.Address]*big.Int),
+		nonces:      make(map[common.Address]uint64),
+		txs:         make(map[common.Hash]*types.Transaction),
+		receipts:    make(map[common.Hash]*types.Receipt),
+		handlers:    make(map[string]Handler),
+		calls:       make(map[string]int),
+		blockNumber: 100,
+		baseFee:     big.NewInt(1e9),
+		autoReceipt: true,
+	}
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(n.Close)
+	return n
+}
+
+// ChainID 节点返回的链 id
+func (n *Node) ChainID() uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.chainID
+}
+
+// SetChainID 修改节点返回的链 id
+func (n *Node) SetChainID(id uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.chainID = id
+}
+
+// SetBalance 设置地址的余额，单位为 ether
+func (n *Node) SetBalance(address string, ether float64) {
+	wei, _ := new(big.Float).Mul(big.NewFloat(ether), big.NewFloat(1e18)).Int(nil)
+	n.SetBalanceWei(address, wei)
+}
+
+// SetBalanceWei 设置地址的余额，单位为 wei
+func (n *Node) SetBalanceWei(address string, wei *big.Int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.balances[common.HexToAddress(address)] = new(big.Int).Set(wei)
+}
+
+// SetNonce 设置地址的 nonce，同时作为 latest 与 pending nonce 返回
+func (n *Node) SetNonce(address string, nonce uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.nonces[common.HexToAddress(address)] = nonce
+}
+
+// SetBlock 设置最新区块的高度、时间与 base fee
+func (n *Node) SetBlock(number uint64, at time.Time, baseFee *big.Int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.blockNumber, n.blockTime, n.baseFee = number, at, baseFee
+}
+
+// SetAutoReceipt 为 false 时收到的交易不生成回执，模拟交易等待打包
+func (n *Node) SetAutoReceipt(auto bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.autoReceipt = auto
+}
+
+// Mine 为已收到的交易生成回执，status 为回执状态
+func (n *Node) Mine(hash common.Hash, status uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if tx, ok := n.txs[hash]; ok {
+		n.receipts[hash] = n.receipt(tx, status)
+	}
+}
+
+// Drop 从节点中删除交易及其回执，模拟交易被丢弃
+func (n *Node) Drop(hash common.Hash) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.txs, hash)
+	delete(n.receipts, hash)
+}
+
+// Handle 替换某个方法的默认实现
+func (n *Node) Handle(method string, handler Handler) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.handlers[method] = handler
+}
+
+// Calls 返回某个方法被调用的次数
+func (n *Node) Calls(method string) int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.calls[method]
+}
+
+// Sent 返回节点收到的全部交易
+func (n *Node) Sent() []*types.Transaction {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([]*types.Transaction(nil), n.sent...)
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+func (n *Node) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.lock.Lock()
+	n.calls[req.Method]++
+	handler, ok := n.handlers[req.Method]
+	n.lock.Unlock()
+	if !ok {
+		handler = n.defaultHandler(req.Method)
+	}
+
+	res := response{Version: "2.0", ID: req.ID}
+	if handler == nil {
+		res.Error = &rpcError{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method)}
+	} else if result, err := handler(req.Params); err != nil {
+		switch e := err.(type) {
+		case *HTTPError:
+			http.Error(w, e.Error(), e.Status)
+			return
+		case *RPCError:
+			res.Error = &rpcError{Code: e.Code, Message: e.Message}
+		default:
+			res.Error = &rpcError{Code: -32000, Message: err.Error()}
+		}
+	} else {
+		res.Result = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (n *Node) defaultHandler(method string) Handler {
+	switch method {
+	case "eth_chainId":
+		return func([]json.RawMessage) (any, error) {
+			return hexutil.Uint64(n.ChainID()), nil
+		}
+	case "net_version":
+		return func([]json.RawMessage) (any, error) {
+			return fmt.Sprint(n.ChainID()), nil
+		}
+	case "eth_getBalance":
+		return func(params []json.RawMessage) (any, error) {
+			address, err := addressParam(params)
+			if err != nil {
+				return nil, err
+			}
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			balance := n.balances[address]
+			if balance == nil {
+				balance = new(big.Int)
+			}
+			return (*hexutil.Big)(balance), nil
+		}
+	case "eth_getTransactionCount":
+		return func(params []json.RawMessage) (any, error) {
+			address, err := addressParam(params)
+			if err != nil {
+				return nil, err
+			}
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			return hexutil.Uint64(n.nonces[address]), nil
+		}
+	case "eth_gasPrice", "eth_maxPriorityFeePerGas":
+		return func([]json.RawMessage) (any, error) {
+			return (*hexutil.Big)(big.NewInt(1e9)), nil
+		}
+	case "eth_estimateGas":
+		return func([]json.RawMessage) (any, error) {
+			return hexutil.Uint64(50000), nil
+		}
+	case "eth_call":
+		return func([]json.RawMessage) (any, error) {
+			return hexutil.Bytes{}, nil
+		}
+	case "eth_getCode":
+		// 任意地址都视为已部署合约，绑定合约发送交易前会检查合约代码
+		return func([]json.RawMessage) (any, error) {
+			return hexutil.Bytes{0x00}, nil
+		}
+	case "eth_blockNumber":
+		return func([]json.RawMessage) (any, error) {
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			return hexutil.Uint64(n.blockNumber), nil
+		}
+	case "eth_getBlockByNumber":
+		return func([]json.RawMessage) (any, error) {
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			return n.header(), nil
+		}
+	case "eth_getLogs":
+		return func([]json.RawMessage) (any, error) {
+			return []*types.Log{}, nil
+		}
+	case "eth_sendRawTransaction":
+		return n.sendRawTransaction
+	case "eth_getTransactionReceipt":
+		return func(params []json.RawMessage) (any, error) {
+			hash, err := hashParam(params)
+			if err != nil {
+				return nil, err
+			}
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			if receipt, ok := n.receipts[hash]; ok {
+				return receipt, nil
+			}
+			return nil, nil
+		}
+	case "eth_getTransactionByHash":
+		return func(params []json.RawMessage) (any, error) {
+			hash, err := hashParam(params)
+			if err != nil {
+				return nil, err
+			}
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			tx, ok := n.txs[hash]
+			if !ok {
+				return nil, nil
+			}
+			return n.rpcTransaction(tx)
+		}
+	}
+	return nil
+}
+
+func (n *Node) sendRawTransaction(params []json.RawMessage) (any, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("missing raw transaction")
+	}
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(params[0], &raw); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.txs[tx.Hash()] = tx
+	n.sent = append(n.sent, tx)
+	if tx.Nonce() >= n.nonces[from] {
+		n.nonces[from] = tx.Nonce() + 1
+	}
+	if n.autoReceipt {
+		n.receipts[tx.Hash()] = n.receipt(tx, types.ReceiptStatusSuccessful)
+	}
+	return tx.Hash(), nil
+}
+
+// receipt 调用方需持有 lock
+func (n *Node) receipt(tx *types.Transaction, status uint64) *types.Receipt {
+	return &types.Receipt{
+		Type:              tx.Type(),
+		Status:            status,
+		CumulativeGasUsed: 21000,
+		Logs:              []*types.Log{},
+		TxHash:            tx.Hash(),
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(1e9),
+		BlockHash:         common.BigToHash(new(big.Int).SetUint64(n.blockNumber)),
+		BlockNumber:       new(big.Int).SetUint64(n.blockNumber),
+	}
+}
+
+// header 调用方需持有 lock
+func (n *Node) header() *types.Header {
+	at := n.blockTime
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return &types.Header{
+		Number:     new(big.Int).SetUint64(n.blockNumber),
+		Difficulty: new(big.Int),
+		GasLimit:   30000000,
+		Time:       uint64(at.Unix()),
+		BaseFee:    n.baseFee,
+		Extra:      []byte{},
+	}
+}
+
+// rpcTransaction 调用方需持有 lock，已打包的交易附带区块信息
+func (n *Node) rpcTransaction(tx *types.Transaction) (any, error) {
+	raw, err := tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]any)
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if receipt, ok := n.receipts[tx.Hash()]; ok {
+		fields["blockNumber"] = (*hexutil.Big)(receipt.BlockNumber)
+		fields["blockHash"] = receipt.BlockHash
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		fields["from"] = from
+	}
+	return fields, nil
+}
+
+func addressParam(params []json.RawMessage) (common.Address, error) {
+	if len(params) == 0 {
+		return common.Address{}, fmt.Errorf("missing address")
+	}
+	var address string
+	if err := json.Unmarshal(params[0], &address); err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(strings.TrimSpace(address)), nil
+}
+
+func hashParam(params []json.RawMessage) (common.Hash, error) {
+	if len(params) == 0 {
+		return common.Hash{}, fmt.Errorf("missing hash")
+	}
+	var hash common.Hash
+	if err := json.Unmarshal(params[0], &hash); err != nil {
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// 部分 rpc 服务商限流时返回的 JSON-RPC 错误码
+const rpcLimitExceededCode = -32005
+
+// isRPCThrottled 判断错误是否为 rpc 服务商限流，与链上执行失败等错误区分
+func isRPCThrottled(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcLimitExceededCode {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "too many requests")
+}
+
+// sendTxAxmWithRetry 发送领取交易，rpc 服务商限流时按 rpc_retry_backoff 指数退避重试，最多重试 rpc_retry_budget 次
+func (c *Client) sendTxAxmWithRetry(ctx context.Context, address string, amount float64) (*types.Transaction, uint64, error) {
+	backoff := c.Config.Axiom.RPCRetryBackoff.ToDuration()
+	for attempt := 0; ; attempt++ {
+		tx, estimate, err := sendTxAxm(ctx, c, address, amount)
+		if err == nil || !isRPCThrottled(err) {
+			return tx, estimate, err
+		}
+		throttled := atomic.AddInt64(&c.rpcThrottled, 1)
+		if attempt >= c.Config.Axiom.RPCRetryBudget {
+			c.requestLogger(ctx).Warnf("rpc throttled, retry budget %d exhausted (total throttled: %d): %v", c.Config.Axiom.RPCRetryBudget, throttled, err)
+			return nil, 0, err
+		}
+		wait := backoff << attempt
+		c.requestLogger(ctx).Warnf("rpc throttled, retry after %s (total throttled: %d): %v", wait, throttled, err)
+		select {
+		case <-c.ctx.Done():
+			return nil, 0, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RPCThrottledCount 启动以来 rpc 服务商限流的次数
+func (c *Client) RPCThrottledCount() int64 {
+	return atomic.LoadInt64(&c.rpcThrottled)
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+type testRPCError struct {
+	code int
+}
+
+func (e testRPCError) Error() string  { return "limit exceeded" }
+func (e testRPCError) ErrorCode() int { return e.code }
+
+func TestIsRPCThrottled(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{fmt.Errorf("send tx: %w", rpc.HTTPError{StatusCode: http.StatusTooManyRequests}), true},
+		{rpc.HTTPError{StatusCode: http.StatusBadGateway}, false},
+		{testRPCError{code: rpcLimitExceededCode}, true},
+		{testRPCError{code: -32000}, false},
+		{errors.New("429 Too Many Requests"), true},
+		{errors.New("execution reverted"), false},
+	} {
+		if got := isRPCThrottled(tc.err); got != tc.want {
+			t.Fatalf("%v: expect %v, got %v", tc.err, tc.want, got)
+		}
+	}
+}
+
+// handleThrottled 前 failures 次 eth_gasPrice 请求返回 429，返回请求次数
+func handleThrottled(node *testutil.Node, failures int32) *int32 {
+	var calls int32
+	node.Handle("eth_gasPrice", func([]json.RawMessage) (any, error) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			return nil, &testutil.HTTPError{Status: http.StatusTooManyRequests}
+		}
+		return (*hexutil.Big)(big.NewInt(1e9)), nil
+	})
+	return &calls
+}
+
+func newThrottleClient(t *testing.T, node *testutil.Node, budget int) *Client {
+	t.Helper()
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.RPCRetryBudget = budget
+		cfg.Axiom.RPCRetryBackoff = repo.Duration(time.Millisecond)
+	})
+}
+
+func TestClaimRetriesWhenThrottled(t *testing.T) {
+	node := testutil.NewNode(t)
+	calls := handleThrottled(node, 2)
+	c := newThrottleClient(t, node, 3)
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim should succeed within the retry budget, got %d %v", code, err)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("expect 3 attempts, got %d", got)
+	}
+	if got := c.RPCThrottledCount(); got != 2 {
+		t.Fatalf("expect 2 throttled responses, got %d", got)
+	}
+}
+
+func TestClaimReportsRPCBusyAfterBudget(t *testing.T) {
+	node := testutil.NewNode(t)
+	calls := handleThrottled(node, 100)
+	c := newThrottleClient(t, node, 2)
+
+	if _, code, _ := claim(c, context.Background(), testRecipient, 1); code != global.RPCBusyCode {
+		t.Fatalf("expect %d once the budget is exhausted, got %d", global.RPCBusyCode, code)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("expect 1 attempt and 2 retries, got %d", got)
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent, got %d", len(sent))
+	}
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/axiomesh/faucet/global"
+)
+
+// erc20ABI 只包含水龙头用到的 ERC-20 方法
+const erc20ABI = `[
+	{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+	{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}
+]`
+
+// initTokens 启动时读取并缓存每个代币的 decimals，读取失败的代币直接拒绝
+func (c *Client) initTokens() error {
+	tokenAbi, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return err
+	}
+	c.erc20Abi = tokenAbi
+	c.tokenDecimals = make(map[string]uint8, len(c.Config.Axiom.Tokens))
+	for _, token := range c.Config.Axiom.Tokens {
+		if !common.IsHexAddress(token.Address) {
+			return fmt.Errorf("invalid address of token %s: %s", token.Name, token.Address)
+		}
+		decimals, err := c.tokenDecimalsOf(common.HexToAddress(token.Address))
+		if err != nil {
+			return fmt.Errorf("read decimals of token %s(%s): %w", token.Name, token.Address, err)
+		}
+		c.tokenDecimals[strings.ToLower(token.Address)] = decimals
+		c.logger.Infof("token %s(%s) decimals: %d", token.Name, token.Address, decimals)
+	}
+	return nil
+}
+
+func (c *Client) tokenDecimalsOf(tokenAddress common.Address) (uint8, error) {
+	input, err := c.erc20Abi.Pack("decimals")
+	if err != nil {
+		return 0, err
+	}
+	output, err := c.axiomClient.CallContract(context.Background(), ethereum.CallMsg{To: &tokenAddress, Data: input}, nil)
+	if err != nil {
+		return 0, err
+	}
+	values, err := c.erc20Abi.Unpack("decimals", output)
+	if err != nil {
+		return 0, err
+	}
+	decimals, ok := values[0].(uint8)
+	if !ok {
+		return 0, fmt.Errorf("unexpected decimals output: %v", values[0])
+	}
+	return decimals, nil
+}
+
+// tokenAmount 按缓存的 decimals 将可读数量转换为代币最小单位
+func (c *Client) tokenAmount(tokenAddress string, amount float64) (*big.Int, error) {
+	decimals, ok := c.tokenDecimals[strings.ToLower(tokenAddress)]
+	if !ok {
+		return nil, fmt.Errorf("token %s is not configured", tokenAddress)
+	}
+	return floatToBigInt(amount, decimals), nil
+}
+
+// sendTxToken 由资金账户直接转出 ERC-20 代币，与 sendTxAxm 共用 axiomLock 按顺序分配 nonce
+func sendTxToken(ctx context.Context, c *Client, toAddr string, tokenAddress string, value *big.Int) (*types.Transaction, error) {
+	c.axiomLock.Lock()
+	defer c.axiomLock.Unlock()
+	if c.axiomAuth == nil {
+		return nil, fmt.Errorf(global.NetDisabledMsg)
+	}
+	if _, err := c.checkChain(); err != nil {
+		return nil, err
+	}
+	client := c.axiomClient
+
+	nonce, err := client.PendingNonceAt(context.Background(), c.axiomAuth.From)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return nil, err
+	}
+	if err := c.checkNonceGap(nonce); err != nil {
+		return nil, err
+	}
+	chainId, err := c.ChainID()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(c.axiomPrivateKey, chainId)
+	if err != nil {
+		return nil, err
+	}
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.Value = big.NewInt(0)
+
+	token := bind.NewBoundContract(common.HexToAddress(tokenAddress), c.erc20Abi, client, client, client)
+	tx, err := token.Transact(auth, "transfer", common.HexToAddress(toAddr), value)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return nil, err
+	}
+
+	c.nextNonce = tx.Nonce() + 1
+	c.requestLogger(ctx).Infof("token %s tx sent: %s, nonce: %d", tokenAddress, tx.Hash().Hex(), tx.Nonce())
+	return tx, nil
+}
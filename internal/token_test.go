@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testToken = "0x3333333333333333333333333333333333333333"
+
+// handleDecimals 测试节点对 decimals 调用按合约地址返回配置的精度，未配置的合约返回空
+func handleDecimals(t *testing.T, node *testutil.Node, decimals map[string]uint8) {
+	t.Helper()
+	tokenAbi, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	selector := hex.EncodeToString(tokenAbi.Methods["decimals"].ID)
+	node.Handle("eth_call", func(params []json.RawMessage) (any, error) {
+		var call struct {
+			To    string        `json:"to"`
+			Input hexutil.Bytes `json:"input"`
+			Data  hexutil.Bytes `json:"data"`
+		}
+		if err := json.Unmarshal(params[0], &call); err != nil {
+			return nil, err
+		}
+		input := call.Input
+		if len(input) == 0 {
+			input = call.Data
+		}
+		value, ok := decimals[strings.ToLower(call.To)]
+		if !ok || len(input) < 4 || hex.EncodeToString(input[:4]) != selector {
+			return hexutil.Bytes{}, nil
+		}
+		output, err := tokenAbi.Methods["decimals"].Outputs.Pack(value)
+		if err != nil {
+			return nil, err
+		}
+		return hexutil.Bytes(output), nil
+	})
+}
+
+func TestTokenAmountUsesChainDecimals(t *testing.T) {
+	node := testutil.NewNode(t)
+	handleDecimals(t, node, map[string]uint8{testToken: 6})
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.Tokens = []repo.Token{{Name: "USDT", Address: testToken, Amount: 1.5}}
+	})
+
+	amount, err := c.tokenAmount(testToken, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount.Cmp(big.NewInt(1_500_000)) != 0 {
+		t.Fatalf("expect 1.5 with 6 decimals to be 1500000, got %s", amount)
+	}
+}
+
+func TestTokenAmountRejectsUnknownToken(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), nil)
+	if _, err := c.tokenAmount(testToken, 1); err == nil {
+		t.Fatal("unconfigured token should be rejected")
+	}
+}
+
+func TestInitializeRejectsTokenWithoutDecimals(t *testing.T) {
+	for name, token := range map[string]repo.Token{
+		"invalid address":  {Name: "BAD", Address: "0x1234", Amount: 1},
+		"decimals missing": {Name: "NODEC", Address: testToken, Amount: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			node := testutil.NewNode(t)
+			handleDecimals(t, node, map[string]uint8{})
+			cfg := repo.DefaultConfig()
+			cfg.Axiom.AxiomAddr = node.URL
+			cfg.Axiom.ChainID = node.ChainID()
+			cfg.Axiom.Tokens = []repo.Token{token}
+
+			c := &Client{}
+			if err := c.Initialize(cfg, t.TempDir()); err == nil {
+				c.Close()
+				t.Fatal("initialize should fail")
+			}
+		})
+	}
+}
@@ -10,56 +10,70 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/axiomesh/faucet/global"
 	"github.com/axiomesh/faucet/internal/contract"
 )
 
-func sendTxAxm(c *Client, toAddr string, amount float64) (string, error) {
+// sendTxAxm 发送领取交易，同时返回 eth_estimateGas 的预估值，未预估时为 0
+func sendTxAxm(ctx context.Context, c *Client, toAddr string, amount float64) (*types.Transaction, uint64, error) {
 	c.axiomLock.Lock()
 	defer c.axiomLock.Unlock()
+	if c.axiomAuth == nil {
+		return nil, 0, fmt.Errorf(global.NetDisabledMsg)
+	}
+	if _, err := c.checkChain(); err != nil {
+		return nil, 0, err
+	}
 	client := c.axiomClient
 
 	fromAddress := c.axiomAuth.From
 	// 余额查询
 	balanceNow, err := client.BalanceAt(context.Background(), common.HexToAddress(toAddr), nil)
 	if err != nil {
-		c.logger.Error(err)
-		return "", err
+		c.requestLogger(ctx).Error(err)
+		return nil, 0, err
 	}
 	limit := floatToEtherBigInt(c.Config.Axiom.ClaimLimit)
 	if balanceNow.Cmp(limit) >= 0 {
-		return "", fmt.Errorf(global.EnoughTokenMsg)
+		return nil, 0, fmt.Errorf(global.EnoughTokenMsg)
 	}
 
 	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
 	if err != nil {
-		c.logger.Error(err)
-		return "", err
+		c.requestLogger(ctx).Error(err)
+		return nil, 0, err
+	}
+	if err := c.checkNonceGap(nonce); err != nil {
+		return nil, 0, err
 	}
 
 	value := floatToEtherBigInt(amount)
+	if err := checkFaucetReserve(ctx, c, value, true); err != nil {
+		return nil, 0, err
+	}
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 	chainId, err := client.ChainID(context.Background())
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 	taurusFaucet, err := contract.NewTaurusFaucet(common.HexToAddress(c.Config.Axiom.FaucetAddr), c.axiomClient)
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 
 	contractAbi, err := abi.JSON(strings.NewReader(string(contract.TaurusFaucetABI)))
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 
 	input, err := contractAbi.Pack("drip", common.HexToAddress(toAddr), value)
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 	contractAddress := common.HexToAddress(c.Config.Axiom.FaucetAddr)
 
@@ -68,44 +82,135 @@ func sendTxAxm(c *Client, toAddr string, amount float64) (string, error) {
 		To:   &contractAddress,
 		Data: input,
 	}
-	_, err = client.CallContract(context.Background(), msg, nil)
-	if err != nil {
-		c.logger.Error(err)
-		return "", err
+	gasLimit := c.Config.Axiom.GasLimit
+	var estimate uint64
+	if c.Config.Axiom.GasLimitMultiplier > 0 {
+		estimate, err = client.EstimateGas(context.Background(), msg)
+		if err != nil {
+			c.requestLogger(ctx).Error(err)
+			return nil, 0, err
+		}
+		gasLimit = clampGasLimit(ctx, c, estimate)
+	} else {
+		_, err = client.CallContract(context.Background(), msg, nil)
+		if err != nil {
+			c.requestLogger(ctx).Error(err)
+			return nil, 0, err
+		}
 	}
 
 	auth, err := bind.NewKeyedTransactorWithChainID(c.axiomPrivateKey, chainId)
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 	gasTipCap, err := client.SuggestGasTipCap(context.Background())
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
 
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = big.NewInt(0) // in wei
-	auth.GasLimit = uint64(c.Config.Axiom.GasLimit)
+	auth.GasLimit = gasLimit
 	auth.GasFeeCap = new(big.Int).Mul(gasPrice, big.NewInt(2))
 	auth.GasTipCap = gasTipCap
+	// 先签名不发送，排队领取在广播前记录交易哈希，重启后据此判断交易是否已发出
+	auth.NoSend = true
 
 	tx, err := taurusFaucet.Drip(auth, common.HexToAddress(toAddr), value)
 	if err != nil {
-		c.logger.Error(err)
-		return "", err
+		c.requestLogger(ctx).Error(err)
+		return nil, 0, err
+	}
+	c.markTicketSending(ctx, tx)
+	if err := client.SendTransaction(context.Background(), tx); err != nil {
+		c.requestLogger(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	c.nextNonce = tx.Nonce() + 1
+	c.reserveFunds(tx.Hash().Hex(), value)
+	c.requestLogger(ctx).Infof("axm tx sent: %s, nonce: %d", tx.Hash().Hex(), tx.Nonce())
+
+	return tx, estimate, nil
+}
+
+// clampGasLimit 按倍数放大预估的 gas，并限制在 [GasLimitFloor, GasLimit] 范围内
+func clampGasLimit(ctx context.Context, c *Client, estimate uint64) uint64 {
+	gasLimit := uint64(float64(estimate) * c.Config.Axiom.GasLimitMultiplier)
+	floor, ceiling := c.Config.Axiom.GasLimitFloor, c.Config.Axiom.GasLimit
+	if floor > 0 && gasLimit < floor {
+		c.requestLogger(ctx).Infof("gas limit %d (estimate %d) clamped to floor %d", gasLimit, estimate, floor)
+		gasLimit = floor
+	}
+	if ceiling > 0 && gasLimit > ceiling {
+		c.requestLogger(ctx).Warnf("gas limit %d (estimate %d) clamped to ceiling %d", gasLimit, estimate, ceiling)
+		gasLimit = ceiling
+	}
+	return gasLimit
+}
+
+// availableBalance 水龙头合约余额扣除已发出未确认的数量后可用于发放的余额，fresh 为 false 时可以使用缓存余额
+func availableBalance(c *Client, fresh bool) (*big.Int, error) {
+	balance, err := c.faucetBalance(fresh)
+	if err != nil {
+		return nil, err
+	}
+	if c.Config.Axiom.ReserveInFlight {
+		balance.Sub(balance, c.reservedFunds())
 	}
+	return balance, nil
+}
 
-	c.logger.Infof("axm tx sent: %s", tx.Hash().Hex())
+// checkFaucetReserve 水龙头合约的可用余额需足够本次发放，且支付 gas 的资金账户余额不能低于 GasReserve
+func checkFaucetReserve(ctx context.Context, c *Client, value *big.Int, fresh bool) error {
+	available, err := availableBalance(c, fresh)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return err
+	}
+	if available.Cmp(value) < 0 {
+		c.requestLogger(ctx).Warnf("faucet available balance %s is less than claim value %s", available, value)
+		return fmt.Errorf(global.ReserveErrMsg)
+	}
+	return checkSenderReserve(ctx, c)
+}
+
+// checkSenderReserve gas 由资金账户支付，资金账户余额低于 GasReserve 时拒绝领取，保证仍能支付清扫、管理等交易
+func checkSenderReserve(ctx context.Context, c *Client) error {
+	sender := c.FundingAddress()
+	if c.Config.Axiom.GasReserve <= 0 || sender == "" {
+		return nil
+	}
+	balance, err := c.axiomClient.BalanceAt(context.Background(), common.HexToAddress(sender), nil)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return err
+	}
+	if reserve := floatToEtherBigInt(c.Config.Axiom.GasReserve); balance.Cmp(reserve) < 0 {
+		c.requestLogger(ctx).Warnf("sender %s balance %s is less than gas reserve %s", sender, balance, reserve)
+		return fmt.Errorf(global.ReserveErrMsg)
+	}
+	return nil
+}
 
-	return tx.Hash().Hex(), nil
+// preflightBalance 加预锁前的余额预检，按最小的单次发放量判断，余额连这一笔都不够时拒绝
+func (c *Client) preflightBalance(ctx context.Context) error {
+	if !c.Config.Axiom.PreflightBalanceCheck {
+		return nil
+	}
+	amount := c.Config.Axiom.Amount
+	if tweetAmount := c.Config.Axiom.TweetAmount; tweetAmount > 0 && (amount <= 0 || tweetAmount < amount) {
+		amount = tweetAmount
+	}
+	return checkFaucetReserve(ctx, c, floatToEtherBigInt(amount), false)
 }
 
-func checkBalance(c *Client, toAddr string) (bool, error) {
+func checkBalance(ctx context.Context, c *Client, toAddr string) (bool, error) {
 	client := c.axiomClient
 	// 余额查询
 	balanceNow, err := client.BalanceAt(context.Background(), common.HexToAddress(toAddr), nil)
 	if err != nil {
-		c.logger.Error(err)
+		c.requestLogger(ctx).Error(err)
 		return false, err
 	}
 	limit := floatToEtherBigInt(c.Config.Axiom.ClaimLimit)
@@ -115,10 +220,53 @@ func checkBalance(c *Client, toAddr string) (bool, error) {
 	return true, nil
 }
 
-func floatToEtherBigInt(value float64) *big.Int {
+// topUpAmount 补足模式下只发送使余额达到目标值所需的数量，最多不超过 amount
+func topUpAmount(ctx context.Context, c *Client, toAddr string, amount float64) (float64, error) {
+	target := c.Config.Axiom.TopUpTarget
+	if target <= 0 {
+		return amount, nil
+	}
+	balanceNow, err := c.axiomClient.BalanceAt(context.Background(), common.HexToAddress(toAddr), nil)
+	if err != nil {
+		c.requestLogger(ctx).Error(err)
+		return 0, err
+	}
+	targetValue := floatToEtherBigInt(target)
+	if balanceNow.Cmp(targetValue) >= 0 {
+		return 0, fmt.Errorf(global.EnoughTokenMsg)
+	}
+	delta := etherBigIntToFloat(new(big.Int).Sub(targetValue, balanceNow))
+	if delta > amount {
+		return amount, nil
+	}
+	return delta, nil
+}
+
+func etherBigIntToFloat(value *big.Int) float64 {
 	decimalMultiplier := new(big.Int)
 	decimalMultiplier.Exp(big.NewInt(10), big.NewInt(18), nil)
 
+	valueAsBigFloat := new(big.Float).SetInt(value)
+	valueAsBigFloat.Quo(valueAsBigFloat, new(big.Float).SetInt(decimalMultiplier))
+
+	f, _ := valueAsBigFloat.Float64()
+	return f
+}
+
+// EtherToWei 按发放交易使用的同一换算方式将数量转换为 wei
+func EtherToWei(value float64) *big.Int {
+	return floatToEtherBigInt(value)
+}
+
+func floatToEtherBigInt(value float64) *big.Int {
+	return floatToBigInt(value, 18)
+}
+
+// floatToBigInt 将可读数量按 decimals 转换为最小单位
+func floatToBigInt(value float64, decimals uint8) *big.Int {
+	decimalMultiplier := new(big.Int)
+	decimalMultiplier.Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+
 	valueAsBigFloat := new(big.Float).SetFloat64(value)
 	valueAsBigFloat.Mul(valueAsBigFloat, new(big.Float).SetInt(decimalMultiplier))
 
@@ -127,3 +275,12 @@ func floatToEtherBigInt(value float64) *big.Int {
 
 	return etherBigInt
 }
+
+// txSender 从已签名交易中恢复发送地址
+func txSender(tx *types.Transaction) string {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return ""
+	}
+	return from.Hex()
+}
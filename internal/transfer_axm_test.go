@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+func TestTopUpAmountSendsOnlyTheShortfall(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.TopUpTarget = 10
+	})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		balance float64
+		amount  float64
+		want    float64
+	}{
+		{balance: 4, amount: 100, want: 6},
+		{balance: 0, amount: 5, want: 5},
+		{balance: 9.5, amount: 5, want: 0.5},
+	} {
+		node.SetBalance(testRecipient, tc.balance)
+		got, err := topUpAmount(ctx, c, testRecipient, tc.amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("balance %v amount %v: expect %v, got %v", tc.balance, tc.amount, tc.want, got)
+		}
+	}
+
+	node.SetBalance(testRecipient, 10)
+	if _, err := topUpAmount(ctx, c, testRecipient, 5); err == nil || err.Error() != global.EnoughTokenMsg {
+		t.Fatalf("balance at target should be rejected, got %v", err)
+	}
+}
+
+func TestTopUpAmountDisabled(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetBalance(testRecipient, 4)
+
+	got, err := topUpAmount(context.Background(), c, testRecipient, 100)
+	if err != nil || got != 100 {
+		t.Fatalf("top up is off, expect full amount, got %v %v", got, err)
+	}
+}
+
+func TestSendTraTopsUpRecipient(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.TopUpTarget = 10
+	})
+	node.SetBalance(testRecipient, 7)
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 100); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	sent := node.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("expect 1 tx, got %d", len(sent))
+	}
+	if to, value := dripValue(t, sent[0]); to != testRecipient || value != 3 {
+		t.Fatalf("expect drip 3 to %s, got %v to %s", testRecipient, value, to)
+	}
+}
+
+func TestClampGasLimit(t *testing.T) {
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.GasLimitMultiplier = 1.5
+		cfg.Axiom.GasLimitFloor = 60000
+		cfg.Axiom.GasLimit = 300000
+	})
+	for _, tc := range []struct {
+		estimate uint64
+		want     uint64
+	}{
+		{estimate: 10000, want: 60000},
+		{estimate: 100000, want: 150000},
+		{estimate: 500000, want: 300000},
+	} {
+		if got := clampGasLimit(context.Background(), c, tc.estimate); got != tc.want {
+			t.Errorf("estimate %d: expect %d, got %d", tc.estimate, tc.want, got)
+		}
+	}
+}
+
+func TestSendTraUsesEstimatedGasLimit(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.GasLimitMultiplier = 1.2
+		cfg.Axiom.GasLimitFloor = 0
+	})
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	// 测试节点的 eth_estimateGas 返回 50000
+	if sent := node.Sent(); len(sent) != 1 || sent[0].Gas() != 60000 {
+		t.Fatalf("expect gas limit 60000 from the estimate, got %v", sent)
+	}
+}
+
+// 默认配置不开启估算，固定使用 gas_limit
+func TestSendTraUsesFixedGasLimitWithoutMultiplier(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if calls := node.Calls("eth_estimateGas"); calls != 0 {
+		t.Fatalf("multiplier is off, expect no estimate, got %d", calls)
+	}
+	if sent := node.Sent(); len(sent) != 1 || sent[0].Gas() != c.Config.Axiom.GasLimit {
+		t.Fatalf("expect fixed gas limit %d, got %v", c.Config.Axiom.GasLimit, sent)
+	}
+}
+
+// gas 保留额度按支付 gas 的资金账户余额检查
+func TestSendTraKeepsSenderGasReserve(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.GasReserve = 10
+	})
+	ctx := context.Background()
+
+	node.SetBalance(c.FundingAddress(), 5)
+	if _, code, err := claim(c, ctx, testRecipient, 1); code != global.ReserveErrCode {
+		t.Fatalf("sender below the gas reserve should be refused with %d, got %d %v", global.ReserveErrCode, code, err)
+	}
+	if sent := node.Sent(); len(sent) != 0 {
+		t.Fatalf("no tx should be sent, got %d", len(sent))
+	}
+	node.SetBalance(c.FundingAddress(), 20)
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("sender above the gas reserve should pass, got %d %v", code, err)
+	}
+}
+
+// 水龙头合约余额只需覆盖发放数量，不再扣除 gas 保留额度
+func TestSendTraDoesNotReserveGasFromFaucet(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Axiom.GasReserve = 10
+		cfg.Axiom.BalanceCacheInterval = 0
+	})
+	ctx := context.Background()
+
+	node.SetBalance(c.Config.Axiom.FaucetAddr, 0.5)
+	if _, code, _ := claim(c, ctx, testRecipient, 1); code != global.ReserveErrCode {
+		t.Fatalf("faucet below the claim amount should be refused with %d, got %d", global.ReserveErrCode, code)
+	}
+	node.SetBalance(c.Config.Axiom.FaucetAddr, 1)
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("faucet holding exactly the claim amount should pass, got %d %v", code, err)
+	}
+}
+
+func TestGasReserveDisabledByDefault(t *testing.T) {
+	if reserve := repo.DefaultConfig().Axiom.GasReserve; reserve != 0 {
+		t.Fatalf("gas reserve should be off by default, got %v", reserve)
+	}
+}
@@ -1,14 +1,25 @@
 package internal
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Rican7/retry"
+	"github.com/Rican7/retry/backoff"
+	"github.com/Rican7/retry/strategy"
+
 	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/persist"
 )
 
 type APIResponse struct {
@@ -16,10 +27,63 @@ type APIResponse struct {
 	Success bool   `json:"success"`
 }
 
-func (c *Client) TweetReqCheck(tweetURL string, addr string) (int, string) {
-	client := &http.Client{
-		Timeout: 20 * time.Second,
+var (
+	tweetIDRegex     = regexp.MustCompile(`/status/(\d+)`)
+	tweetAuthorRegex = regexp.MustCompile(`^/([A-Za-z0-9_]+)/status/\d+`)
+)
+
+func (c *Client) TweetReqCheck(ctx context.Context, tweetURL string, addr string) (int, string) {
+	if c.Config.DevMode {
+		return global.SUCCESS, "tweet verification skipped in dev mode"
+	}
+	// 同一推文、同一地址验证成功后在缓存时间内不再请求 scrapper
+	cacheKey := tweetCacheKey(tweetURL, addr)
+	if c.tweetVerified(cacheKey) {
+		return global.SUCCESS, "tweet verified"
+	}
+
+	var apiResp *APIResponse
+	err := retry.Retry(func(attempt uint) error {
+		resp, retryable, err := c.requestScrapper(tweetURL, addr)
+		if err != nil {
+			c.requestLogger(ctx).Warnf("tweet verification attempt %d failed: %v", attempt, err)
+			if retryable {
+				return err
+			}
+			return nil
+		}
+		apiResp = resp
+		return nil
+	}, strategy.Limit(uint(c.Config.Scrapper.Retries)), strategy.Backoff(backoff.Fibonacci(200*time.Millisecond)))
+	if err != nil {
+		return global.VerifierDownCode, global.VerifierDownMsg
 	}
+	if apiResp == nil {
+		return global.ScrapperErrCode, global.ScrapperErrMsg
+	}
+
+	c.requestLogger(ctx).Infof("msg: %s", apiResp.Message)
+	c.requestLogger(ctx).Infof("success sign: %v", apiResp.Success)
+	if apiResp.Success {
+		c.cacheTweetVerified(cacheKey)
+		return global.SUCCESS, apiResp.Message
+	}
+	switch apiResp.Message {
+	case "The address is not in the tweet":
+		return global.TweetAddrErrCode, global.TweetAddrErrMsg
+	case "Err quote tweet", "No tweet content":
+		return global.TweetLinkErrCode, global.TweetLinkErrMsg
+	case "Err quote tweet time", "Expired tweet":
+		return global.TweetTimeErrCode, global.TweetTimeErrMsg
+	default:
+		return global.ScrapperErrCode, global.ScrapperErrMsg
+	}
+}
+
+// requestScrapper 请求推文验证服务，网络错误和 5xx 视为可重试的临时错误
+func (c *Client) requestScrapper(tweetURL string, addr string) (*APIResponse, bool, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.outboundTimeout(c.Config.Scrapper.Timeout))
+	defer cancel()
 
 	// 发起HTTP GET请求，替换为你的实际URL
 	queryParams := url.Values{}
@@ -28,48 +92,174 @@ func (c *Client) TweetReqCheck(tweetURL string, addr string) (int, string) {
 
 	url := c.Config.Scrapper.ScrapperAddr
 	fullURL := fmt.Sprintf("%s?%s", url, queryParams.Encode())
-	resp, err := client.Get(fullURL)
-
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
 	if err != nil {
-		c.logger.Error("http request err:", err)
-		return global.ScrapperErrCode, global.ScrapperErrMsg
+		return nil, false, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("http request err: %w", err)
 	}
 	defer resp.Body.Close()
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return global.ScrapperErrCode, global.ScrapperErrMsg
+		return nil, true, fmt.Errorf("read response err: %w", err)
 	}
 
 	// 根据HTTP状态码判断是否成功
-	if resp.StatusCode == http.StatusOK {
-		// 处理成功的情况
-		var apiResp APIResponse
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, true, fmt.Errorf("scrapper responds %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("scrapper responds %d", resp.StatusCode)
+	}
 
-		// 解析JSON数据到结构体
-		err = json.Unmarshal(body, &apiResp)
-		if err != nil {
-			c.logger.Error("unmarshal json err:", err)
-			return global.ScrapperErrCode, global.ScrapperErrMsg
+	// 解析JSON数据到结构体
+	var apiResp APIResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, false, errors.New("unmarshal json err: " + err.Error())
+	}
+	return &apiResp, false, nil
+}
+
+func tweetCacheKey(tweetURL string, addr string) string {
+	return tweetID(tweetURL) + "-" + strings.ToLower(addr)
+}
+
+func tweetID(tweetURL string) string {
+	if match := tweetIDRegex.FindStringSubmatch(tweetURL); match != nil {
+		return match[1]
+	}
+	return tweetURL
+}
+
+// reserveTweet 原子地检查并记录推文已被使用，同一推文只能领取一次，并发提交时只有一个请求成功；
+// 同时累计推文作者当天的领取次数，超过 max_claims_per_author 时拒绝。返回计数所在的日期，释放时按该日期回退
+func (c *Client) reserveTweet(ctx context.Context, net string, tweetURL string, addr string) (string, int, error) {
+	c.tweetLock.Lock()
+	defer c.tweetLock.Unlock()
+	key := c.construTweetKey(net, tweetID(tweetURL))
+	if c.ldb.Has(key) {
+		return "", global.TweetUsedCode, fmt.Errorf(global.TweetUsedMsg)
+	}
+	day := time.Now().Format("2006-01-02")
+	if limit := c.Config.Scrapper.MaxClaimsPerAuthor; limit > 0 {
+		author, ok := tweetAuthor(tweetURL)
+		if !ok {
+			return "", global.TweetUrlErrCode, fmt.Errorf(global.TweetUrlErrMsg)
+		}
+		authorKey := c.construTweetAuthorKey(net, day, author)
+		count := c.authorClaims(ctx, authorKey)
+		if count >= limit {
+			return "", global.AuthorLimitCode, fmt.Errorf(global.AuthorLimitMsg)
 		}
-		// 打印结果
+		c.ldb.Put(authorKey, []byte(strconv.Itoa(count+1)))
+	}
+	c.ldb.Put(key, []byte(strings.ToLower(addr)))
+	return day, global.SUCCESS, nil
+}
 
-		c.logger.Info("msg: %s\n", apiResp.Message)
-		c.logger.Info("success sign: %v\n", apiResp.Success)
-		if apiResp.Success {
-			return global.SUCCESS, apiResp.Message
+// releaseTweet 交易未发出时释放推文，允许重新提交，作者计数回退到预留时的日期
+func (c *Client) releaseTweet(ctx context.Context, net string, tweetURL string, day string) {
+	c.tweetLock.Lock()
+	defer c.tweetLock.Unlock()
+	c.ldb.Delete(c.construTweetKey(net, tweetID(tweetURL)))
+	if c.Config.Scrapper.MaxClaimsPerAuthor > 0 {
+		author, ok := tweetAuthor(tweetURL)
+		if !ok {
+			return
 		}
-		switch apiResp.Message {
-		case "The address is not in the tweet":
-			return global.TweetAddrErrCode, global.TweetAddrErrMsg
-		case "Err quote tweet", "No tweet content":
-			return global.TweetLinkErrCode, global.TweetLinkErrMsg
-		case "Err quote tweet time", "Expired tweet":
-			return global.TweetTimeErrCode, global.TweetTimeErrMsg
-		default:
-			return global.ScrapperErrCode, global.ScrapperErrMsg
+		authorKey := c.construTweetAuthorKey(net, day, author)
+		if count := c.authorClaims(ctx, authorKey); count > 0 {
+			c.ldb.Put(authorKey, []byte(strconv.Itoa(count-1)))
+		}
+	}
+}
+
+func (c *Client) authorClaims(ctx context.Context, authorKey []byte) int {
+	value := c.ldb.Get(authorKey)
+	if value == nil {
+		return 0
+	}
+	count, err := strconv.Atoi(string(value))
+	if err != nil {
+		c.requestLogger(ctx).Errorf("parse author claims %s failed: %v", authorKey, err)
+		return 0
+	}
+	return count
+}
+
+// tweetAuthor 从推文链接 https://x.com/<author>/status/<id> 中取出作者，
+// 无法解析或没有作者的链接（如 /i/web/status/<id>）返回 false
+func tweetAuthor(tweetURL string) (string, bool) {
+	u, err := url.Parse(tweetURL)
+	if err != nil {
+		return "", false
+	}
+	match := tweetAuthorRegex.FindStringSubmatch(u.Path)
+	if match == nil || match[1] == "i" {
+		return "", false
+	}
+	return strings.ToLower(match[1]), true
+}
+
+// construTweetAuthorKey 按天统计推文作者的领取次数
+func (c *Client) construTweetAuthorKey(net string, day string, author string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("author-")
+	buffer.WriteString(day)
+	buffer.WriteString("-")
+	buffer.WriteString(author)
+	return persist.CompositeKey(net, buffer)
+}
+
+// firstTweetBonus 地址首次通过推文领取时返回额外奖励，发出交易后通过 markTweetBonus 记录，不会重复发放
+func (c *Client) firstTweetBonus(net string, addr string) float64 {
+	bonus := c.Config.Axiom.FirstTweetBonus
+	if bonus <= 0 || c.ldb.Has(c.construTweetBonusKey(net, addr)) {
+		return 0
+	}
+	return bonus
+}
+
+func (c *Client) markTweetBonus(net string, addr string) {
+	c.ldb.Put(c.construTweetBonusKey(net, addr), []byte(strconv.FormatInt(time.Now().Unix(), 10)))
+}
+
+func (c *Client) construTweetBonusKey(net string, addr string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("tweetbonus-")
+	buffer.WriteString(addr)
+	return persist.CompositeKey(net, buffer)
+}
+
+func (c *Client) construTweetKey(net string, tweetID string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("tweet-")
+	buffer.WriteString(tweetID)
+	return persist.CompositeKey(net, buffer)
+}
+
+func (c *Client) tweetVerified(key string) bool {
+	c.tweetCacheLock.Lock()
+	defer c.tweetCacheLock.Unlock()
+	expireAt, ok := c.tweetCache[key]
+	return ok && time.Now().Before(expireAt)
+}
+
+func (c *Client) cacheTweetVerified(key string) {
+	ttl := c.Config.Scrapper.CacheTTL.ToDuration()
+	if ttl <= 0 {
+		return
+	}
+	c.tweetCacheLock.Lock()
+	defer c.tweetCacheLock.Unlock()
+	now := time.Now()
+	for k, expireAt := range c.tweetCache {
+		if now.After(expireAt) {
+			delete(c.tweetCache, k)
 		}
-	} else {
-		return global.ScrapperErrCode, global.ScrapperErrMsg
 	}
+	c.tweetCache[key] = now.Add(ttl)
 }
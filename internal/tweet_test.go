@@ -0,0 +1,339 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testTweetURL = "https://x.com/axiomesh/status/1700000000000000000"
+
+// newScrapper 启动推文验证服务，前 failures 次请求返回 status，之后返回 resp
+func newScrapper(t *testing.T, failures int32, status int, resp *APIResponse) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTweetClient(t *testing.T, scrapper string, retries int) *Client {
+	t.Helper()
+	return newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Scrapper.ScrapperAddr = scrapper
+		cfg.Scrapper.Retries = retries
+		cfg.Scrapper.CacheTTL = repo.Duration(time.Minute)
+	})
+}
+
+func TestTweetReqCheckRetriesServerErrors(t *testing.T) {
+	scrapper, calls := newScrapper(t, 2, http.StatusBadGateway, &APIResponse{Success: true})
+	c := newTweetClient(t, scrapper.URL, 2)
+
+	if code, msg := c.TweetReqCheck(context.Background(), testTweetURL, testRecipient); code != global.SUCCESS {
+		t.Fatalf("verification should succeed after retries, got %d %s", code, msg)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Fatalf("expect 3 attempts, got %d", got)
+	}
+}
+
+func TestTweetReqCheckReportsVerifierDown(t *testing.T) {
+	scrapper, calls := newScrapper(t, 10, http.StatusServiceUnavailable, nil)
+	c := newTweetClient(t, scrapper.URL, 1)
+
+	if code, _ := c.TweetReqCheck(context.Background(), testTweetURL, testRecipient); code != global.VerifierDownCode {
+		t.Fatalf("expect %d after retries are exhausted, got %d", global.VerifierDownCode, code)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("expect 2 attempts, got %d", got)
+	}
+}
+
+// 4xx 不是临时错误，不重试
+func TestTweetReqCheckDoesNotRetryClientErrors(t *testing.T) {
+	scrapper, calls := newScrapper(t, 10, http.StatusBadRequest, nil)
+	c := newTweetClient(t, scrapper.URL, 3)
+
+	if code, _ := c.TweetReqCheck(context.Background(), testTweetURL, testRecipient); code != global.ScrapperErrCode {
+		t.Fatalf("expect %d, got %d", global.ScrapperErrCode, code)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("expect a single attempt, got %d", got)
+	}
+}
+
+func TestTweetReqCheckCachesSuccess(t *testing.T) {
+	scrapper, calls := newScrapper(t, 0, http.StatusOK, &APIResponse{Success: true})
+	c := newTweetClient(t, scrapper.URL, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if code, msg := c.TweetReqCheck(ctx, testTweetURL, testRecipient); code != global.SUCCESS {
+			t.Fatalf("verification failed: %d %s", code, msg)
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Fatalf("verified tweet should be cached, got %d requests", got)
+	}
+	if code, _ := c.TweetReqCheck(ctx, testTweetURL, "0x2222222222222222222222222222222222222222"); code != global.SUCCESS {
+		t.Fatal("verification for another address failed")
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("cache should be keyed by address, got %d requests", got)
+	}
+}
+
+func TestTweetReqCheckDoesNotCacheFailure(t *testing.T) {
+	scrapper, calls := newScrapper(t, 0, http.StatusOK, &APIResponse{Message: "The address is not in the tweet"})
+	c := newTweetClient(t, scrapper.URL, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if code, _ := c.TweetReqCheck(ctx, testTweetURL, testRecipient); code != global.TweetAddrErrCode {
+			t.Fatalf("expect %d, got %d", global.TweetAddrErrCode, code)
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Fatalf("failed verification should not be cached, got %d requests", got)
+	}
+}
+
+func TestTweetPaysOutOnce(t *testing.T) {
+	scrapper, _ := newScrapper(t, 0, http.StatusOK, &APIResponse{Success: true})
+	c := newTweetClient(t, scrapper.URL, 0)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	if _, code, err := c.SendTra(ctx, net, testRecipient, 1, 0, testTweetURL, ""); err != nil {
+		t.Fatalf("tweet claim failed: %d %v", code, err)
+	}
+	// 同一推文换个地址或换个链接写法也不能再次领取
+	other := "0x2222222222222222222222222222222222222222"
+	if _, code, _ := c.SendTra(ctx, net, other, 1, 0, "https://twitter.com/someone/status/1700000000000000000?s=20", ""); code != global.TweetUsedCode {
+		t.Fatalf("reused tweet should be refused with %d, got %d", global.TweetUsedCode, code)
+	}
+}
+
+func TestReleaseTweetAllowsResubmit(t *testing.T) {
+	c := newTweetClient(t, "", 0)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	day, code, err := c.reserveTweet(ctx, net, testTweetURL, testRecipient)
+	if err != nil {
+		t.Fatalf("reserve failed: %d %v", code, err)
+	}
+	if _, code, _ := c.reserveTweet(ctx, net, testTweetURL, testRecipient); code != global.TweetUsedCode {
+		t.Fatalf("expect %d for a reserved tweet, got %d", global.TweetUsedCode, code)
+	}
+	c.releaseTweet(ctx, net, testTweetURL, day)
+	if _, code, err := c.reserveTweet(ctx, net, testTweetURL, testRecipient); err != nil {
+		t.Fatalf("released tweet should be reservable again, got %d %v", code, err)
+	}
+}
+
+// 并发提交同一推文时只有一个请求预留成功
+func TestReserveTweetConcurrently(t *testing.T) {
+	c := newTweetClient(t, "", 0)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var reserved int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := c.reserveTweet(ctx, c.Config.Axiom.TestNetName, testTweetURL, testRecipient); err == nil {
+				atomic.AddInt32(&reserved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 1 {
+		t.Fatalf("expect exactly one reservation, got %d", reserved)
+	}
+}
+
+func TestFirstTweetBonusPaidOnce(t *testing.T) {
+	scrapper, _ := newScrapper(t, 0, http.StatusOK, &APIResponse{Success: true})
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Scrapper.ScrapperAddr = scrapper.URL
+		cfg.Axiom.FirstTweetBonus = 5
+		cfg.Axiom.TesterAllowlist = []string{testRecipient}
+	})
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	// 直接领取不发放奖励，也不占用首次推文奖励
+	if _, code, err := claim(c, ctx, testRecipient, 1); err != nil {
+		t.Fatalf("direct claim failed: %d %v", code, err)
+	}
+	for i, tweetURL := range []string{testTweetURL, "https://x.com/axiomesh/status/1700000000000000001"} {
+		if _, code, err := c.SendTra(ctx, net, testRecipient, 1, 0, tweetURL, ""); err != nil {
+			t.Fatalf("tweet claim %d failed: %d %v", i, code, err)
+		}
+		DeleteTxData(c, testRecipient, global.NativeToken, net)
+	}
+
+	sent := node.Sent()
+	if len(sent) != 3 {
+		t.Fatalf("expect 3 txs, got %d", len(sent))
+	}
+	for i, want := range []float64{1, 6, 1} {
+		if _, amount := dripValue(t, sent[i]); amount != want {
+			t.Fatalf("tx %d: expect %v, got %v", i, want, amount)
+		}
+	}
+}
+
+func TestTweetAuthor(t *testing.T) {
+	for tweetURL, want := range map[string]string{
+		"https://x.com/AxiomESH/status/1700000000000000000": "axiomesh",
+		"https://twitter.com/someone/status/1?s=20":         "someone",
+		"https://x.com/i/web/status/1700000000000000000":    "",
+		"https://x.com/axiomesh":                            "",
+		"://bad url":                                        "",
+	} {
+		got, ok := tweetAuthor(tweetURL)
+		if got != want || ok != (want != "") {
+			t.Fatalf("%s: expect %q, got %q %v", tweetURL, want, got, ok)
+		}
+	}
+}
+
+func newAuthorLimitClient(t *testing.T) *Client {
+	t.Helper()
+	return newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Scrapper.MaxClaimsPerAuthor = 2
+	})
+}
+
+func TestReserveTweetLimitsClaimsPerAuthor(t *testing.T) {
+	c := newAuthorLimitClient(t)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+
+	for i := 0; i < 2; i++ {
+		if _, code, err := c.reserveTweet(ctx, net, fmt.Sprintf("https://x.com/axiomesh/status/%d", i), testRecipient); err != nil {
+			t.Fatalf("reserve %d failed: %d %v", i, code, err)
+		}
+	}
+	if _, code, _ := c.reserveTweet(ctx, net, "https://x.com/AxiomESH/status/2", testRecipient); code != global.AuthorLimitCode {
+		t.Fatalf("expect %d once the author reaches the limit, got %d", global.AuthorLimitCode, code)
+	}
+	if _, code, err := c.reserveTweet(ctx, net, "https://x.com/someone/status/3", testRecipient); err != nil {
+		t.Fatalf("another author should not be limited, got %d %v", code, err)
+	}
+}
+
+// 开启作者限制时拒绝无法解析作者的链接，避免绕过限制
+func TestReserveTweetRejectsLinkWithoutAuthor(t *testing.T) {
+	c := newAuthorLimitClient(t)
+
+	if _, code, _ := c.reserveTweet(context.Background(), c.Config.Axiom.TestNetName, "https://x.com/i/web/status/1", testRecipient); code != global.TweetUrlErrCode {
+		t.Fatalf("expect %d, got %d", global.TweetUrlErrCode, code)
+	}
+}
+
+// 释放时回退预留当天的作者计数，跨零点释放也不影响新一天的计数
+func TestReleaseTweetRestoresAuthorCountOfReservationDay(t *testing.T) {
+	c := newAuthorLimitClient(t)
+	ctx := context.Background()
+	net := c.Config.Axiom.TestNetName
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	yesterdayKey := c.construTweetAuthorKey(net, yesterday, "axiomesh")
+	c.ldb.Put(yesterdayKey, []byte("2"))
+
+	day, code, err := c.reserveTweet(ctx, net, testTweetURL, testRecipient)
+	if err != nil {
+		t.Fatalf("reserve failed: %d %v", code, err)
+	}
+	todayKey := c.construTweetAuthorKey(net, day, "axiomesh")
+	if got := c.authorClaims(ctx, todayKey); got != 1 {
+		t.Fatalf("expect 1 claim today, got %d", got)
+	}
+
+	c.releaseTweet(ctx, net, testTweetURL, yesterday)
+	if got := c.authorClaims(ctx, yesterdayKey); got != 1 {
+		t.Fatalf("release should restore the reservation day, got %d", got)
+	}
+	if got := c.authorClaims(ctx, todayKey); got != 1 {
+		t.Fatalf("other days should be kept, got %d", got)
+	}
+}
+
+func newUnavailableVerifierClient(t *testing.T, node *testutil.Node, policy string) *Client {
+	t.Helper()
+	scrapper, _ := newScrapper(t, 100, http.StatusServiceUnavailable, nil)
+	return newTestClient(t, node, func(cfg *repo.Config) {
+		cfg.Scrapper.ScrapperAddr = scrapper.URL
+		cfg.Scrapper.Retries = 0
+		cfg.Scrapper.UnavailablePolicy = policy
+		cfg.Axiom.FirstTweetBonus = 5
+	})
+}
+
+func TestVerifierUnavailableFails(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newUnavailableVerifierClient(t, node, repo.VerifierPolicyFail)
+
+	_, code, _ := c.SendTra(context.Background(), c.Config.Axiom.TestNetName, testRecipient, 2, 0, testTweetURL, "")
+	DeleteTxData(c, testRecipient, global.NativeToken, c.Config.Axiom.TestNetName)
+	if code != global.VerifierDownCode {
+		t.Fatalf("expect %d, got %d", global.VerifierDownCode, code)
+	}
+	if len(node.Sent()) != 0 {
+		t.Fatal("no tx should be sent when the verifier is down")
+	}
+}
+
+// 降级时按倍数发放，不发放首次推文奖励
+func TestVerifierUnavailableDegraded(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newUnavailableVerifierClient(t, node, repo.VerifierPolicyDegraded)
+
+	_, code, err := c.SendTra(context.Background(), c.Config.Axiom.TestNetName, testRecipient, 2, 0, testTweetURL, "")
+	DeleteTxData(c, testRecipient, global.NativeToken, c.Config.Axiom.TestNetName)
+	if err != nil {
+		t.Fatalf("degraded tweet claim failed: %d %v", code, err)
+	}
+	sent := node.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("expect 1 tx, got %d", len(sent))
+	}
+	if _, amount := dripValue(t, sent[0]); amount != 1 {
+		t.Fatalf("expect degraded amount 1, got %v", amount)
+	}
+}
+
+func TestInitializeRejectsUnknownVerifierPolicy(t *testing.T) {
+	node := testutil.NewNode(t)
+	cfg := repo.DefaultConfig()
+	cfg.Axiom.AxiomAddr = node.URL
+	cfg.Axiom.ChainID = node.ChainID()
+	cfg.Scrapper.UnavailablePolicy = "ignore"
+
+	c := &Client{}
+	if err := c.Initialize(cfg, t.TempDir()); err == nil {
+		c.Close()
+		t.Fatal("unknown verifier policy should be rejected")
+	}
+}
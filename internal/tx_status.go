@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	TxPending  = "pending"
+	TxMined    = "mined"
+	TxReverted = "reverted"
+)
+
+// TxStatus 交易的链上状态，查询不到回执时为 pending
+type TxStatus struct {
+	TxHash      string `json:"txHash"`
+	Status      string `json:"status"`
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+}
+
+// Terminal 交易已上链（成功或失败），状态不会再变化
+func (s *TxStatus) Terminal() bool {
+	return s.Status != TxPending
+}
+
+// TxStatus 查询交易回执得到当前状态
+func (c *Client) TxStatus(ctx context.Context, txHash string) (*TxStatus, error) {
+	status := &TxStatus{TxHash: txHash, Status: TxPending}
+	receipt, err := c.axiomClient.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status.Status = TxMined
+	if receipt.Status == types.ReceiptStatusFailed {
+		status.Status = TxReverted
+	}
+	status.BlockNumber = receiptBlock(receipt)
+	return status, nil
+}
+
+func receiptBlock(receipt *types.Receipt) uint64 {
+	if receipt == nil || receipt.BlockNumber == nil {
+		return 0
+	}
+	return receipt.BlockNumber.Uint64()
+}
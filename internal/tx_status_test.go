@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+)
+
+func TestTxStatus(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetAutoReceipt(false)
+	ctx := context.Background()
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	status, err := c.TxStatus(ctx, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != TxPending || status.Terminal() {
+		t.Fatalf("tx without receipt should be pending, got %+v", status)
+	}
+
+	node.Mine(common.HexToHash(txHash), 1)
+	status, err = c.TxStatus(ctx, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != TxMined || !status.Terminal() || status.BlockNumber == 0 {
+		t.Fatalf("expect mined tx with block number, got %+v", status)
+	}
+}
+
+func TestTxStatusReverted(t *testing.T) {
+	node := testutil.NewNode(t)
+	c := newTestClient(t, node, nil)
+	node.SetAutoReceipt(false)
+	ctx := context.Background()
+	txHash, code, err := claim(c, ctx, testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+
+	node.Mine(common.HexToHash(txHash), 0)
+	status, err := c.TxStatus(ctx, txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != TxReverted || !status.Terminal() {
+		t.Fatalf("expect reverted tx, got %+v", status)
+	}
+}
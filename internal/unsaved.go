@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Rican7/retry"
+	"github.com/Rican7/retry/backoff"
+	"github.com/Rican7/retry/strategy"
+)
+
+// unsavedClaim 交易已发出但写入存储失败的领取记录
+type unsavedClaim struct {
+	net     string
+	typ     string
+	address string
+	data    *AddressData
+}
+
+// storeClaimData 交易发出后写入领取记录。写入失败时重试 record_write_retries 次，仍失败则保存在内存中，
+// 由后台按 record_retry_interval 重新写入，写入成功前该地址的领取限制按内存中的记录生效
+func (c *Client) storeClaimData(ctx context.Context, net string, typ string, address string, data *AddressData) {
+	err := retry.Retry(func(attempt uint) error {
+		err := putTxData(ctx, c, address, typ, net, data)
+		if err != nil {
+			c.requestLogger(ctx).Errorf("CLAIM RECORD NOT SAVED: tx %s to %s was sent but writing the record failed (attempt %d): %v", data.TxHash, address, attempt, err)
+		}
+		return err
+	}, strategy.Limit(uint(c.Config.Axiom.RecordWriteRetries)), strategy.Backoff(backoff.Fibonacci(200*time.Millisecond)))
+	if err == nil {
+		return
+	}
+	c.unsavedLock.Lock()
+	defer c.unsavedLock.Unlock()
+	c.unsavedClaims[string(c.construAddressKey(net, typ, address))] = &unsavedClaim{net: net, typ: typ, address: address, data: data}
+	c.requestLogger(ctx).Errorf("claim record of %s (tx %s) is kept in memory until it is persisted, %d record(s) pending", address, data.TxHash, len(c.unsavedClaims))
+}
+
+// unsavedClaimData 内存中尚未写入存储的领取记录，用于领取间隔校验
+func (c *Client) unsavedClaimData(key []byte) []byte {
+	c.unsavedLock.Lock()
+	unsaved, ok := c.unsavedClaims[string(key)]
+	c.unsavedLock.Unlock()
+	if !ok {
+		return nil
+	}
+	value, err := json.Marshal(unsaved.data)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// StartUnsavedClaimWriter 按 record_retry_interval 重新写入内存中的领取记录
+func (c *Client) StartUnsavedClaimWriter() {
+	interval := c.Config.Axiom.RecordRetryInterval.ToDuration()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.flushUnsavedClaims()
+			}
+		}
+	}()
+}
+
+func (c *Client) flushUnsavedClaims() {
+	c.unsavedLock.Lock()
+	pending := make(map[string]*unsavedClaim, len(c.unsavedClaims))
+	for key, unsaved := range c.unsavedClaims {
+		pending[key] = unsaved
+	}
+	c.unsavedLock.Unlock()
+
+	for key, unsaved := range pending {
+		if err := putTxData(c.ctx, c, unsaved.address, unsaved.typ, unsaved.net, unsaved.data); err != nil {
+			c.logger.Errorf("persist claim record of %s (tx %s) failed: %v", unsaved.address, unsaved.data.TxHash, err)
+			continue
+		}
+		c.unsavedLock.Lock()
+		if c.unsavedClaims[key] == unsaved {
+			delete(c.unsavedClaims, key)
+		}
+		c.unsavedLock.Unlock()
+		c.logger.Infof("claim record of %s (tx %s) persisted", unsaved.address, unsaved.data.TxHash)
+	}
+}
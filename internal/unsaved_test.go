@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/axiom-kit/storage"
+
+	"github.com/axiomesh/faucet/global"
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+// failingStorage 写入指定 key 时像 leveldb 一样 panic，failures 为剩余的失败次数，小于 0 时一直失败
+type failingStorage struct {
+	storage.Storage
+	key      []byte
+	failures int32
+	attempts int32
+}
+
+func (s *failingStorage) Put(key, value []byte) {
+	if bytes.Equal(key, s.key) {
+		atomic.AddInt32(&s.attempts, 1)
+		if atomic.LoadInt32(&s.failures) != 0 {
+			atomic.AddInt32(&s.failures, -1)
+			panic("disk full")
+		}
+	}
+	s.Storage.Put(key, value)
+}
+
+func newFailingRecordClient(t *testing.T, retries int, failures int32) (*Client, *failingStorage) {
+	t.Helper()
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Axiom.RecordWriteRetries = retries
+	})
+	s := &failingStorage{
+		Storage:  c.ldb,
+		key:      c.construAddressKey(c.Config.Axiom.TestNetName, global.NativeToken, testRecipient),
+		failures: failures,
+	}
+	c.ldb = s
+	return c, s
+}
+
+func TestStoreClaimDataRetriesConfiguredTimes(t *testing.T) {
+	c, s := newFailingRecordClient(t, 2, -1)
+	c.storeClaimData(context.Background(), c.Config.Axiom.TestNetName, global.NativeToken, testRecipient, &AddressData{TxHash: "0x1", SendTxTime: time.Now().Unix()})
+
+	// 首次写入加 2 次重试
+	if attempts := atomic.LoadInt32(&s.attempts); attempts != 3 {
+		t.Fatalf("expect 3 attempts, got %d", attempts)
+	}
+	if len(c.unsavedClaims) != 1 {
+		t.Fatalf("unsaved record should be kept in memory, got %d", len(c.unsavedClaims))
+	}
+}
+
+func TestStoreClaimDataSucceedsOnRetry(t *testing.T) {
+	c, s := newFailingRecordClient(t, 2, 1)
+	c.storeClaimData(context.Background(), c.Config.Axiom.TestNetName, global.NativeToken, testRecipient, &AddressData{TxHash: "0x1", SendTxTime: time.Now().Unix()})
+
+	if attempts := atomic.LoadInt32(&s.attempts); attempts != 2 {
+		t.Fatalf("expect 2 attempts, got %d", attempts)
+	}
+	if len(c.unsavedClaims) != 0 || c.LastClaim(c.Config.Axiom.TestNetName, testRecipient) == nil {
+		t.Fatal("record should be persisted after the retry")
+	}
+}
+
+// 写入成功前按内存中的记录限制领取，后台写入成功后移出内存
+func TestUnsavedClaimLimitsUntilPersisted(t *testing.T) {
+	c, s := newFailingRecordClient(t, 0, -1)
+	if _, code, err := claim(c, context.Background(), testRecipient, 1); err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	if len(c.unsavedClaims) != 1 {
+		t.Fatalf("unsaved record should be kept in memory, got %d", len(c.unsavedClaims))
+	}
+	if _, code, _ := claim(c, context.Background(), testRecipient, 1); code != global.ReqWithinDayCode {
+		t.Fatalf("expect %d while the record is unsaved, got %d", global.ReqWithinDayCode, code)
+	}
+
+	atomic.StoreInt32(&s.failures, 0)
+	c.flushUnsavedClaims()
+	if len(c.unsavedClaims) != 0 || c.LastClaim(c.Config.Axiom.TestNetName, testRecipient) == nil {
+		t.Fatal("record should be persisted by the writer")
+	}
+}
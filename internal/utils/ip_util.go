@@ -85,3 +85,21 @@ func GetRealIp(r *http.Request) string {
 	}
 	return ip
 }
+
+// IPBucket 将 IP 按前缀长度归并为网段，用作 IP 限制的 key。前缀不小于地址位数或不大于 0 时保留单个地址，
+// 无法解析的 IP 原样返回
+func IPBucket(ip string, v4Prefix int, v6Prefix int) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	bits, prefix := 128, v6Prefix
+	if v4 := parsed.To4(); v4 != nil {
+		parsed, bits, prefix = v4, 32, v4Prefix
+	}
+	if prefix <= 0 || prefix >= bits {
+		return parsed.String()
+	}
+	mask := net.CIDRMask(prefix, bits)
+	return (&net.IPNet{IP: parsed.Mask(mask), Mask: mask}).String()
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+func TestIPBucket(t *testing.T) {
+	tests := []struct {
+		ip       string
+		v4Prefix int
+		v6Prefix int
+		want     string
+	}{
+		{"203.0.113.7", 32, 64, "203.0.113.7"},
+		{"203.0.113.7", 24, 64, "203.0.113.0/24"},
+		{"2001:db8:1:2:3:4:5:6", 32, 64, "2001:db8:1:2::/64"},
+		{"2001:db8:1:2:3:4:5:6", 32, 48, "2001:db8:1::/48"},
+		{"2001:db8:1:2:3:4:5:6", 32, 128, "2001:db8:1:2:3:4:5:6"},
+		{"2001:db8:1:2:3:4:5:6", 32, 0, "2001:db8:1:2:3:4:5:6"},
+		// IPv4 映射的 IPv6 地址按 IPv4 归并
+		{"::ffff:203.0.113.7", 24, 64, "203.0.113.0/24"},
+		{"not an ip", 24, 64, "not an ip"},
+	}
+	for _, tt := range tests {
+		if got := IPBucket(tt.ip, tt.v4Prefix, tt.v6Prefix); got != tt.want {
+			t.Errorf("IPBucket(%q, %d, %d) = %q, want %q", tt.ip, tt.v4Prefix, tt.v6Prefix, got, tt.want)
+		}
+	}
+}
@@ -5,14 +5,14 @@ import (
 	"time"
 )
 
-//Limiter 限流器对象
+// Limiter 限流器对象
 type Limiter struct {
 	value int64
 	max   int64
 	ts    int64
 }
 
-//NewLimiter 产生一个限流器
+// NewLimiter 产生一个限流器
 func NewLimiter(cnt int64) *Limiter {
 	return &Limiter{
 		value: 0,
@@ -21,7 +21,7 @@ func NewLimiter(cnt int64) *Limiter {
 	}
 }
 
-//Ok 是否可以通过
+// Ok 是否可以通过
 func (l *Limiter) Ok() bool {
 	ts := time.Now().Unix()
 	tsOld := atomic.LoadInt64(&l.ts)
@@ -33,7 +33,7 @@ func (l *Limiter) Ok() bool {
 	return atomic.AddInt64(&(l.value), 1) < l.max
 }
 
-//SetMax 设置最大限制
+// SetMax 设置最大限制
 func (l *Limiter) SetMax(m int64) {
 	l.max = m
 }
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// 签名请求头，格式为 sha256=<hex>，接收方使用相同的 secret 对原始请求体计算 HMAC-SHA256 并比较
+const webhookSignatureHeader = "X-Signature"
+
+// WebhookEvent 领取成功后推送给下游的通知
+type WebhookEvent struct {
+	Event   string  `json:"event"`
+	Net     string  `json:"net"`
+	Address string  `json:"address"`
+	TxHash  string  `json:"txHash"`
+	Amount  float64 `json:"amount"`
+	Time    int64   `json:"time"`
+}
+
+// notifyWebhook 异步推送通知，推送失败只记录日志，不影响领取结果
+func (c *Client) notifyWebhook(event *WebhookEvent) {
+	if c.Config.Webhook.URL == "" {
+		return
+	}
+	go func() {
+		if err := c.postWebhook(event); err != nil {
+			c.logger.Warnf("post webhook of %s failed: %v", event.TxHash, err)
+		}
+	}()
+}
+
+func (c *Client) postWebhook(event *WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, c.outboundTimeout(c.Config.Webhook.Timeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Config.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Config.Webhook.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, SignWebhook([]byte(c.Config.Webhook.Secret), body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook 计算推送请求体的签名头
+func SignWebhook(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newClaimEvent(net string, address string, data *AddressData) *WebhookEvent {
+	return &WebhookEvent{
+		Event:   "claim",
+		Net:     net,
+		Address: address,
+		TxHash:  data.TxHash,
+		Amount:  data.Amount,
+		Time:    data.SendTxTime,
+	}
+}
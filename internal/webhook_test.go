@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/axiomesh/faucet/internal/testutil"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+type webhookRequest struct {
+	body      []byte
+	signature string
+}
+
+func newWebhookReceiver(t *testing.T, status int) (*httptest.Server, chan webhookRequest) {
+	t.Helper()
+	requests := make(chan webhookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- webhookRequest{body: body, signature: r.Header.Get(webhookSignatureHeader)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestClaimPostsSignedWebhook(t *testing.T) {
+	receiver, requests := newWebhookReceiver(t, http.StatusOK)
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Webhook.URL = receiver.URL
+		cfg.Webhook.Secret = "webhook-secret"
+	})
+
+	txHash, code, err := claim(c, context.Background(), testRecipient, 1)
+	if err != nil {
+		t.Fatalf("claim failed: %d %v", code, err)
+	}
+	select {
+	case req := <-requests:
+		if want := SignWebhook([]byte("webhook-secret"), req.body); req.signature != want {
+			t.Fatalf("expect signature %s, got %s", want, req.signature)
+		}
+		event := &WebhookEvent{}
+		if err := json.Unmarshal(req.body, event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Event != "claim" || event.Address != testRecipient || event.TxHash != txHash || event.Amount != 1 {
+			t.Fatalf("unexpected webhook event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not received")
+	}
+}
+
+func TestPostWebhookWithoutSecret(t *testing.T) {
+	receiver, requests := newWebhookReceiver(t, http.StatusOK)
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Webhook.URL = receiver.URL
+	})
+
+	if err := c.postWebhook(&WebhookEvent{Event: "claim"}); err != nil {
+		t.Fatal(err)
+	}
+	if req := <-requests; req.signature != "" {
+		t.Fatalf("webhook without secret should not be signed, got %s", req.signature)
+	}
+}
+
+func TestPostWebhookReportsErrorStatus(t *testing.T) {
+	receiver, _ := newWebhookReceiver(t, http.StatusInternalServerError)
+	c := newTestClient(t, testutil.NewNode(t), func(cfg *repo.Config) {
+		cfg.Webhook.URL = receiver.URL
+	})
+
+	if err := c.postWebhook(&WebhookEvent{Event: "claim"}); err == nil {
+		t.Fatal("non-2xx status should be reported")
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '{"event":"claim"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=1f100a6de0fd9898aa8d0c341bfee9c2c3d0c6580bc9e8ae0bbcb5641f789a8f"
+	if got := SignWebhook([]byte("secret"), []byte(`{"event":"claim"}`)); got != want {
+		t.Fatalf("expect %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/axiomesh/faucet/pkg/loggers"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const (
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
+// 审计事件类型
+const (
+	EventClaim        = "claim"
+	EventClaimBlocked = "claim_blocked"
+	EventAdminAction  = "admin_action"
+)
+
+// Event 审计事件，所有事件类型共用同一结构，每个事件输出为一行 JSON
+type Event struct {
+	Time    int64   `json:"time"`
+	Type    string  `json:"type"`
+	Net     string  `json:"net,omitempty"`
+	Address string  `json:"address,omitempty"`
+	IP      string  `json:"ip,omitempty"`
+	Token   string  `json:"token,omitempty"`
+	TxHash  string  `json:"txHash,omitempty"`
+	Amount  float64 `json:"amount,omitempty"`
+	Action  string  `json:"action,omitempty"`
+	Detail  string  `json:"detail,omitempty"`
+}
+
+// Logger 审计日志，与运行日志分开输出，未配置 sink 时丢弃所有事件
+type Logger struct {
+	lock   sync.Mutex
+	writer io.Writer
+	redact bool
+}
+
+// New 创建审计日志，redact 为 true 时事件中的地址按 loggers.RedactAddress 脱敏
+func New(cfg repo.Audit, repoRoot string, redact bool) (*Logger, error) {
+	var writer io.Writer
+	switch cfg.Sink {
+	case "":
+		return &Logger{}, nil
+	case SinkStdout:
+		writer = os.Stdout
+	case SinkFile:
+		writer = &lumberjack.Logger{
+			Filename: filepath.Join(repoRoot, repo.LogsDirName, cfg.Filename),
+			MaxSize:  int(cfg.MaxSize),
+			MaxAge:   int(cfg.MaxAge),
+		}
+	case SinkSyslog:
+		syslogWriter, err := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, cfg.SyslogTag)
+		if err != nil {
+			return nil, fmt.Errorf("connect syslog: %w", err)
+		}
+		writer = syslogWriter
+	default:
+		return nil, fmt.Errorf("unsupported audit sink: %s", cfg.Sink)
+	}
+	logger := NewWithWriter(writer)
+	logger.redact = redact
+	return logger, nil
+}
+
+func NewWithWriter(writer io.Writer) *Logger {
+	return &Logger{writer: writer}
+}
+
+// Write 写入一条审计事件，未设置时间时使用当前时间
+func (l *Logger) Write(event *Event) error {
+	if l == nil || l.writer == nil {
+		return nil
+	}
+	if event.Time == 0 {
+		event.Time = time.Now().Unix()
+	}
+	if l.redact {
+		redacted := *event
+		redacted.Address = loggers.RedactAddresses(event.Address)
+		redacted.Detail = loggers.RedactAddresses(event.Detail)
+		event = &redacted
+	}
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json marshal failed: %w", err)
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err = l.writer.Write(append(raw, '\n'))
+	return err
+}
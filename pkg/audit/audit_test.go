@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/axiomesh/faucet/pkg/loggers"
+	"github.com/axiomesh/faucet/pkg/repo"
+)
+
+const testAddress = "0x1111111111111111111111111111111111111111"
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []*Event {
+	t.Helper()
+	var events []*Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		event := &Event{}
+		if err := json.Unmarshal([]byte(line), event); err != nil {
+			t.Fatalf("each event should be a json line, got %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestWriteEventLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithWriter(buf)
+
+	if err := logger.Write(&Event{Type: EventClaim, Address: testAddress, TxHash: "0xabc", Amount: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Write(&Event{Time: 100, Type: EventAdminAction, Action: "rotate_key"}); err != nil {
+		t.Fatal(err)
+	}
+	events := decodeEvents(t, buf)
+	if len(events) != 2 {
+		t.Fatalf("expect 2 events, got %d", len(events))
+	}
+	if events[0].Time == 0 || events[0].Address != testAddress || events[0].TxHash != "0xabc" {
+		t.Fatalf("unexpected claim event %+v", events[0])
+	}
+	if events[1].Time != 100 || events[1].Action != "rotate_key" {
+		t.Fatalf("unexpected admin event %+v", events[1])
+	}
+}
+
+func TestWriteRedactsAddresses(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithWriter(buf)
+	logger.redact = true
+
+	event := &Event{Type: EventClaimBlocked, Address: testAddress, Detail: "blocked " + testAddress}
+	if err := logger.Write(event); err != nil {
+		t.Fatal(err)
+	}
+	got := decodeEvents(t, buf)[0]
+	redacted := loggers.RedactAddress(testAddress)
+	if got.Address != redacted || got.Detail != "blocked "+redacted {
+		t.Fatalf("address should be redacted, got %+v", got)
+	}
+	// 脱敏不修改调用方的事件
+	if event.Address != testAddress {
+		t.Fatalf("caller's event should be kept, got %s", event.Address)
+	}
+}
+
+func TestNewWithoutSinkDiscards(t *testing.T) {
+	logger, err := New(repo.Audit{}, t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Write(&Event{Type: EventClaim}); err != nil {
+		t.Fatal(err)
+	}
+	var nilLogger *Logger
+	if err := nilLogger.Write(&Event{Type: EventClaim}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewRejectsUnknownSink(t *testing.T) {
+	if _, err := New(repo.Audit{Sink: "kafka"}, t.TempDir(), false); err == nil {
+		t.Fatal("unknown sink should be rejected")
+	}
+}
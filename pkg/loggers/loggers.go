@@ -50,6 +50,15 @@ func Initialize(ctx context.Context, rep *repo.Repo, persist bool) error {
 	m[ApiServer].Logger.SetLevel(log.ParseLevel(config.Log.Module.ApiServer))
 	m[Global] = log.NewWithModule(Global)
 	m[Global].Logger.SetLevel(log.ParseLevel(config.Log.Module.Global))
+	if config.Log.RedactAddresses {
+		hooked := make(map[*logrus.Logger]bool)
+		for _, entry := range m {
+			if !hooked[entry.Logger] {
+				entry.Logger.AddHook(redactHook{})
+				hooked[entry.Logger] = true
+			}
+		}
+	}
 
 	w = &LoggerWrapper{loggers: m}
 	return nil
@@ -0,0 +1,41 @@
+package loggers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 只匹配 40 位十六进制的地址，不匹配 64 位的交易哈希
+var addressRegex = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+
+// RedactAddress 将地址替换为前 6 个字符加地址哈希的前 8 位，同一地址的脱敏结果相同，便于关联日志
+func RedactAddress(address string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(address)))
+	return address[:6] + "…" + hex.EncodeToString(sum[:4])
+}
+
+// RedactAddresses 脱敏文本中出现的所有地址
+func RedactAddresses(text string) string {
+	return addressRegex.ReplaceAllStringFunc(text, RedactAddress)
+}
+
+// redactHook 在日志输出前脱敏消息和字符串字段中的地址
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = RedactAddresses(entry.Message)
+	for key, value := range entry.Data {
+		if text, ok := value.(string); ok {
+			entry.Data[key] = RedactAddresses(text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package loggers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testAddress = "0x1111111111111111111111111111111111111111"
+
+func TestRedactAddressIsStable(t *testing.T) {
+	redacted := RedactAddress(testAddress)
+	if !strings.HasPrefix(redacted, "0x1111") || strings.Contains(redacted, testAddress) {
+		t.Fatalf("address should keep only its prefix, got %s", redacted)
+	}
+	// 大小写不同的同一地址脱敏结果相同
+	if upper := RedactAddress("0x" + strings.ToUpper(testAddress[2:])); upper != redacted {
+		t.Fatalf("same address should redact the same, got %s and %s", redacted, upper)
+	}
+	if other := RedactAddress("0x1111222222222222222222222222222222222222"); other == redacted {
+		t.Fatal("different addresses should redact differently")
+	}
+}
+
+// 交易哈希不被脱敏
+func TestRedactAddressesKeepsTxHash(t *testing.T) {
+	txHash := "0x" + strings.Repeat("ab", 32)
+	text := "send to " + testAddress + ", tx " + txHash
+	want := "send to " + RedactAddress(testAddress) + ", tx " + txHash
+	if got := RedactAddresses(text); got != want {
+		t.Fatalf("expect %q, got %q", want, got)
+	}
+}
+
+func TestRedactHook(t *testing.T) {
+	entry := &logrus.Entry{
+		Message: "claim of " + testAddress,
+		Data:    logrus.Fields{"address": testAddress, "amount": 1},
+	}
+	if err := (redactHook{}).Fire(entry); err != nil {
+		t.Fatal(err)
+	}
+	redacted := RedactAddress(testAddress)
+	if entry.Message != "claim of "+redacted || entry.Data["address"] != redacted {
+		t.Fatalf("message and fields should be redacted, got %q %v", entry.Message, entry.Data)
+	}
+	if entry.Data["amount"] != 1 {
+		t.Fatalf("non string fields should be kept, got %v", entry.Data["amount"])
+	}
+}
@@ -26,16 +26,224 @@ func (d *Duration) String() string {
 }
 
 type Config struct {
-	Axiom    AXIOM    `mapstructure:"axiom" toml:"axiom"`
-	Network  Network  `mapstructure:"network" toml:"network"`
-	Log      Log      `mapstructure:"log" toml:"log"`
-	Scrapper Scrapper `mapstructure:"scrapper" toml:"scrapper"`
+	Axiom           AXIOM           `mapstructure:"axiom" toml:"axiom"`
+	Network         Network         `mapstructure:"network" toml:"network"`
+	Log             Log             `mapstructure:"log" toml:"log"`
+	Scrapper        Scrapper        `mapstructure:"scrapper" toml:"scrapper"`
+	AuthorizedClaim AuthorizedClaim `mapstructure:"authorized_claim" toml:"authorized_claim"`
+	RequestSign     RequestSign     `mapstructure:"request_sign" toml:"request_sign"`
+	Campaign        Campaign        `mapstructure:"campaign" toml:"campaign"`
+	Queue           Queue           `mapstructure:"queue" toml:"queue"`
+	ClaimEvents     ClaimEvents     `mapstructure:"claim_events" toml:"claim_events"`
+	Maintenance     Maintenance     `mapstructure:"maintenance" toml:"maintenance"`
+	Event           Event           `mapstructure:"event" toml:"event"`
+	SignatureClaim  SignatureClaim  `mapstructure:"signature_claim" toml:"signature_claim"`
+	Admin           Admin           `mapstructure:"admin" toml:"admin"`
+	Webhook         Webhook         `mapstructure:"webhook" toml:"webhook"`
+	Email           Email           `mapstructure:"email" toml:"email"`
+	Audit           Audit           `mapstructure:"audit" toml:"audit"`
+	Risk            Risk            `mapstructure:"risk" toml:"risk"`
+	Referral        Referral        `mapstructure:"referral" toml:"referral"`
+	Sanction        Sanction        `mapstructure:"sanction" toml:"sanction"`
+	// DevMode 本地开发模式，跳过推文验证、每日领取间隔、IP 地址数与每日地址数限制以及接口限流，禁止在生产环境开启
+	DevMode bool `mapstructure:"dev_mode" toml:"dev_mode"`
+}
+
+// Sanction 制裁/风险名单检查，配置 list_path 时使用本地名单文件，否则请求 url（{address} 为占位符，
+// 配置 api_key 时放在 X-API-Key 请求头中），响应 JSON 中 result_field 为 true 或非空数组时视为命中。
+// 结果缓存 cache_ttl，fail_open 为 true 时查询失败放行，否则拒绝；timeout 为 0 时使用 network.outbound_timeout
+type Sanction struct {
+	Enable      bool     `mapstructure:"enable" toml:"enable"`
+	ListPath    string   `mapstructure:"list_path" toml:"list_path"`
+	URL         string   `mapstructure:"url" toml:"url"`
+	APIKey      string   `mapstructure:"api_key" toml:"api_key"`
+	ResultField string   `mapstructure:"result_field" toml:"result_field"`
+	Timeout     Duration `mapstructure:"timeout" toml:"timeout"`
+	CacheTTL    Duration `mapstructure:"cache_ttl" toml:"cache_ttl"`
+	FailOpen    bool     `mapstructure:"fail_open" toml:"fail_open"`
+}
+
+// Referral 推荐码配置，开启后直接、推文、异步、多币种、授权与签名领取必须携带有效的推荐码，
+// codes 中的推荐码启动时写入存储，管理员也可以通过管理接口生成；max_bonus 为推荐码额外奖励的上限
+type Referral struct {
+	Enable   bool           `mapstructure:"enable" toml:"enable"`
+	MaxBonus float64        `mapstructure:"max_bonus" toml:"max_bonus"`
+	Codes    []ReferralCode `mapstructure:"codes" toml:"codes"`
+}
+
+// ReferralCode max_uses 为 0 时不限制使用次数，bonus 为使用推荐码领取时额外发放的数量
+type ReferralCode struct {
+	Code    string  `mapstructure:"code" toml:"code"`
+	MaxUses int     `mapstructure:"max_uses" toml:"max_uses"`
+	Bonus   float64 `mapstructure:"bonus" toml:"bonus"`
+}
+
+// Risk 综合风险评分，各信号命中时累加对应权重，总分超过 threshold 时拒绝领取，权重为 0 的信号不采集
+type Risk struct {
+	Enable    bool    `mapstructure:"enable" toml:"enable"`
+	Threshold float64 `mapstructure:"threshold" toml:"threshold"`
+	// NewAccount 地址从未发送过交易
+	NewAccount float64 `mapstructure:"new_account" toml:"new_account"`
+	// HasBalance 地址已有余额
+	HasBalance float64 `mapstructure:"has_balance" toml:"has_balance"`
+	// Flagged 地址被管理员标记
+	Flagged float64 `mapstructure:"flagged" toml:"flagged"`
+	// PerIPAddress 同一 IP 此前每领取过一个其他地址累加一次，依赖 axiom.max_addresses_per_ip 开启后记录的地址数
+	PerIPAddress float64 `mapstructure:"per_ip_address" toml:"per_ip_address"`
+	// PerPriorClaim 地址此前每成功领取一次累加一次
+	PerPriorClaim float64 `mapstructure:"per_prior_claim" toml:"per_prior_claim"`
+}
+
+// Audit 审计日志配置，sink 可选 stdout、file、syslog，为空时不记录
+type Audit struct {
+	Sink      string `mapstructure:"sink" toml:"sink"`
+	Filename  string `mapstructure:"filename" toml:"filename"`
+	MaxSize   uint   `mapstructure:"max_size" toml:"max_size"`
+	MaxAge    uint   `mapstructure:"max_age" toml:"max_age"`
+	SyslogTag string `mapstructure:"syslog_tag" toml:"syslog_tag"`
+}
+
+// Webhook 领取成功后推送通知，url 为空时不推送；配置 secret 时在 X-Signature 头中附带 sha256=<请求体的 HMAC-SHA256>。
+// timeout 为 0 时使用 network.outbound_timeout
+type Webhook struct {
+	URL     string   `mapstructure:"url" toml:"url"`
+	Secret  string   `mapstructure:"secret" toml:"secret"`
+	Timeout Duration `mapstructure:"timeout" toml:"timeout"`
+}
+
+// Email 领取成功后向请求中填写的邮箱发送回执，host 为空时不发送，timeout 为 0 时使用 network.outbound_timeout
+type Email struct {
+	Host     string   `mapstructure:"host" toml:"host"`
+	Port     int      `mapstructure:"port" toml:"port"`
+	Username string   `mapstructure:"username" toml:"username"`
+	Password string   `mapstructure:"password" toml:"password"`
+	From     string   `mapstructure:"from" toml:"from"`
+	Subject  string   `mapstructure:"subject" toml:"subject"`
+	Timeout  Duration `mapstructure:"timeout" toml:"timeout"`
+}
+
+// Admin 管理接口配置，token 为空时不开启管理接口
+type Admin struct {
+	Token            string   `mapstructure:"token" toml:"token"`
+	OverviewCacheTTL Duration `mapstructure:"overview_cache_ttl" toml:"overview_cache_ttl"`
+	// MaxAmount 管理员指定数量领取的上限，超过时按上限发放
+	MaxAmount float64 `mapstructure:"max_amount" toml:"max_amount"`
+}
+
+// SignatureClaim 钱包签名领取配置，签名时间戳与服务器时间相差超过 window 时拒绝
+type SignatureClaim struct {
+	Enable bool     `mapstructure:"enable" toml:"enable"`
+	Window Duration `mapstructure:"window" toml:"window"`
+}
+
+// Maintenance 定期维护时间段，格式为 "02:00-03:00" 或 "Sat 22:00-02:00"，维护期间暂停领取
+type Maintenance struct {
+	Timezone string   `mapstructure:"timezone" toml:"timezone"`
+	Windows  []string `mapstructure:"windows" toml:"windows"`
+}
+
+// Event 限时活动，只在 start 到 end 之间提供领取，格式为 "2006-01-02 15:04"，按 timezone 解析，留空的一端不限制
+type Event struct {
+	Timezone string `mapstructure:"timezone" toml:"timezone"`
+	Start    string `mapstructure:"start" toml:"start"`
+	End      string `mapstructure:"end" toml:"end"`
+}
+
+// Queue 异步领取队列配置，ticket_ttl 为处理完成的凭证保留时间
+type Queue struct {
+	Enable    bool     `mapstructure:"enable" toml:"enable"`
+	Size      int      `mapstructure:"size" toml:"size"`
+	TicketTTL Duration `mapstructure:"ticket_ttl" toml:"ticket_ttl"`
+	// DrainTimeout 停止服务时继续处理队列中领取的最长时间，剩余的领取在重启后恢复
+	DrainTimeout Duration `mapstructure:"drain_timeout" toml:"drain_timeout"`
+}
+
+// ClaimEvents 开启后提供 /faucet/claim/:hash/events，以 SSE 推送领取交易的上链状态。
+// poll_interval 为轮询节点的间隔，max_duration 为单个连接的最长时间，应小于 network.write_timeout
+type ClaimEvents struct {
+	Enable       bool     `mapstructure:"enable" toml:"enable"`
+	PollInterval Duration `mapstructure:"poll_interval" toml:"poll_interval"`
+	MaxDuration  Duration `mapstructure:"max_duration" toml:"max_duration"`
+}
+
+// Campaign 领取来源/活动标记的白名单，请求中的 source 必须在其中
+type Campaign struct {
+	Sources []string `mapstructure:"sources" toml:"sources"`
+	// Proofs 按活动配置的链上行为要求，未配置的活动不要求
+	Proofs []ActionProof `mapstructure:"proofs" toml:"proofs"`
+	// Holdings 按活动配置的持有要求，未配置的活动不要求
+	Holdings []HoldingPolicy `mapstructure:"holdings" toml:"holdings"`
+}
+
+// ActionProof 要求领取地址在 window 内触发过 contract 的 event 事件，
+// event 为事件签名如 Transfer(address,address,uint256)，address_topic 为地址所在的 indexed 参数位置（1-3），window 为 0 时不限制时间
+type ActionProof struct {
+	Source       string   `mapstructure:"source" toml:"source"`
+	Contract     string   `mapstructure:"contract" toml:"contract"`
+	Event        string   `mapstructure:"event" toml:"event"`
+	AddressTopic int      `mapstructure:"address_topic" toml:"address_topic"`
+	Window       Duration `mapstructure:"window" toml:"window"`
+}
+
+// HoldingPolicy 要求领取地址持有 contract 的代币或 NFT：配置 token_id 时按 ownerOf 校验指定 NFT，
+// 否则按 balanceOf 校验余额不低于 min_balance（最小单位，为空时为 1）
+type HoldingPolicy struct {
+	Source     string `mapstructure:"source" toml:"source"`
+	Contract   string `mapstructure:"contract" toml:"contract"`
+	MinBalance string `mapstructure:"min_balance" toml:"min_balance"`
+	TokenID    string `mapstructure:"token_id" toml:"token_id"`
+}
+
+// RequestSign 可信调用方的请求签名配置，timestamp 与服务器时间相差超过 window 的请求将被拒绝
+type RequestSign struct {
+	Enable   bool     `mapstructure:"enable" toml:"enable"`
+	Required bool     `mapstructure:"required" toml:"required"`
+	Window   Duration `mapstructure:"window" toml:"window"`
+	ApiKeys  []ApiKey `mapstructure:"api_keys" toml:"api_keys"`
+}
+
+type ApiKey struct {
+	ID     string `mapstructure:"id" toml:"id"`
+	Secret string `mapstructure:"secret" toml:"secret"`
+}
+
+// AuthorizedClaim 可信后端签发 JWT 授权领取的配置，key_path 为 HMAC 密钥或公钥 PEM 文件
+type AuthorizedClaim struct {
+	Enable    bool   `mapstructure:"enable" toml:"enable"`
+	Algorithm string `mapstructure:"algorithm" toml:"algorithm"`
+	KeyPath   string `mapstructure:"key_path" toml:"key_path"`
+	// MaxTTL 授权 token 的过期时间距当前时间不能超过该值，拒绝长期有效的 token
+	MaxTTL Duration `mapstructure:"max_ttl" toml:"max_ttl"`
 }
 
 type Scrapper struct {
-	ScrapperAddr string `mapstructure:"scrapper_addr" toml:"scrapper_addr"`
+	ScrapperAddr string   `mapstructure:"scrapper_addr" toml:"scrapper_addr"`
+	TweetDomains []string `mapstructure:"tweet_domains" toml:"tweet_domains"`
+	// Retries 推文验证服务网络错误或 5xx 时的重试次数，CacheTTL 为验证成功结果的缓存时间
+	Retries  int      `mapstructure:"retries" toml:"retries"`
+	CacheTTL Duration `mapstructure:"cache_ttl" toml:"cache_ttl"`
+	// Timeout 单次请求推文验证服务的超时，0 时使用 network.outbound_timeout
+	Timeout Duration `mapstructure:"timeout" toml:"timeout"`
+	// MaxClaimsPerAuthor 同一推特账号的推文每天可以领取的次数，0 表示不限制
+	MaxClaimsPerAuthor int `mapstructure:"max_claims_per_author" toml:"max_claims_per_author"`
+	// MaxURLLength 推文链接的最大长度，超过时不进行格式校验直接拒绝
+	MaxURLLength int `mapstructure:"max_url_length" toml:"max_url_length"`
+	// UnavailablePolicy 推文验证服务重试后仍不可用时的处理方式：fail 返回验证不可用错误；
+	// degraded 只校验推文链接格式，按 degraded_multiplier 倍发放且不发放首次推文奖励
+	UnavailablePolicy  string  `mapstructure:"unavailable_policy" toml:"unavailable_policy"`
+	DegradedMultiplier float64 `mapstructure:"degraded_multiplier" toml:"degraded_multiplier"`
 }
 
+const (
+	VerifierPolicyFail     = "fail"
+	VerifierPolicyDegraded = "degraded"
+)
+
+const (
+	NonceGapPolicyReuse  = "reuse"
+	NonceGapPolicyRefuse = "refuse"
+)
+
 // Log are config about log
 type Log struct {
 	Filename     string `mapstructure:"filename" toml:"filename"`
@@ -48,6 +256,8 @@ type Log struct {
 	MaxAge           uint     `mapstructure:"max_age" toml:"max_age"`
 	MaxSize          uint     `mapstructure:"max_size" toml:"max_size"`
 	RotationTime     Duration `mapstructure:"rotation_time" toml:"rotation_time"`
+	// RedactAddresses 运行日志与审计日志中的地址只保留前缀和哈希，默认输出完整地址
+	RedactAddresses bool `mapstructure:"redact_addresses" toml:"redact_addresses"`
 
 	Module LogModule `mapstructure:"module" toml:"module"`
 }
@@ -57,34 +267,246 @@ type LogModule struct {
 }
 
 type AXIOM struct {
-	TestNetName  string  `mapstructure:"test_net_name" json:"test_net_name" toml:"test_net_name"`
-	FaucetAddr   string  `mapstructure:"faucet_addr" json:"faucet_addr" toml:"faucet_addr"`
-	AxiomAddr    string  `mapstructure:"axiom_addr" json:"axiom_addr" toml:"axiom_addr"`
-	AxiomKeyPath string  `mapstructure:"axiom_key_path" json:"axiom_key_path" toml:"axiom_key_path"`
-	Amount       float64 `mapstructure:"amount" json:"amount" toml:"amount"`
-	TweetAmount  float64 `mapstructure:"tweet_amount" json:"tweet_amount" toml:"tweet_amount"`
-	ClaimLimit   float64 `mapstructure:"claim_limit" json:"claim_limit" toml:"claim_limit"`
-	GasLimit     uint64  `mapstructure:"gas_limit" json:"gas_limit" toml:"gas_limit"`
+	TestNetName  string `mapstructure:"test_net_name" json:"test_net_name" toml:"test_net_name"`
+	ChainID      uint64 `mapstructure:"chain_id" json:"chain_id" toml:"chain_id"`
+	FaucetAddr   string `mapstructure:"faucet_addr" json:"faucet_addr" toml:"faucet_addr"`
+	AxiomAddr    string `mapstructure:"axiom_addr" json:"axiom_addr" toml:"axiom_addr"`
+	AxiomKeyPath string `mapstructure:"axiom_key_path" json:"axiom_key_path" toml:"axiom_key_path"`
+	// AxiomKey 资金账户私钥，支持 ${ENV_VAR} 或 file:path 引用，配置后优先于 axiom_key_path
+	AxiomKey    string  `mapstructure:"axiom_key" json:"-" toml:"axiom_key"`
+	Amount      float64 `mapstructure:"amount" json:"amount" toml:"amount"`
+	TweetAmount float64 `mapstructure:"tweet_amount" json:"tweet_amount" toml:"tweet_amount"`
+	ClaimLimit  float64 `mapstructure:"claim_limit" json:"claim_limit" toml:"claim_limit"`
+	GasLimit    uint64  `mapstructure:"gas_limit" json:"gas_limit" toml:"gas_limit"`
+	// GasLimitMultiplier 大于 0 时按 eth_estimateGas 结果乘以该倍数作为 gas limit，
+	// 并限制在 [GasLimitFloor, GasLimit] 之间；为 0 时固定使用 GasLimit
+	GasLimitMultiplier float64 `mapstructure:"gas_limit_multiplier" json:"gas_limit_multiplier" toml:"gas_limit_multiplier"`
+	GasLimitFloor      uint64  `mapstructure:"gas_limit_floor" json:"gas_limit_floor" toml:"gas_limit_floor"`
+	// MaxClockSkew 领取记录时间戳允许超前当前时间的最大偏差
+	MaxClockSkew Duration `mapstructure:"max_clock_skew" json:"max_clock_skew" toml:"max_clock_skew"`
+	// MinAccountNonce、MinAccountAge 为领取地址的链上活跃度要求，为 0 时不校验
+	MinAccountNonce uint64   `mapstructure:"min_account_nonce" json:"min_account_nonce" toml:"min_account_nonce"`
+	MinAccountAge   Duration `mapstructure:"min_account_age" json:"min_account_age" toml:"min_account_age"`
+	// TopUpTarget 大于 0 时只补足到该余额，不再固定发送 amount
+	TopUpTarget float64 `mapstructure:"top_up_target" json:"top_up_target" toml:"top_up_target"`
+	// MaxSaneAmount、MinSaneAmount 启动时校验 amount、tweet_amount、top_up_target 的合理范围，
+	// 用于发现 wei 与整币单位混用的配置错误，为 0 时不校验该端
+	MaxSaneAmount float64 `mapstructure:"max_sane_amount" json:"max_sane_amount" toml:"max_sane_amount"`
+	MinSaneAmount float64 `mapstructure:"min_sane_amount" json:"min_sane_amount" toml:"min_sane_amount"`
+	// SelfTest 启动时模拟一次领取，配置有误时直接退出
+	SelfTest bool `mapstructure:"self_test" json:"self_test" toml:"self_test"`
+	// StrictKey 资金账户私钥无效时直接退出；为 false 时只停用领取，其余接口正常服务，可通过管理接口轮换私钥恢复
+	StrictKey bool `mapstructure:"strict_key" json:"strict_key" toml:"strict_key"`
+	// AllowInsecureKey 允许使用公开的测试私钥，仅用于本地开发
+	AllowInsecureKey bool `mapstructure:"allow_insecure_key" json:"allow_insecure_key" toml:"allow_insecure_key"`
+	// ClaimTierMultipliers 按此前成功领取次数（0 次、1 次...）对应的发放倍数，最后一档为上限，为空时不分档
+	ClaimTierMultipliers []float64 `mapstructure:"claim_tier_multipliers" json:"claim_tier_multipliers" toml:"claim_tier_multipliers"`
+	// BalanceTiers 按水龙头余额分档调整发放倍数，余额不低于 above 的最高一档生效，余额低于所有档位时按最低一档发放，为空时不分档
+	BalanceTiers []BalanceTier `mapstructure:"balance_tiers" json:"balance_tiers" toml:"balance_tiers"`
+	// CongestionTiers 按节点最新区块的 base fee（gwei）分档调整发放倍数，base fee 不低于 base_fee_above 的最高一档生效，
+	// 低于所有档位时不调整，为空时不分档
+	CongestionTiers []CongestionTier `mapstructure:"congestion_tiers" json:"congestion_tiers" toml:"congestion_tiers"`
+	// DroppedTxTimeout 大于 0 时开启交易确认跟踪，发送超过该时长仍查不到回执的交易视为被丢弃，清除领取记录允许重新领取
+	DroppedTxTimeout Duration `mapstructure:"dropped_tx_timeout" json:"dropped_tx_timeout" toml:"dropped_tx_timeout"`
+	// RecordWriteRetries 交易发出后写入领取记录失败时的重试次数，仍失败时记录保存在内存中
+	RecordWriteRetries int `mapstructure:"record_write_retries" json:"record_write_retries" toml:"record_write_retries"`
+	// RecordRetryInterval 后台重新写入内存中领取记录的间隔，0 表示不重新写入
+	RecordRetryInterval Duration `mapstructure:"record_retry_interval" json:"record_retry_interval" toml:"record_retry_interval"`
+	// StuckTxThreshold 大于 0 时开启加价重发：发送后未及时确认的交易等待超过该时长且 base fee 上涨时，
+	// 以相同 nonce 按 gas_bump_percent 提高费用重发，每笔交易最多加价 max_gas_bumps 次
+	StuckTxThreshold Duration `mapstructure:"stuck_tx_threshold" json:"stuck_tx_threshold" toml:"stuck_tx_threshold"`
+	// StuckTxCheckInterval 检查未确认交易的间隔
+	StuckTxCheckInterval Duration `mapstructure:"stuck_tx_check_interval" json:"stuck_tx_check_interval" toml:"stuck_tx_check_interval"`
+	MaxGasBumps          int      `mapstructure:"max_gas_bumps" json:"max_gas_bumps" toml:"max_gas_bumps"`
+	// GasBumpPercent 每次加价的百分比，节点替换交易通常要求至少提高 10%
+	GasBumpPercent int `mapstructure:"gas_bump_percent" json:"gas_bump_percent" toml:"gas_bump_percent"`
+	// NonceGapPolicy 已发出的交易从交易池中消失导致 nonce 缺口时的处理方式：
+	// reuse 从节点的 pending nonce 继续发送以填补缺口；refuse 拒绝领取直到缺口被填补
+	NonceGapPolicy string `mapstructure:"nonce_gap_policy" json:"nonce_gap_policy" toml:"nonce_gap_policy"`
+	// DroppedTxCheckInterval 交易确认跟踪的检查间隔
+	DroppedTxCheckInterval Duration `mapstructure:"dropped_tx_check_interval" json:"dropped_tx_check_interval" toml:"dropped_tx_check_interval"`
+	// MaxDailyRecipients 每个网络每天最多发放的不同地址数，0 表示不限制
+	MaxDailyRecipients int `mapstructure:"max_daily_recipients" json:"max_daily_recipients" toml:"max_daily_recipients"`
+	// MaxAddressesPerIP 单个 IP 累计可以领取的不同地址数量，0 表示不限制
+	MaxAddressesPerIP int `mapstructure:"max_addresses_per_ip" json:"max_addresses_per_ip" toml:"max_addresses_per_ip"`
+	// ExplorerTxURL、WalletDeeplink 领取成功响应中附带的链接模板，为空时不返回，
+	// 支持 {txHash}、{address} 占位符，deeplink 还支持 {explorerUrl}
+	ExplorerTxURL  string `mapstructure:"explorer_tx_url" json:"explorer_tx_url" toml:"explorer_tx_url"`
+	WalletDeeplink string `mapstructure:"wallet_deeplink" json:"wallet_deeplink" toml:"wallet_deeplink"`
+	// MaxChainLag 节点最新区块时间落后超过该时长时拒绝领取，0 表示不检查
+	MaxChainLag Duration `mapstructure:"max_chain_lag" json:"max_chain_lag" toml:"max_chain_lag"`
+	// TesterAllowlist 测试地址白名单，不受每日领取间隔限制
+	TesterAllowlist []string `mapstructure:"tester_allowlist" json:"tester_allowlist" toml:"tester_allowlist"`
+	// WaitForReceipt 领取响应中附带交易回执的实际 gas 用量与 gas 价格
+	WaitForReceipt bool `mapstructure:"wait_for_receipt" json:"wait_for_receipt" toml:"wait_for_receipt"`
+	// FirstTweetBonus 地址首次通过推文领取时在 tweet_amount 之外额外发放的数量，只发放一次
+	FirstTweetBonus float64 `mapstructure:"first_tweet_bonus" json:"first_tweet_bonus" toml:"first_tweet_bonus"`
+	// PreflightBalanceCheck 加地址预锁之前先确认水龙头余额（扣除 gas_reserve）足够发放一次，
+	// 余额明显不足时直接拒绝，不占用领取次数
+	PreflightBalanceCheck bool `mapstructure:"preflight_balance_check" json:"preflight_balance_check" toml:"preflight_balance_check"`
+	// BalanceCacheInterval 大于 0 时缓存水龙头合约余额并按该间隔刷新，status 与余额预检读取缓存，发送交易前仍查询链上余额
+	BalanceCacheInterval Duration `mapstructure:"balance_cache_interval" json:"balance_cache_interval" toml:"balance_cache_interval"`
+	// ReserveInFlight 已发出、尚未确认的领取数量从可用余额中扣除，避免并发领取时超发导致交易因余额不足失败
+	ReserveInFlight bool `mapstructure:"reserve_in_flight" json:"reserve_in_flight" toml:"reserve_in_flight"`
+	// RPCRetryBudget、RPCRetryBackoff rpc 服务商限流（429）时发送交易的最大重试次数和首次重试间隔，之后每次间隔翻倍
+	RPCRetryBudget  int      `mapstructure:"rpc_retry_budget" json:"rpc_retry_budget" toml:"rpc_retry_budget"`
+	RPCRetryBackoff Duration `mapstructure:"rpc_retry_backoff" json:"rpc_retry_backoff" toml:"rpc_retry_backoff"`
+	// AmountRules 按顺序匹配的数量规则，第一条匹配的规则决定发放数量（之后仍按 claim_tier_multipliers 分档），
+	// 没有规则或都不匹配时使用 amount/tweet_amount；管理员指定数量的领取不使用规则
+	AmountRules []AmountRule `mapstructure:"amount_rules" json:"amount_rules" toml:"amount_rules"`
+	// GasReserve 支付 gas 的资金账户保留的最低余额，余额低于该值时拒绝领取，0 表示不检查
+	GasReserve float64 `mapstructure:"gas_reserve" json:"gas_reserve" toml:"gas_reserve"`
+	// Tokens 可领取的 ERC-20 测试代币，amount 为可读数量，按链上 decimals 换算
+	Tokens []Token `mapstructure:"tokens" json:"tokens" toml:"tokens"`
+}
+
+// BalanceTier 水龙头余额（ether）不低于 above 时按 multiplier 倍发放
+type BalanceTier struct {
+	Above      float64 `mapstructure:"above" json:"above" toml:"above"`
+	Multiplier float64 `mapstructure:"multiplier" json:"multiplier" toml:"multiplier"`
+}
+
+// CongestionTier 节点 base fee（gwei）不低于 base_fee_above 时按 multiplier 倍发放
+type CongestionTier struct {
+	BaseFeeAbove float64 `mapstructure:"base_fee_above" json:"base_fee_above" toml:"base_fee_above"`
+	Multiplier   float64 `mapstructure:"multiplier" json:"multiplier" toml:"multiplier"`
+}
+
+// AmountRule when 由 && 连接的条件组成，如 "balance < 1 && claims == 0"，可用信号为 balance（领取地址余额，ether）、
+// claims（此前成功领取次数）和 net（网络名，只支持 == 与 !=），when 为空时总是匹配
+type AmountRule struct {
+	When   string  `mapstructure:"when" json:"when" toml:"when"`
+	Amount float64 `mapstructure:"amount" json:"amount" toml:"amount"`
+}
+
+type Token struct {
+	Name    string  `mapstructure:"name" json:"name" toml:"name"`
+	Address string  `mapstructure:"address" json:"address" toml:"address"`
+	Amount  float64 `mapstructure:"amount" json:"amount" toml:"amount"`
 }
 
 type Network struct {
-	Port string `mapstructure:"port" toml:"port"`
+	Port          string   `mapstructure:"port" toml:"port"`
+	StatsCacheTTL Duration `mapstructure:"stats_cache_ttl" toml:"stats_cache_ttl"`
+	// ConfigCacheTTL、StatusCacheTTL 为只读接口响应头中的缓存时间
+	ConfigCacheTTL Duration  `mapstructure:"config_cache_ttl" toml:"config_cache_ttl"`
+	StatusCacheTTL Duration  `mapstructure:"status_cache_ttl" toml:"status_cache_ttl"`
+	RateLimit      RateLimit `mapstructure:"rate_limit" toml:"rate_limit"`
+	// EnableExport 开启 /faucet/export 导出全部领取记录
+	EnableExport bool `mapstructure:"enable_export" toml:"enable_export"`
+	// ClaimDedupeWindow 相同地址与网络的直接领取、推文领取在该窗口内合并，返回同一个结果，0 表示不合并
+	ClaimDedupeWindow Duration `mapstructure:"claim_dedupe_window" toml:"claim_dedupe_window"`
+	// ReportAllErrors 领取请求执行全部参数校验，在 errors 中返回所有失败原因，默认只返回第一个
+	ReportAllErrors bool `mapstructure:"report_all_errors" toml:"report_all_errors"`
+	// AmountFormat 响应中数量的默认格式：ether、decimal（十进制 wei）或 hex（十六进制 wei）
+	AmountFormat string `mapstructure:"amount_format" toml:"amount_format"`
+	// EnableGzip 对只读接口开启 gzip 压缩，小于 gzip_min_size 字节的响应不压缩
+	EnableGzip  bool `mapstructure:"enable_gzip" toml:"enable_gzip"`
+	GzipMinSize int  `mapstructure:"gzip_min_size" toml:"gzip_min_size"`
+	// EnableMetrics 开启 /metrics，输出限流拒绝次数，按接口和 IP 段统计 limiter_metrics_window 内的次数
+	EnableMetrics        bool     `mapstructure:"enable_metrics" toml:"enable_metrics"`
+	LimiterMetricsWindow Duration `mapstructure:"limiter_metrics_window" toml:"limiter_metrics_window"`
+	// LimiterMetricsLogInterval 定期将限流拒绝统计写入日志，0 表示不写
+	LimiterMetricsLogInterval Duration `mapstructure:"limiter_metrics_log_interval" toml:"limiter_metrics_log_interval"`
+	// LatencyBuckets 领取耗时分布的分桶上界（秒），应包含 claim_slo 以便按桶计算达标比例；
+	// ClaimSLO 领取交易发出后应在该时长内确认，/metrics 输出在该时长内确认的领取数与总数
+	LatencyBuckets []float64 `mapstructure:"latency_buckets" toml:"latency_buckets"`
+	ClaimSLO       Duration  `mapstructure:"claim_slo" toml:"claim_slo"`
+	// HTTP 服务的超时设置，0 表示不超时；write_timeout 同样限制 export 等流式接口的总耗时
+	ReadTimeout       Duration `mapstructure:"read_timeout" toml:"read_timeout"`
+	ReadHeaderTimeout Duration `mapstructure:"read_header_timeout" toml:"read_header_timeout"`
+	WriteTimeout      Duration `mapstructure:"write_timeout" toml:"write_timeout"`
+	IdleTimeout       Duration `mapstructure:"idle_timeout" toml:"idle_timeout"`
+	// OutboundTimeout 请求推文验证、制裁名单、webhook、邮件等外部服务的默认超时，各服务的 timeout 为 0 时使用
+	OutboundTimeout Duration `mapstructure:"outbound_timeout" toml:"outbound_timeout"`
+	// IPv4Prefix、IPv6Prefix IP 限制按该前缀长度归并网段计数，IPv6 用户通常可在同一 /64 内任意更换地址；
+	// 前缀为 0 或不小于地址位数时按单个地址计数
+	IPv4Prefix int `mapstructure:"ipv4_prefix" toml:"ipv4_prefix"`
+	IPv6Prefix int `mapstructure:"ipv6_prefix" toml:"ipv6_prefix"`
+	// TrustedProxies 可信反向代理的 IP 或网段，只有来自这些地址的请求才按 X-Forwarded-For 等请求头取客户端 IP，
+	// 为空时一律使用连接的对端地址
+	TrustedProxies []string `mapstructure:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// RateLimit 每秒允许的最大请求数，global 作用于所有请求，其余各接口独立计数，为 0 时不限制
+type RateLimit struct {
+	Global          int64 `mapstructure:"global" toml:"global"`
+	DirectClaim     int64 `mapstructure:"direct_claim" toml:"direct_claim"`
+	TweetClaim      int64 `mapstructure:"tweet_claim" toml:"tweet_claim"`
+	AuthorizedClaim int64 `mapstructure:"authorized_claim" toml:"authorized_claim"`
+	PreCheck        int64 `mapstructure:"pre_check" toml:"pre_check"`
+	Read            int64 `mapstructure:"read" toml:"read"`
+	// PreCheckInterval 同一 IP 两次 preCheck 的最小间隔，与上面的每秒限流独立，0 表示不限制
+	PreCheckInterval Duration `mapstructure:"pre_check_interval" toml:"pre_check_interval"`
 }
 
 func DefaultConfig() *Config {
 	return &Config{
 		Axiom: AXIOM{
-			TestNetName:  "Taurus",
-			FaucetAddr:   "0x0000000000000000000000000000000000000000",
-			AxiomAddr:    "http://127.0.0.1:8881",
-			AxiomKeyPath: "axiom.account.key",
-			Amount:       100,
-			TweetAmount:  200,
-			ClaimLimit:   600,
-			GasLimit:     100000,
+			TestNetName:            "Taurus",
+			FaucetAddr:             "0x0000000000000000000000000000000000000000",
+			AxiomAddr:              "http://127.0.0.1:8881",
+			AxiomKeyPath:           "axiom.account.key",
+			Amount:                 100,
+			TweetAmount:            200,
+			ClaimLimit:             600,
+			MaxSaneAmount:          1000,
+			MinSaneAmount:          0.000000001,
+			GasLimit:               100000,
+			GasLimitMultiplier:     0,
+			GasLimitFloor:          50000,
+			SelfTest:               false,
+			AllowInsecureKey:       false,
+			ClaimTierMultipliers:   []float64{},
+			BalanceTiers:           []BalanceTier{},
+			CongestionTiers:        []CongestionTier{},
+			DroppedTxTimeout:       0,
+			DroppedTxCheckInterval: Duration(time.Minute),
+			StuckTxThreshold:       0,
+			StuckTxCheckInterval:   Duration(30 * time.Second),
+			MaxGasBumps:            3,
+			GasBumpPercent:         15,
+			NonceGapPolicy:         NonceGapPolicyReuse,
+			RecordWriteRetries:     3,
+			RecordRetryInterval:    Duration(30 * time.Second),
+			MaxAddressesPerIP:      0,
+			TesterAllowlist:        []string{},
+			GasReserve:             0,
+			ReserveInFlight:        true,
+			RPCRetryBudget:         3,
+			RPCRetryBackoff:        Duration(500 * time.Millisecond),
+			Tokens:                 []Token{},
+			MaxClockSkew:           Duration(5 * time.Minute),
 		},
 		Network: Network{
-			Port: "8080",
+			Port:                      "8080",
+			StatsCacheTTL:             Duration(30 * time.Second),
+			ConfigCacheTTL:            Duration(5 * time.Minute),
+			StatusCacheTTL:            Duration(5 * time.Second),
+			EnableExport:              false,
+			AmountFormat:              AmountFormatEther,
+			EnableGzip:                true,
+			GzipMinSize:               1024,
+			ReadTimeout:               Duration(10 * time.Second),
+			ReadHeaderTimeout:         Duration(5 * time.Second),
+			WriteTimeout:              Duration(60 * time.Second),
+			IdleTimeout:               Duration(120 * time.Second),
+			OutboundTimeout:           Duration(10 * time.Second),
+			IPv4Prefix:                32,
+			IPv6Prefix:                64,
+			TrustedProxies:            []string{"127.0.0.1", "::1"},
+			LimiterMetricsWindow:      Duration(10 * time.Minute),
+			LimiterMetricsLogInterval: Duration(time.Minute),
+			LatencyBuckets:            []float64{1, 2, 5, 10, 15, 30, 60},
+			ClaimSLO:                  Duration(15 * time.Second),
+			RateLimit: RateLimit{
+				Global:          200,
+				DirectClaim:     20,
+				TweetClaim:      20,
+				AuthorizedClaim: 20,
+				PreCheck:        100,
+				Read:            100,
+			},
 		},
 		Log: Log{
 			Filename:         "faucet",
@@ -102,7 +524,91 @@ func DefaultConfig() *Config {
 			},
 		},
 		Scrapper: Scrapper{
-			ScrapperAddr: "http://127.0.0.1:5000/tweetCheck",
+			ScrapperAddr:       "http://127.0.0.1:5000/tweetCheck",
+			TweetDomains:       []string{"twitter.com", "x.com"},
+			Retries:            2,
+			CacheTTL:           Duration(10 * time.Minute),
+			Timeout:            Duration(20 * time.Second),
+			MaxURLLength:       512,
+			UnavailablePolicy:  VerifierPolicyFail,
+			DegradedMultiplier: 0.5,
+		},
+		AuthorizedClaim: AuthorizedClaim{
+			Enable:    false,
+			Algorithm: "ES256",
+			KeyPath:   "authorized_claim.pem",
+			MaxTTL:    Duration(10 * time.Minute),
+		},
+		RequestSign: RequestSign{
+			Enable:   false,
+			Required: false,
+			Window:   Duration(5 * time.Minute),
+			ApiKeys:  []ApiKey{},
+		},
+		Campaign: Campaign{
+			Sources:  []string{},
+			Proofs:   []ActionProof{},
+			Holdings: []HoldingPolicy{},
+		},
+		Queue: Queue{
+			Enable:       false,
+			Size:         1000,
+			TicketTTL:    Duration(time.Hour),
+			DrainTimeout: Duration(30 * time.Second),
+		},
+		ClaimEvents: ClaimEvents{
+			Enable:       false,
+			PollInterval: Duration(2 * time.Second),
+			MaxDuration:  Duration(50 * time.Second),
+		},
+		Maintenance: Maintenance{
+			Timezone: "UTC",
+			Windows:  []string{},
+		},
+		Event: Event{
+			Timezone: "UTC",
+		},
+		SignatureClaim: SignatureClaim{
+			Enable: false,
+			Window: Duration(5 * time.Minute),
+		},
+		Admin: Admin{
+			Token:            "",
+			OverviewCacheTTL: Duration(5 * time.Second),
+			MaxAmount:        1000,
+		},
+		Webhook: Webhook{
+			URL:     "",
+			Secret:  "",
+			Timeout: Duration(5 * time.Second),
+		},
+		Email: Email{
+			Port:    587,
+			Subject: "Faucet claim receipt",
+			Timeout: Duration(10 * time.Second),
+		},
+		Audit: Audit{
+			Sink:      "",
+			Filename:  "audit.log",
+			MaxSize:   128,
+			MaxAge:    90,
+			SyslogTag: "faucet",
+		},
+		Risk: Risk{
+			Enable:    false,
+			Threshold: 10,
+		},
+		Referral: Referral{
+			Enable:   false,
+			MaxBonus: 100,
+			Codes:    []ReferralCode{},
+		},
+		Sanction: Sanction{
+			Enable:      false,
+			ResultField: "sanctioned",
+			Timeout:     Duration(5 * time.Second),
+			CacheTTL:    Duration(10 * time.Minute),
+			FailOpen:    false,
 		},
 	}
 
@@ -129,6 +635,9 @@ func LoadConfig(repoRoot string) (*Config, error) {
 			if err := readConfigFromFile(cfgPath, cfg); err != nil {
 				return nil, err
 			}
+			if err := cfg.resolveSecrets(repoRoot); err != nil {
+				return nil, err
+			}
 		}
 
 		return cfg, nil
@@ -21,4 +21,9 @@ const (
 	APIName = "api"
 
 	RootPathEnvVar = "FAUCET_ROOT_PATH"
+
+	// 响应中数量的格式
+	AmountFormatEther   = "ether"
+	AmountFormatDecimal = "decimal"
+	AmountFormatHex     = "hex"
 )
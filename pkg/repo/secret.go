@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const secretFilePrefix = "file:"
+
+// resolveSecret 展开密钥引用：${ENV_VAR} 读取环境变量，file:path 读取文件内容，其余按明文返回。
+// 返回的错误中只包含引用本身，不包含解析出的密钥
+func resolveSecret(repoRoot string, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}"):
+		name := value[2 : len(value)-1]
+		secret, ok := os.LookupEnv(name)
+		if !ok || secret == "" {
+			return "", errors.Errorf("secret env %s is not set", name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, secretFilePrefix):
+		secretPath := strings.TrimPrefix(value, secretFilePrefix)
+		if !filepath.IsAbs(secretPath) {
+			secretPath = filepath.Join(repoRoot, secretPath)
+		}
+		raw, err := os.ReadFile(secretPath)
+		if err != nil {
+			return "", errors.Wrapf(err, "read secret file %s failed", secretPath)
+		}
+		secret := strings.TrimSpace(string(raw))
+		if secret == "" {
+			return "", errors.Errorf("secret file %s is empty", secretPath)
+		}
+		return secret, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveSecrets 在加载配置时展开所有密钥字段
+func (c *Config) resolveSecrets(repoRoot string) error {
+	var err error
+	if c.Axiom.AxiomKey, err = resolveSecret(repoRoot, c.Axiom.AxiomKey); err != nil {
+		return errors.Wrap(err, "resolve axiom.axiom_key failed")
+	}
+	if c.Admin.Token, err = resolveSecret(repoRoot, c.Admin.Token); err != nil {
+		return errors.Wrap(err, "resolve admin.token failed")
+	}
+	if c.Webhook.Secret, err = resolveSecret(repoRoot, c.Webhook.Secret); err != nil {
+		return errors.Wrap(err, "resolve webhook.secret failed")
+	}
+	if c.Email.Password, err = resolveSecret(repoRoot, c.Email.Password); err != nil {
+		return errors.Wrap(err, "resolve email.password failed")
+	}
+	if c.Sanction.APIKey, err = resolveSecret(repoRoot, c.Sanction.APIKey); err != nil {
+		return errors.Wrap(err, "resolve sanction.api_key failed")
+	}
+	for i := range c.RequestSign.ApiKeys {
+		if c.RequestSign.ApiKeys[i].Secret, err = resolveSecret(repoRoot, c.RequestSign.ApiKeys[i].Secret); err != nil {
+			return errors.Wrapf(err, "resolve secret of api key %s failed", c.RequestSign.ApiKeys[i].ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "token"), []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	abs := filepath.Join(t.TempDir(), "abs-token")
+	if err := os.WriteFile(abs, []byte("abs-secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAUCET_TEST_SECRET", "env-secret")
+
+	for value, want := range map[string]string{
+		"plain":                 "plain",
+		"":                      "",
+		"${FAUCET_TEST_SECRET}": "env-secret",
+		"file:token":            "file-secret",
+		"file:" + abs:           "abs-secret",
+	} {
+		got, err := resolveSecret(root, value)
+		if err != nil {
+			t.Fatalf("%q: %v", value, err)
+		}
+		if got != want {
+			t.Fatalf("%q: expect %q, got %q", value, want, got)
+		}
+	}
+}
+
+func TestResolveSecretRejectsMissingReference(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "empty"), []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAUCET_TEST_EMPTY", "")
+
+	for _, value := range []string{"${FAUCET_TEST_UNSET}", "${FAUCET_TEST_EMPTY}", "file:missing", "file:empty"} {
+		if _, err := resolveSecret(root, value); err == nil {
+			t.Fatalf("%q should fail to resolve", value)
+		}
+	}
+}
+
+func TestLoadConfigResolvesSecrets(t *testing.T) {
+	root := t.TempDir()
+	content := "[axiom]\naxiom_key = \"${FAUCET_TEST_KEY}\"\n\n[admin]\ntoken = \"file:admin.token\"\n"
+	if err := os.WriteFile(filepath.Join(root, CfgFileName), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "admin.token"), []byte("admin-secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAUCET_TEST_KEY", "key-secret")
+
+	cfg, err := LoadConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Axiom.AxiomKey != "key-secret" || cfg.Admin.Token != "admin-secret" {
+		t.Fatalf("secrets should be resolved, got key %q token %q", cfg.Axiom.AxiomKey, cfg.Admin.Token)
+	}
+}
+
+// 解析失败的错误中不包含已读取的其他密钥
+func TestLoadConfigSecretErrorOmitsValues(t *testing.T) {
+	root := t.TempDir()
+	content := "[axiom]\naxiom_key = \"${FAUCET_TEST_KEY}\"\n\n[admin]\ntoken = \"${FAUCET_TEST_UNSET}\"\n"
+	if err := os.WriteFile(filepath.Join(root, CfgFileName), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAUCET_TEST_KEY", "key-secret")
+
+	_, err := LoadConfig(root)
+	if err == nil {
+		t.Fatal("unset secret env should fail to load")
+	}
+	if strings.Contains(err.Error(), "key-secret") {
+		t.Fatalf("error should not contain secrets: %v", err)
+	}
+}